@@ -0,0 +1,322 @@
+package vlt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AttachmentInfo describes a non-markdown file in the vault.
+type AttachmentInfo struct {
+	Path string `json:"path"` // vault-relative path
+	Size int64  `json:"size"` // size in bytes
+}
+
+// loadAttachmentFolder reads Obsidian's attachmentFolderPath setting from
+// .obsidian/app.json. Returns "" when unset, which Obsidian treats as the
+// vault root. The raw value is returned: "/" means vault root, "./" means
+// the same folder as the note, "./sub" a subfolder next to the note, and
+// anything else a fixed vault-relative folder.
+func loadAttachmentFolder(vaultDir string) string {
+	data, err := os.ReadFile(filepath.Join(vaultDir, ".obsidian", "app.json"))
+	if err != nil {
+		return ""
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ""
+	}
+	folder, _ := raw["attachmentFolderPath"].(string)
+	return folder
+}
+
+// fixedAttachmentFolder returns the vault-relative attachment folder when
+// attachmentFolderPath names a single fixed folder. Returns "" for the vault
+// root and for note-relative settings ("./", "./sub"), which have no single
+// location.
+func fixedAttachmentFolder(setting string) string {
+	if setting == "" || setting == "/" || strings.HasPrefix(setting, "./") || setting == "." {
+		return ""
+	}
+	return strings.Trim(setting, "/")
+}
+
+// hasFileExtension reports whether a link target carries an explicit file
+// extension (e.g., "diagram.png", "spec.pdf", "Note.md").
+func hasFileExtension(title string) bool {
+	ext := filepath.Ext(title)
+	return len(ext) > 1 && !strings.ContainsAny(ext, " /")
+}
+
+// resolveAttachment finds a file referenced by its full name (with extension),
+// as used by embeds like ![[diagram.png]] or path-qualified links like
+// ![[assets/diagram.png]]. A path-qualified name only resolves to that
+// vault-relative path. A bare name is looked up in the configured attachment
+// folder before falling back to a vault-wide filename match. Matching is
+// case-insensitive, like Obsidian's. Skips hidden dirs and .trash.
+func resolveAttachment(vaultDir, name string) (string, bool) {
+	qualified := strings.Contains(name, "/")
+	if qualified {
+		if p, err := safePath(vaultDir, name); err == nil {
+			if info, err := os.Stat(p); err == nil && !info.IsDir() {
+				return p, true
+			}
+		}
+	}
+
+	base := filepath.Base(name)
+	if !qualified {
+		if folder := fixedAttachmentFolder(loadAttachmentFolder(vaultDir)); folder != "" {
+			if p, err := safePath(vaultDir, filepath.Join(folder, base)); err == nil {
+				if info, err := os.Stat(p); err == nil && !info.IsDir() {
+					return p, true
+				}
+			}
+		}
+	}
+
+	var found string
+	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipHiddenDir(path, d, vaultDir) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.EqualFold(d.Name(), base) {
+			return nil
+		}
+		if qualified {
+			rel, _ := filepath.Rel(vaultDir, path)
+			if !strings.EqualFold(filepath.ToSlash(rel), filepath.ToSlash(filepath.Clean(name))) {
+				return nil
+			}
+		}
+		found = path
+		return filepath.SkipAll
+	})
+
+	return found, found != ""
+}
+
+// mdAttachmentLinkPattern matches markdown-style links and images to any file
+// with an extension: [text](path.ext), ![alt](path.ext), optionally with a
+// #fragment. Used when moving attachments, whose links are not limited to .md.
+var mdAttachmentLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+\.\w+(?:#[^)]*)?)\)`)
+
+// updateVaultAttachmentLinks rewrites wikilinks and embeds referencing a
+// moved attachment. References by bare filename (![[old.png]]) are updated
+// when the filename changes; path-qualified references (![[assets/old.png]])
+// are updated whenever the vault-relative path changes.
+// oldRelPath and newRelPath are vault-relative paths.
+// Returns the number of files modified.
+// If reg is non-nil, updated files are registered for integrity tracking.
+func updateVaultAttachmentLinks(vaultDir, oldRelPath, newRelPath string, reg *Registry) (int, error) {
+	edits, err := attachmentLinkEdits(vaultDir, oldRelPath, newRelPath)
+	if err != nil {
+		return 0, err
+	}
+	return applyEdits(vaultDir, edits, reg)
+}
+
+// attachmentLinkEdits plans the edits of updateVaultAttachmentLinks without
+// writing.
+func attachmentLinkEdits(vaultDir, oldRelPath, newRelPath string) ([]fileEdit, error) {
+	oldName := filepath.Base(oldRelPath)
+	newName := filepath.Base(newRelPath)
+	oldSlash := filepath.ToSlash(filepath.Clean(oldRelPath))
+	newSlash := filepath.ToSlash(filepath.Clean(newRelPath))
+
+	return noteRewriteEdits(vaultDir, func(text string) string {
+		if oldName != newName {
+			text = ReplaceWikilinks(text, oldName, newName)
+		}
+		if oldSlash != newSlash && strings.Contains(oldSlash, "/") {
+			text = ReplaceWikilinks(text, oldSlash, newSlash)
+		}
+		return text
+	})
+}
+
+// isAttachmentFile reports whether a vault file counts as an attachment:
+// anything that is not a markdown note or a canvas.
+func isAttachmentFile(name string) bool {
+	return !isLinkSource(name)
+}
+
+// attachmentRefs holds every way the vault references attachments. Keys are
+// lowercased so matching is case-insensitive like Obsidian's resolver.
+type attachmentRefs struct {
+	names map[string]bool // bare filenames from wikilinks/embeds
+	paths map[string]bool // vault-relative slash paths from links
+}
+
+// referenced reports whether an attachment at relPath is referenced.
+func (r attachmentRefs) referenced(relPath string) bool {
+	slash := strings.ToLower(filepath.ToSlash(relPath))
+	return r.paths[slash] || r.names[strings.ToLower(filepath.Base(relPath))]
+}
+
+// collectAttachmentRefs scans all notes for wikilinks, embeds and markdown
+// links that name a file with an extension, and all canvases for file nodes
+// and text-node wikilinks. Markdown link targets are resolved both relative
+// to the referencing note and to the vault root, matching Obsidian's lenient
+// resolution. Inert zones are masked first.
+func collectAttachmentRefs(vaultDir string) attachmentRefs {
+	refs := attachmentRefs{
+		names: make(map[string]bool),
+		paths: make(map[string]bool),
+	}
+
+	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipHiddenDir(path, d, vaultDir) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !isLinkSource(d.Name()) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		if isCanvasFile(d.Name()) {
+			if c, err := parseCanvas(data); err == nil {
+				for _, n := range c.Nodes {
+					switch n.Type {
+					case "file":
+						if n.File != "" {
+							refs.paths[strings.ToLower(filepath.ToSlash(filepath.Clean(n.File)))] = true
+						}
+					case "text":
+						for _, link := range ParseWikilinks(n.Text) {
+							if hasFileExtension(link.Title) {
+								refs.names[strings.ToLower(filepath.Base(link.Title))] = true
+							}
+						}
+					}
+				}
+			}
+			return nil
+		}
+
+		text := string(data)
+		for _, link := range ParseWikilinks(text) {
+			if !hasFileExtension(link.Title) {
+				continue
+			}
+			refs.names[strings.ToLower(filepath.Base(link.Title))] = true
+			refs.paths[strings.ToLower(filepath.ToSlash(filepath.Clean(link.Title)))] = true
+		}
+
+		noteDir, _ := filepath.Rel(vaultDir, filepath.Dir(path))
+		for _, m := range mdAttachmentLinkPattern.FindAllStringSubmatch(MaskInertContent(text), -1) {
+			target := m[2]
+			if idx := strings.Index(target, "#"); idx >= 0 {
+				target = target[:idx]
+			}
+			if strings.Contains(target, "://") || filepath.IsAbs(target) {
+				continue
+			}
+			if unescaped, err := url.PathUnescape(target); err == nil {
+				target = unescaped
+			}
+			for _, candidate := range []string{filepath.Join(noteDir, target), target} {
+				refs.paths[strings.ToLower(filepath.ToSlash(filepath.Clean(candidate)))] = true
+			}
+		}
+		return nil
+	})
+
+	return refs
+}
+
+// UnusedAttachments lists attachments that no note references by wikilink,
+// embed or markdown link. When attachmentFolderPath names a fixed folder only
+// that folder is scanned; otherwise every non-markdown file in the vault is
+// considered. Results are sorted by path.
+func (v *Vault) UnusedAttachments() ([]AttachmentInfo, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return unusedAttachments(v.dir)
+}
+
+// unusedAttachments implements UnusedAttachments without locking.
+func unusedAttachments(vaultDir string) ([]AttachmentInfo, error) {
+	scanRoot := vaultDir
+	if folder := fixedAttachmentFolder(loadAttachmentFolder(vaultDir)); folder != "" {
+		root, err := safePath(vaultDir, folder)
+		if err != nil {
+			return nil, fmt.Errorf("attachment folder: %w", err)
+		}
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			return nil, nil
+		}
+		scanRoot = root
+	}
+
+	refs := collectAttachmentRefs(vaultDir)
+
+	var unused []AttachmentInfo
+	err := filepath.WalkDir(scanRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipHiddenDir(path, d, scanRoot) {
+			return filepath.SkipDir
+		}
+		// Dotfiles (.vlt.lock, .DS_Store) are never attachments.
+		if d.IsDir() || !isAttachmentFile(d.Name()) || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(vaultDir, path)
+		if refs.referenced(relPath) {
+			return nil
+		}
+
+		info, infoErr := d.Info()
+		if infoErr != nil {
+			return nil
+		}
+		unused = append(unused, AttachmentInfo{Path: relPath, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(unused, func(i, j int) bool { return unused[i].Path < unused[j].Path })
+	return unused, nil
+}
+
+// CleanAttachments moves every unused attachment (see UnusedAttachments) to
+// .trash/ using the same mechanism as Delete. Returns the trashed files.
+func (v *Vault) CleanAttachments() ([]AttachmentInfo, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	unused, err := unusedAttachments(v.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var trashed []AttachmentInfo
+	for _, a := range unused {
+		if _, err := discardFile(v.dir, filepath.Join(v.dir, a.Path)); err != nil {
+			return trashed, fmt.Errorf("trash %s: %w", a.Path, err)
+		}
+		trashed = append(trashed, a)
+	}
+	return trashed, nil
+}
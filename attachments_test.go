@@ -0,0 +1,344 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadAttachmentFolder(t *testing.T) {
+	vaultDir := t.TempDir()
+
+	if got := loadAttachmentFolder(vaultDir); got != "" {
+		t.Errorf("no app.json: got %q, want empty", got)
+	}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(
+		filepath.Join(vaultDir, ".obsidian", "app.json"),
+		[]byte(`{"attachmentFolderPath": "assets/img"}`),
+		0644,
+	)
+	if got := loadAttachmentFolder(vaultDir); got != "assets/img" {
+		t.Errorf("got %q, want %q", got, "assets/img")
+	}
+}
+
+func TestFixedAttachmentFolder(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    string
+	}{
+		{"", ""},
+		{"/", ""},
+		{"./", ""},
+		{"./attachments", ""},
+		{"assets", "assets"},
+		{"assets/img/", "assets/img"},
+	}
+	for _, tt := range tests {
+		if got := fixedAttachmentFolder(tt.setting); got != tt.want {
+			t.Errorf("fixedAttachmentFolder(%q) = %q, want %q", tt.setting, got, tt.want)
+		}
+	}
+}
+
+func TestHasFileExtension(t *testing.T) {
+	tests := []struct {
+		title string
+		want  bool
+	}{
+		{"diagram.png", true},
+		{"spec.pdf", true},
+		{"assets/diagram.png", true},
+		{"Board.canvas", true},
+		{"Plain Note", false},
+		{"v1.2 Release", false},
+		{"trailing.", false},
+	}
+	for _, tt := range tests {
+		if got := hasFileExtension(tt.title); got != tt.want {
+			t.Errorf("hasFileExtension(%q) = %v, want %v", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestResolveNote_Attachment(t *testing.T) {
+	vaultDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(vaultDir, "assets"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "other"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "assets", "diagram.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "other", "spec.pdf"), []byte("pdf"), 0644)
+
+	tests := []struct {
+		title   string
+		wantRel string
+	}{
+		{"diagram.png", "assets/diagram.png"},
+		{"assets/diagram.png", "assets/diagram.png"},
+		{"spec.pdf", "other/spec.pdf"},
+	}
+	for _, tt := range tests {
+		path, err := resolveNote(vaultDir, tt.title)
+		if err != nil {
+			t.Fatalf("resolveNote(%q): %v", tt.title, err)
+		}
+		rel, _ := filepath.Rel(vaultDir, path)
+		if rel != tt.wantRel {
+			t.Errorf("resolveNote(%q) = %q, want %q", tt.title, rel, tt.wantRel)
+		}
+	}
+
+	if _, err := resolveNote(vaultDir, "missing.png"); err == nil {
+		t.Error("expected error for missing attachment")
+	}
+}
+
+func TestResolveNote_AttachmentFolderPreferred(t *testing.T) {
+	vaultDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "aaa"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "zz-attachments"), 0755)
+	os.WriteFile(
+		filepath.Join(vaultDir, ".obsidian", "app.json"),
+		[]byte(`{"attachmentFolderPath": "zz-attachments"}`),
+		0644,
+	)
+	// Same filename in two places; the walk would find aaa/ first.
+	os.WriteFile(filepath.Join(vaultDir, "aaa", "photo.jpg"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "zz-attachments", "photo.jpg"), []byte("b"), 0644)
+
+	path, err := resolveNote(vaultDir, "photo.jpg")
+	if err != nil {
+		t.Fatalf("resolveNote: %v", err)
+	}
+	rel, _ := filepath.Rel(vaultDir, path)
+	if rel != "zz-attachments/photo.jpg" {
+		t.Errorf("got %q, want zz-attachments/photo.jpg", rel)
+	}
+}
+
+func TestUnresolved_AttachmentEmbeds(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, "assets"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "assets", "diagram.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "spec.pdf"), []byte("pdf"), 0644)
+	os.WriteFile(
+		filepath.Join(vaultDir, "Note.md"),
+		[]byte("![[diagram.png]]\n![[assets/diagram.png]]\n![[spec.pdf#page=2]]\n![[gone.png]]\n"),
+		0644,
+	)
+
+	results, err := v.Unresolved()
+	if err != nil {
+		t.Fatalf("unresolved: %v", err)
+	}
+	if len(results) != 1 || results[0].Target != "gone.png" {
+		t.Errorf("expected only gone.png unresolved, got %+v", results)
+	}
+}
+
+func TestUnresolved_AgreesWithResolver(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, "other"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "other", "Pic.PNG"), []byte("png"), 0644)
+	os.WriteFile(
+		filepath.Join(vaultDir, "Note.md"),
+		[]byte("![[pic.png]]\n![[other/pic.png]]\n![[missing/dir/pic.png]]\n"),
+		0644,
+	)
+
+	results, err := v.Unresolved()
+	if err != nil {
+		t.Fatalf("unresolved: %v", err)
+	}
+	if len(results) != 1 || results[0].Target != "missing/dir/pic.png" {
+		t.Errorf("expected only missing/dir/pic.png unresolved, got %+v", results)
+	}
+	for target, want := range map[string]bool{"pic.png": true, "other/pic.png": true, "missing/dir/pic.png": false} {
+		if _, err := resolveNote(vaultDir, target); (err == nil) != want {
+			t.Errorf("resolveNote(%q) error = %v, want resolved=%v", target, err, want)
+		}
+	}
+}
+
+func TestLinks_AttachmentResolves(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "diagram.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("![[diagram.png]]\n"), 0644)
+
+	links, err := v.Links("Note")
+	if err != nil {
+		t.Fatalf("links: %v", err)
+	}
+	if len(links) != 1 || links[0].Broken || links[0].Path != "diagram.png" {
+		t.Errorf("unexpected links: %+v", links)
+	}
+}
+
+func TestReadFollow_SkipsAttachments(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "diagram.png"), []byte("\x89PNG"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("# Other\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("![[diagram.png]] [[Other]]\n"), 0644)
+
+	_, linked, err := v.ReadFollow("Note", "")
+	if err != nil {
+		t.Fatalf("read follow: %v", err)
+	}
+	if len(linked) != 1 || linked[0].Title != "Other" {
+		t.Errorf("expected only Other to be followed, got %+v", linked)
+	}
+}
+
+func TestMove_AttachmentRewritesEmbeds(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, "assets"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "assets", "diagram.png"), []byte("png"), 0644)
+	os.WriteFile(
+		filepath.Join(vaultDir, "Note.md"),
+		[]byte("![[diagram.png]]\n![[assets/diagram.png|300]]\n![alt](assets/diagram.png)\n[[diagram]]\n"),
+		0644,
+	)
+
+	res, err := v.Move("assets/diagram.png", "img/architecture.png")
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if res.WikilinksUpdated != 1 {
+		t.Errorf("WikilinksUpdated = %d, want 1", res.WikilinksUpdated)
+	}
+	if res.MdLinksUpdated != 1 {
+		t.Errorf("MdLinksUpdated = %d, want 1", res.MdLinksUpdated)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Note.md"))
+	got := string(data)
+	want := "![[architecture.png]]\n![[img/architecture.png|300]]\n![alt](img/architecture.png)\n[[diagram]]\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := os.Stat(filepath.Join(vaultDir, "img", "architecture.png")); err != nil {
+		t.Errorf("attachment not moved: %v", err)
+	}
+}
+
+func TestMove_AttachmentFolderOnly(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "spec.pdf"), []byte("pdf"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("![[spec.pdf]]\n"), 0644)
+
+	if _, err := v.Move("spec.pdf", "docs/spec.pdf"); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Note.md"))
+	if !strings.Contains(string(data), "![[spec.pdf]]") {
+		t.Errorf("bare embed should be unchanged on folder-only move, got %q", string(data))
+	}
+}
+
+func TestUnusedAttachments(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, "assets"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "notes"), 0755)
+	for _, name := range []string{"embedded.png", "pathed.png", "mdlink.png", "relative.png", "spaced name.png", "orphan.png", "coded.png"} {
+		os.WriteFile(filepath.Join(vaultDir, "assets", name), []byte("12345"), 0644)
+	}
+	os.WriteFile(filepath.Join(vaultDir, ".vlt.lock"), nil, 0644)
+	os.WriteFile(
+		filepath.Join(vaultDir, "notes", "Note.md"),
+		[]byte("![[embedded.png]]\n"+
+			"![[assets/pathed.png|200]]\n"+
+			"![alt](assets/mdlink.png)\n"+
+			"![rel](../assets/relative.png)\n"+
+			"![sp](assets/spaced%20name.png)\n"+
+			"```\n![[coded.png]]\n```\n"),
+		0644,
+	)
+
+	unused, err := v.UnusedAttachments()
+	if err != nil {
+		t.Fatalf("unused: %v", err)
+	}
+
+	var got []string
+	for _, a := range unused {
+		got = append(got, a.Path)
+		if a.Size != 5 {
+			t.Errorf("%s: size = %d, want 5", a.Path, a.Size)
+		}
+	}
+	want := []string{"assets/coded.png", "assets/orphan.png"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestUnusedAttachments_FixedFolderOnly(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "attachments"), 0755)
+	os.WriteFile(
+		filepath.Join(vaultDir, ".obsidian", "app.json"),
+		[]byte(`{"attachmentFolderPath": "attachments"}`),
+		0644,
+	)
+	os.WriteFile(filepath.Join(vaultDir, "attachments", "unused.pdf"), []byte("pdf"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "outside.pdf"), []byte("pdf"), 0644)
+
+	unused, err := v.UnusedAttachments()
+	if err != nil {
+		t.Fatalf("unused: %v", err)
+	}
+	if len(unused) != 1 || unused[0].Path != "attachments/unused.pdf" {
+		t.Errorf("expected only attachments/unused.pdf, got %+v", unused)
+	}
+}
+
+func TestCleanAttachments(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "used.png"), []byte("used"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "stale.png"), []byte("stale!"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("![[used.png]]\n"), 0644)
+
+	trashed, err := v.CleanAttachments()
+	if err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].Path != "stale.png" || trashed[0].Size != 6 {
+		t.Fatalf("unexpected trashed list: %+v", trashed)
+	}
+
+	if _, err := os.Stat(filepath.Join(vaultDir, "stale.png")); !os.IsNotExist(err) {
+		t.Error("stale.png should be gone from the vault root")
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, ".trash", "stale.png")); err != nil {
+		t.Errorf("stale.png not found in .trash: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "used.png")); err != nil {
+		t.Errorf("used.png should be kept: %v", err)
+	}
+}
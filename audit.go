@@ -0,0 +1,302 @@
+package vlt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// auditLogName is the audit log file inside the registry directory.
+const auditLogName = "audit.log"
+
+// AuditRecord is one entry of the integrity audit log. Records form a hash
+// chain: Prev is the Hash of the previous record and Hash covers every
+// other field, so editing, reordering or deleting a record breaks the chain.
+// The chain alone cannot stop someone from rewriting the whole log; with
+// VLT_AUDIT_KEY set each record is also signed (Sig, an HMAC-SHA256 of Hash),
+// which cannot be recomputed without the key.
+type AuditRecord struct {
+	Seq     int    `json:"seq"`
+	Ts      string `json:"ts"`
+	Path    string `json:"path"`
+	OldHash string `json:"old_hash"`
+	NewHash string `json:"new_hash"`
+	Command string `json:"command,omitempty"`
+	Actor   string `json:"actor,omitempty"`
+	Prev    string `json:"prev"`
+	Hash    string `json:"hash"`
+	Sig     string `json:"sig,omitempty"`
+}
+
+// AuditReport is the result of verifying the audit log against itself and
+// the registry. The log is intact when Problems is empty.
+type AuditReport struct {
+	Records  int      `json:"records"`
+	Head     string   `json:"head"`
+	Signed   bool     `json:"signed"` // signatures were checked with VLT_AUDIT_KEY
+	Problems []string `json:"problems"`
+}
+
+// recordHash computes the chained hash of a record (its Hash and Sig fields
+// ignored).
+func recordHash(rec AuditRecord) string {
+	rec.Hash, rec.Sig = "", ""
+	data, _ := json.Marshal(rec)
+	return contentHash(data)
+}
+
+// auditKey returns the audit log signing key (VLT_AUDIT_KEY), or "" when
+// records are not signed.
+func auditKey() string {
+	return os.Getenv("VLT_AUDIT_KEY")
+}
+
+// recordSig signs a record hash with key.
+func recordSig(key, hash string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// auditActor returns the actor recorded in the audit log when none is set:
+// VLT_ACTOR, else the OS user name.
+func auditActor() string {
+	if actor := os.Getenv("VLT_ACTOR"); actor != "" {
+		return actor
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return os.Getenv("USERNAME")
+}
+
+// SetAuditContext sets the command and actor recorded in the audit log for
+// writes made through this vault. An empty actor uses VLT_ACTOR or the OS
+// user name.
+func (v *Vault) SetAuditContext(command, actor string) {
+	v.registry.mu.Lock()
+	defer v.registry.mu.Unlock()
+	if actor == "" {
+		actor = auditActor()
+	}
+	v.registry.command = command
+	v.registry.actor = actor
+}
+
+// lastAuditRecord returns the final record of the audit log, or a zero
+// record when the log is missing or empty.
+func lastAuditRecord(path string) (AuditRecord, error) {
+	var last AuditRecord
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return last, nil
+	}
+	if err != nil {
+		return last, err
+	}
+	defer f.Close()
+
+	// Records are small; the tail of the file holds the last one.
+	info, err := f.Stat()
+	if err != nil {
+		return last, err
+	}
+	const tail = 16 << 10
+	offset := info.Size() - tail
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil && err != io.EOF {
+		return last, err
+	}
+	lines := bytes.Split(bytes.TrimRight(buf, "\n"), []byte("\n"))
+	if line := lines[len(lines)-1]; len(line) > 0 {
+		if err := json.Unmarshal(line, &last); err != nil {
+			return last, fmt.Errorf("audit log %s: last record is unreadable: %w", path, err)
+		}
+	}
+	return last, nil
+}
+
+// appendAudit chains a record for a change of rel from oldHash to newHash
+// onto the audit log. Caller must hold r.mu.
+func (r *Registry) appendAudit(rel, oldHash, newHash string) error {
+	if err := os.MkdirAll(r.dir, 0700); err != nil {
+		return err
+	}
+	logPath := filepath.Join(r.dir, auditLogName)
+	last, err := lastAuditRecord(logPath)
+	if err != nil {
+		return err
+	}
+
+	rec := AuditRecord{
+		Seq:     last.Seq + 1,
+		Ts:      time.Now().UTC().Format(time.RFC3339Nano),
+		Path:    filepath.ToSlash(rel),
+		OldHash: oldHash,
+		NewHash: newHash,
+		Command: r.command,
+		Actor:   r.actor,
+		Prev:    last.Hash,
+	}
+	if rec.Actor == "" {
+		rec.Actor = auditActor()
+	}
+	rec.Hash = recordHash(rec)
+	if key := auditKey(); key != "" {
+		rec.Sig = recordSig(key, rec.Hash)
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// auditLatest returns the latest hash the audit log recorded for each path
+// (slash-separated). Caller must hold r.mu.
+func (r *Registry) auditLatest() (map[string]string, error) {
+	latest := make(map[string]string)
+	err := readAuditLog(filepath.Join(r.dir, auditLogName), func(_ int, rec AuditRecord, err error) {
+		if err == nil {
+			latest[rec.Path] = rec.NewHash
+		}
+	})
+	return latest, err
+}
+
+// AuditLog returns every record of the audit log, oldest first. Records
+// that cannot be parsed are skipped; IntegrityAudit reports them.
+func (v *Vault) AuditLog() ([]AuditRecord, error) {
+	v.registry.mu.Lock()
+	defer v.registry.mu.Unlock()
+
+	var records []AuditRecord
+	err := readAuditLog(filepath.Join(v.registry.dir, auditLogName), func(_ int, rec AuditRecord, err error) {
+		if err == nil {
+			records = append(records, rec)
+		}
+	})
+	return records, err
+}
+
+// readAuditLog calls fn for each line of the audit log with its 1-based
+// line number and the parsed record (or the parse error).
+func readAuditLog(path string, fn func(line int, rec AuditRecord, err error)) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	n := 0
+	for scanner.Scan() {
+		n++
+		var rec AuditRecord
+		err := json.Unmarshal(scanner.Bytes(), &rec)
+		fn(n, rec, err)
+	}
+	return scanner.Err()
+}
+
+// IntegrityAudit verifies the audit log's hash chain and checks that the
+// registry agrees with it: every registered hash must be the latest hash
+// the log recorded for that path. A corrupted registry is a problem too.
+// With VLT_AUDIT_KEY set, every record must carry a valid signature.
+func (v *Vault) IntegrityAudit() (AuditReport, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	r := v.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := auditKey()
+	report := AuditReport{Signed: key != "", Problems: []string{}}
+	if r.loadErr != nil {
+		report.Problems = append(report.Problems, r.loadErr.Error())
+	}
+
+	latest := make(map[string]string)
+	prev := ""
+	err := readAuditLog(filepath.Join(r.dir, auditLogName), func(line int, rec AuditRecord, err error) {
+		report.Records++
+		if err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("audit log line %d: unreadable record: %v", line, err))
+			return
+		}
+		if rec.Seq != line {
+			report.Problems = append(report.Problems, fmt.Sprintf("audit log line %d: sequence number is %d (records missing or reordered)", line, rec.Seq))
+		}
+		if rec.Prev != prev {
+			report.Problems = append(report.Problems, fmt.Sprintf("audit log line %d: chain broken (previous hash does not match line %d)", line, line-1))
+		}
+		if recordHash(rec) != rec.Hash {
+			report.Problems = append(report.Problems, fmt.Sprintf("audit log line %d: record hash mismatch (record was modified)", line))
+		}
+		switch {
+		case key == "":
+		case rec.Sig == "":
+			report.Problems = append(report.Problems, fmt.Sprintf("audit log line %d: record is not signed", line))
+		case !hmac.Equal([]byte(rec.Sig), []byte(recordSig(key, rec.Hash))):
+			report.Problems = append(report.Problems, fmt.Sprintf("audit log line %d: signature mismatch (record forged, or signed with another VLT_AUDIT_KEY)", line))
+		}
+		prev = rec.Hash
+		latest[rec.Path] = rec.NewHash
+	})
+	if err != nil {
+		return report, err
+	}
+	report.Head = prev
+
+	if r.loadErr != nil {
+		return report, nil
+	}
+	var paths []string
+	for rel := range r.entries {
+		paths = append(paths, rel)
+	}
+	for rel, hash := range latest {
+		if _, ok := r.entries[filepath.FromSlash(rel)]; !ok && hash != "" {
+			paths = append(paths, filepath.FromSlash(rel))
+		}
+	}
+	sort.Strings(paths)
+	for _, rel := range paths {
+		entry, registered := r.entries[rel]
+		logged, ok := latest[filepath.ToSlash(rel)]
+		switch {
+		case !registered:
+			report.Problems = append(report.Problems, fmt.Sprintf("%s: in the audit log but missing from the registry", rel))
+		case !ok:
+			report.Problems = append(report.Problems, fmt.Sprintf("%s: registered but never recorded in the audit log", rel))
+		case logged != entry.Hash:
+			report.Problems = append(report.Problems, fmt.Sprintf("%s: registry hash does not match the audit log", rel))
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,219 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newAuditVault(t *testing.T) *Vault {
+	t.Helper()
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	v.SetAuditContext("write", "agent-7")
+	return v
+}
+
+func TestAuditLog_Chain(t *testing.T) {
+	v := newAuditVault(t)
+	path := filepath.Join(v.dir, "note.md")
+
+	v.registry.register(v.dir, path, []byte("one\n"))
+	v.registry.register(v.dir, path, []byte("one\n")) // unchanged: not logged
+	v.registry.register(v.dir, path, []byte("two\n"))
+	v.registry.deregister(v.dir, path)
+
+	records, err := v.AuditLog()
+	if err != nil {
+		t.Fatalf("AuditLog: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	first, second, third := records[0], records[1], records[2]
+	if first.OldHash != "" || first.NewHash != contentHash([]byte("one\n")) || first.Prev != "" {
+		t.Errorf("first record = %+v", first)
+	}
+	if second.OldHash != first.NewHash || second.Prev != first.Hash || second.Seq != 2 {
+		t.Errorf("second record not chained to the first: %+v", second)
+	}
+	if third.NewHash != "" || third.Path != "note.md" {
+		t.Errorf("deregister record = %+v", third)
+	}
+	if first.Command != "write" || first.Actor != "agent-7" {
+		t.Errorf("command/actor = %q/%q, want write/agent-7", first.Command, first.Actor)
+	}
+
+	report, err := v.IntegrityAudit()
+	if err != nil {
+		t.Fatalf("IntegrityAudit: %v", err)
+	}
+	if len(report.Problems) != 0 || report.Records != 3 || report.Head != third.Hash {
+		t.Errorf("report = %+v, want intact log of 3 records", report)
+	}
+}
+
+func TestAuditLog_Tampering(t *testing.T) {
+	v := newAuditVault(t)
+	a := filepath.Join(v.dir, "a.md")
+	b := filepath.Join(v.dir, "b.md")
+	v.registry.register(v.dir, a, []byte("a1"))
+	v.registry.register(v.dir, b, []byte("b1"))
+	v.registry.register(v.dir, a, []byte("a2"))
+
+	logPath := filepath.Join(v.registry.dir, auditLogName)
+	data, _ := os.ReadFile(logPath)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	// Rewrite history: pretend b.md was registered with other content.
+	tampered := strings.Replace(lines[1], contentHash([]byte("b1")), contentHash([]byte("evil")), 1)
+	os.WriteFile(logPath, []byte(lines[0]+"\n"+tampered+"\n"+lines[2]+"\n"), 0600)
+
+	report, err := v.IntegrityAudit()
+	if err != nil {
+		t.Fatalf("IntegrityAudit: %v", err)
+	}
+	joined := strings.Join(report.Problems, "\n")
+	for _, want := range []string{
+		"line 2: record hash mismatch",
+		"b.md: registry hash does not match the audit log",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("problems missing %q:\n%s", want, joined)
+		}
+	}
+
+	// Dropping a record breaks the chain.
+	os.WriteFile(logPath, []byte(lines[0]+"\n"+lines[2]+"\n"), 0600)
+	report, _ = v.IntegrityAudit()
+	joined = strings.Join(report.Problems, "\n")
+	if !strings.Contains(joined, "line 2: chain broken") || !strings.Contains(joined, "sequence number is 3") {
+		t.Errorf("expected chain and sequence problems, got:\n%s", joined)
+	}
+}
+
+func TestRegistryCorrupted(t *testing.T) {
+	v := newAuditVault(t)
+	path := filepath.Join(v.dir, "note.md")
+	os.WriteFile(path, []byte("# Note\n"), 0644)
+	v.registry.register(v.dir, path, []byte("# Note\n"))
+
+	regPath := filepath.Join(v.registry.dir, "registry.json")
+	os.WriteFile(regPath, []byte("{not json"), 0600)
+
+	v = &Vault{dir: v.dir, registry: openRegistry(v.dir)}
+	if err := v.RegistryError(); err == nil || !strings.Contains(err.Error(), "corrupted") {
+		t.Fatalf("RegistryError = %v, want corruption error", err)
+	}
+
+	// Writes must not silently replace the corrupted registry.
+	v.registry.register(v.dir, path, []byte("# Changed\n"))
+	if data, _ := os.ReadFile(regPath); string(data) != "{not json" {
+		t.Errorf("corrupted registry was overwritten: %q", data)
+	}
+	report, err := v.IntegrityAudit()
+	if err != nil {
+		t.Fatalf("IntegrityAudit: %v", err)
+	}
+	if len(report.Problems) == 0 || !strings.Contains(report.Problems[0], "corrupted") {
+		t.Errorf("audit problems = %v, want corrupted registry", report.Problems)
+	}
+
+	// Baseline moves the corrupted file aside and rebuilds.
+	os.WriteFile(path, []byte("# Changed\n"), 0644)
+	if err := v.IntegrityBaseline(false); err != nil {
+		t.Fatalf("IntegrityBaseline: %v", err)
+	}
+	if _, err := os.Stat(regPath + ".corrupt"); err != nil {
+		t.Errorf("corrupted registry not kept: %v", err)
+	}
+	if err := v.RegistryError(); err != nil {
+		t.Errorf("RegistryError after baseline = %v", err)
+	}
+	report, _ = v.IntegrityAudit()
+	if len(report.Problems) != 0 {
+		t.Errorf("audit after baseline: %v", report.Problems)
+	}
+}
+
+func TestIntegrityBaseline_LogsPreexistingEntries(t *testing.T) {
+	v := newAuditVault(t)
+	path := filepath.Join(v.dir, "old.md")
+	os.WriteFile(path, []byte("# Old\n"), 0644)
+	v.registry.register(v.dir, path, []byte("# Old\n"))
+
+	// Simulate a registry created before the audit log existed.
+	os.Remove(filepath.Join(v.registry.dir, auditLogName))
+	report, _ := v.IntegrityAudit()
+	if len(report.Problems) != 1 || !strings.Contains(report.Problems[0], "never recorded") {
+		t.Fatalf("problems = %v, want unlogged entry", report.Problems)
+	}
+
+	// A missing log might have been deleted, so re-seeding needs force.
+	if err := v.IntegrityBaseline(false); err == nil || !strings.Contains(err.Error(), "no audit log") {
+		t.Fatalf("IntegrityBaseline without force = %v, want missing log error", err)
+	}
+	if err := v.IntegrityBaseline(true); err != nil {
+		t.Fatalf("IntegrityBaseline: %v", err)
+	}
+	report, _ = v.IntegrityAudit()
+	if len(report.Problems) != 0 || report.Records != 1 {
+		t.Errorf("report after baseline = %+v", report)
+	}
+
+	// Corrupting the registry as well must not get around force.
+	os.Remove(filepath.Join(v.registry.dir, auditLogName))
+	os.WriteFile(filepath.Join(v.registry.dir, "registry.json"), []byte("{not json"), 0600)
+	v.registry = openRegistry(v.dir)
+	if err := v.IntegrityBaseline(false); err == nil || !strings.Contains(err.Error(), "no audit log") {
+		t.Errorf("IntegrityBaseline on a corrupted registry without force = %v, want missing log error", err)
+	}
+}
+
+func TestAuditLog_Signed(t *testing.T) {
+	v := newAuditVault(t)
+	path := filepath.Join(v.dir, "note.md")
+
+	t.Setenv("VLT_AUDIT_KEY", "secret")
+	v.registry.register(v.dir, path, []byte("one\n"))
+	report, err := v.IntegrityAudit()
+	if err != nil {
+		t.Fatalf("IntegrityAudit: %v", err)
+	}
+	if !report.Signed || len(report.Problems) != 0 {
+		t.Fatalf("report = %+v, want signed and clean", report)
+	}
+
+	// A record written without the key is unsigned.
+	t.Setenv("VLT_AUDIT_KEY", "")
+	v.registry.register(v.dir, path, []byte("two\n"))
+	t.Setenv("VLT_AUDIT_KEY", "secret")
+	report, _ = v.IntegrityAudit()
+	if len(report.Problems) != 1 || !strings.Contains(report.Problems[0], "line 2: record is not signed") {
+		t.Errorf("problems = %v, want unsigned line 2", report.Problems)
+	}
+
+	// A rebuilt chain cannot be signed without the key.
+	t.Setenv("VLT_AUDIT_KEY", "other")
+	report, _ = v.IntegrityAudit()
+	if len(report.Problems) != 2 || !strings.Contains(report.Problems[0], "line 1: signature mismatch") {
+		t.Errorf("problems = %v, want signature mismatch on line 1", report.Problems)
+	}
+}
+
+func TestAuditError(t *testing.T) {
+	v := newAuditVault(t)
+	path := filepath.Join(v.dir, "note.md")
+
+	// A directory in place of the log makes every append fail.
+	os.MkdirAll(filepath.Join(v.registry.dir, auditLogName), 0755)
+	v.registry.register(v.dir, path, []byte("one\n"))
+	if err := v.AuditError(); err == nil || !strings.Contains(err.Error(), "audit log") {
+		t.Errorf("AuditError = %v, want append failure", err)
+	}
+	if got := v.registry.verify(v.dir, path, []byte("one\n")); got != IntegrityOK {
+		t.Errorf("registry not updated after audit failure: %q", got)
+	}
+}
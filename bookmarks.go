@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,12 +16,43 @@ type bookmarksFile struct {
 }
 
 // bookmark represents a single bookmark entry. Groups contain nested items.
+// Obsidian stores heading and block bookmarks as file bookmarks with a
+// subpath ("#Heading", "#^block-id") and search bookmarks with a query.
 type bookmark struct {
-	Type  string     `json:"type"`
-	Ctime int64      `json:"ctime"`
-	Path  string     `json:"path,omitempty"`
-	Title string     `json:"title,omitempty"`
-	Items []bookmark `json:"items,omitempty"`
+	Type    string     `json:"type"`
+	Ctime   int64      `json:"ctime"`
+	Path    string     `json:"path,omitempty"`
+	Subpath string     `json:"subpath,omitempty"`
+	Query   string     `json:"query,omitempty"`
+	URL     string     `json:"url,omitempty"`
+	Title   string     `json:"title,omitempty"`
+	Items   []bookmark `json:"items,omitempty"`
+}
+
+// BookmarkEntry is a bookmark as listed by BookmarksTree. Type is "file",
+// "heading", "block", "folder", "search", "url", "graph" or "group"; groups
+// carry their entries in Items.
+type BookmarkEntry struct {
+	Type    string          `json:"type"`
+	Title   string          `json:"title,omitempty"`
+	Path    string          `json:"path,omitempty"`
+	Subpath string          `json:"subpath,omitempty"`
+	Query   string          `json:"query,omitempty"`
+	URL     string          `json:"url,omitempty"`
+	Items   []BookmarkEntry `json:"items,omitempty"`
+}
+
+// BookmarkSpec describes a bookmark to add or remove. Set File alone for a
+// note, File with Heading or Block for a heading or block bookmark, Folder
+// for a folder, or Query for a saved search.
+type BookmarkSpec struct {
+	File    string // note title (resolved like other commands)
+	Heading string // heading text, with or without # prefix
+	Block   string // block ID, with or without ^
+	Folder  string // vault-relative folder
+	Query   string // search query
+	Title   string // display title (optional)
+	Group   string // group path, e.g. "Projects/Active"; empty for top level
 }
 
 // bookmarksPath returns the filesystem path to the bookmarks.json file.
@@ -67,71 +100,121 @@ func saveBookmarks(vaultDir string, bm *bookmarksFile) error {
 	return os.WriteFile(bookmarksPath(vaultDir), data, 0644)
 }
 
-// flattenBookmarks recursively collects all file-type bookmark paths,
-// descending into groups.
-func flattenBookmarks(items []bookmark) []string {
-	var paths []string
+// bookmarkType returns the user-facing type of a bookmark, telling heading
+// and block bookmarks apart from whole-file ones.
+func bookmarkType(b bookmark) string {
+	if b.Type == "file" && strings.HasPrefix(b.Subpath, "#^") {
+		return "block"
+	}
+	if b.Type == "file" && strings.HasPrefix(b.Subpath, "#") {
+		return "heading"
+	}
+	return b.Type
+}
+
+// bookmarkEntries converts stored bookmarks to BookmarkEntry values,
+// preserving order and nesting.
+func bookmarkEntries(items []bookmark) []BookmarkEntry {
+	entries := make([]BookmarkEntry, 0, len(items))
 	for _, item := range items {
-		switch item.Type {
-		case "file":
-			paths = append(paths, item.Path)
-		case "group":
-			paths = append(paths, flattenBookmarks(item.Items)...)
+		e := BookmarkEntry{
+			Type: bookmarkType(item), Title: item.Title, Path: item.Path,
+			Subpath: item.Subpath, Query: item.Query, URL: item.URL,
+		}
+		if item.Type == "group" {
+			e.Items = bookmarkEntries(item.Items)
 		}
+		entries = append(entries, e)
 	}
-	return paths
+	return entries
+}
+
+// sameBookmark reports whether two bookmarks point at the same target.
+func sameBookmark(a, b bookmark) bool {
+	return a.Type == b.Type && a.Type != "group" &&
+		a.Path == b.Path && a.Subpath == b.Subpath && a.Query == b.Query && a.URL == b.URL
 }
 
-// containsBookmark checks whether a path is already bookmarked,
-// recursing into groups.
-func containsBookmark(items []bookmark, path string) bool {
+// findBookmark reports whether target is bookmarked, recursing into groups.
+func findBookmark(items []bookmark, target bookmark) bool {
 	for _, item := range items {
-		if item.Type == "file" && item.Path == path {
+		if sameBookmark(item, target) {
 			return true
 		}
-		if item.Type == "group" && containsBookmark(item.Items, path) {
+		if item.Type == "group" && findBookmark(item.Items, target) {
 			return true
 		}
 	}
 	return false
 }
 
-// addBookmark adds a file bookmark to the top-level items array.
-// Returns false if the path is already bookmarked (no-op).
-func addBookmark(bm *bookmarksFile, path string) bool {
-	if containsBookmark(bm.Items, path) {
-		return false
+// removeMatching removes the first bookmark equal to target, recursing into
+// groups. Returns true if one was removed.
+func removeMatching(items *[]bookmark, target bookmark) bool {
+	for i, item := range *items {
+		if sameBookmark(item, target) {
+			*items = append((*items)[:i], (*items)[i+1:]...)
+			return true
+		}
+		if item.Type == "group" && removeMatching(&(*items)[i].Items, target) {
+			return true
+		}
 	}
-
-	bm.Items = append(bm.Items, bookmark{
-		Type:  "file",
-		Ctime: time.Now().UnixMilli(),
-		Path:  path,
-	})
-	return true
+	return false
 }
 
-// removeBookmark removes a file bookmark matching the given path,
-// searching recursively into groups. Returns false if not found.
-func removeBookmark(bm *bookmarksFile, path string) bool {
-	return removeFromItems(&bm.Items, path)
+// splitGroupPath splits "A/B" into its group names.
+func splitGroupPath(path string) []string {
+	var names []string
+	for _, name := range strings.Split(path, "/") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
-// removeFromItems removes a bookmark matching path from a slice,
-// recursing into groups. Returns true if found and removed.
-func removeFromItems(items *[]bookmark, path string) bool {
-	for i, item := range *items {
-		if item.Type == "file" && item.Path == path {
-			*items = append((*items)[:i], (*items)[i+1:]...)
-			return true
+// findGroup returns the items of the group at path ("" is the top level)
+// and the group itself. With create, missing groups along the path are
+// created.
+func findGroup(bm *bookmarksFile, path string, create bool) (*[]bookmark, *bookmark, error) {
+	items := &bm.Items
+	var group *bookmark
+	for _, name := range splitGroupPath(path) {
+		found := false
+		for i := range *items {
+			if (*items)[i].Type == "group" && (*items)[i].Title == name {
+				group = &(*items)[i]
+				found = true
+				break
+			}
 		}
-		if item.Type == "group" {
-			if removeFromItems(&(*items)[i].Items, path) {
-				return true
+		if !found {
+			if !create {
+				return nil, nil, fmt.Errorf("bookmark group %q not found", path)
 			}
+			*items = append(*items, bookmark{Type: "group", Ctime: time.Now().UnixMilli(), Title: name, Items: []bookmark{}})
+			group = &(*items)[len(*items)-1]
 		}
+		items = &group.Items
 	}
-	return false
+	return items, group, nil
+}
+
+// flattenBookmarks recursively collects all file-type bookmark paths,
+// descending into groups. Heading and block bookmarks keep their subpath
+// (e.g. "Note.md#Heading").
+func flattenBookmarks(items []bookmark) []string {
+	var paths []string
+	for _, item := range items {
+		switch item.Type {
+		case "file":
+			paths = append(paths, item.Path+item.Subpath)
+		case "group":
+			paths = append(paths, flattenBookmarks(item.Items)...)
+		}
+	}
+	return paths
 }
 
 // Bookmarks lists all bookmarked file paths (flat, recursing into groups).
@@ -152,40 +235,144 @@ func (v *Vault) Bookmarks() ([]string, error) {
 	return paths, nil
 }
 
-// BookmarksAdd adds a bookmark for a note resolved by title.
+// BookmarksTree lists all bookmarks in their stored order, with groups
+// nesting their entries.
+func (v *Vault) BookmarksTree() ([]BookmarkEntry, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	bm, err := loadBookmarks(v.dir)
+	if err != nil {
+		return nil, err
+	}
+	return bookmarkEntries(bm.Items), nil
+}
+
+// bookmarkTarget builds the stored bookmark described by spec, checking
+// that its note, heading, block or folder exists.
+func (v *Vault) bookmarkTarget(spec BookmarkSpec) (bookmark, error) {
+	b := bookmark{Title: spec.Title}
+	set := 0
+	for _, s := range []string{spec.File, spec.Folder, spec.Query} {
+		if s != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return b, fmt.Errorf("bookmark needs exactly one of a file, folder or query")
+	}
+	if spec.File == "" && (spec.Heading != "" || spec.Block != "") {
+		return b, fmt.Errorf("heading and block bookmarks need a file")
+	}
+	if spec.Heading != "" && spec.Block != "" {
+		return b, fmt.Errorf("use either a heading or a block, not both")
+	}
+
+	switch {
+	case spec.Query != "":
+		b.Type, b.Query = "search", spec.Query
+	case spec.Folder != "":
+		dir, err := safePath(v.dir, spec.Folder)
+		if err != nil {
+			return b, err
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return b, fmt.Errorf("folder %q not found", spec.Folder)
+		}
+		b.Type = "folder"
+		b.Path = filepath.ToSlash(filepath.Clean(spec.Folder))
+	default:
+		notePath, err := resolveNote(v.dir, spec.File)
+		if err != nil {
+			return b, err
+		}
+		relPath, err := filepath.Rel(v.dir, notePath)
+		if err != nil {
+			return b, err
+		}
+		b.Type, b.Path = "file", relPath
+
+		if spec.Heading != "" || spec.Block != "" {
+			data, err := os.ReadFile(notePath)
+			if err != nil {
+				return b, err
+			}
+			lines := strings.Split(string(data), "\n")
+			if spec.Heading != "" {
+				bounds, err := findSection(lines, spec.Heading)
+				if err != nil {
+					return b, fmt.Errorf("%s in %q", err, spec.File)
+				}
+				b.Subpath = "#" + headingText(lines[bounds.HeadingLine])
+			} else {
+				id := strings.TrimPrefix(spec.Block, "^")
+				if !hasBlockID(lines, id) {
+					return b, fmt.Errorf("block ^%s not found in %q", id, spec.File)
+				}
+				b.Subpath = "#^" + id
+			}
+		}
+	}
+	return b, nil
+}
+
+// hasBlockID reports whether a line ends with the block ID ^id.
+func hasBlockID(lines []string, id string) bool {
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "^"+id || strings.HasSuffix(line, " ^"+id) {
+			return true
+		}
+	}
+	return false
+}
+
+// bookmarkLabel describes a bookmark for messages.
+func bookmarkLabel(b bookmark) string {
+	switch b.Type {
+	case "search":
+		return "search " + strconv.Quote(b.Query)
+	case "folder":
+		return b.Path + "/"
+	}
+	return b.Path + b.Subpath
+}
+
+// BookmarksAdd adds a file, heading, block, folder or search bookmark at
+// the end of spec.Group (the top level when empty). The group must exist.
 // Returns a human-readable message (e.g. "bookmarked: path" or "already bookmarked: path").
-func (v *Vault) BookmarksAdd(title string) (string, error) {
+func (v *Vault) BookmarksAdd(spec BookmarkSpec) (string, error) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
-	notePath, err := resolveNote(v.dir, title)
+	b, err := v.bookmarkTarget(spec)
 	if err != nil {
 		return "", err
 	}
+	b.Ctime = time.Now().UnixMilli()
 
-	relPath, err := filepath.Rel(v.dir, notePath)
+	bm, err := loadBookmarks(v.dir)
 	if err != nil {
 		return "", err
 	}
-
-	bm, err := loadBookmarks(v.dir)
+	if findBookmark(bm.Items, b) {
+		return fmt.Sprintf("already bookmarked: %s", bookmarkLabel(b)), nil
+	}
+	items, _, err := findGroup(&bm, spec.Group, false)
 	if err != nil {
 		return "", err
 	}
-
-	if !addBookmark(&bm, relPath) {
-		return fmt.Sprintf("already bookmarked: %s", relPath), nil
-	}
+	*items = append(*items, b)
 
 	if err := saveBookmarks(v.dir, &bm); err != nil {
 		return "", err
 	}
-
-	return fmt.Sprintf("bookmarked: %s", relPath), nil
+	return fmt.Sprintf("bookmarked: %s", bookmarkLabel(b)), nil
 }
 
-// BookmarksRemove removes a bookmark for a note resolved by title.
-func (v *Vault) BookmarksRemove(title string) error {
+// BookmarksRemove removes the bookmark described by spec (spec.Group is
+// ignored; the bookmark is found in any group).
+func (v *Vault) BookmarksRemove(spec BookmarkSpec) error {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
@@ -195,24 +382,105 @@ func (v *Vault) BookmarksRemove(title string) error {
 		return fmt.Errorf("no bookmarks file found in vault")
 	}
 
-	notePath, err := resolveNote(v.dir, title)
+	b, err := v.bookmarkTarget(spec)
 	if err != nil {
 		return err
 	}
 
-	relPath, err := filepath.Rel(v.dir, notePath)
+	bm, err := loadBookmarks(v.dir)
 	if err != nil {
 		return err
 	}
 
+	if !removeMatching(&bm.Items, b) {
+		if spec.File != "" && b.Subpath == "" {
+			return fmt.Errorf("bookmark not found for %q (%s)", spec.File, b.Path)
+		}
+		return fmt.Errorf("bookmark not found: %s", bookmarkLabel(b))
+	}
+
+	return saveBookmarks(v.dir, &bm)
+}
+
+// BookmarksCreateGroup creates a bookmark group; a path such as
+// "Projects/Active" creates missing parent groups too.
+func (v *Vault) BookmarksCreateGroup(path string) error {
+	if len(splitGroupPath(path)) == 0 {
+		return fmt.Errorf("group name is required")
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
 	bm, err := loadBookmarks(v.dir)
 	if err != nil {
 		return err
 	}
+	if _, _, err := findGroup(&bm, path, false); err == nil {
+		return fmt.Errorf("bookmark group %q already exists", path)
+	}
+	if _, _, err := findGroup(&bm, path, true); err != nil {
+		return err
+	}
+	return saveBookmarks(v.dir, &bm)
+}
 
-	if !removeBookmark(&bm, relPath) {
-		return fmt.Errorf("bookmark not found for %q (%s)", title, relPath)
+// BookmarksRenameGroup renames the group at path, keeping its place and
+// contents.
+func (v *Vault) BookmarksRenameGroup(path, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" || strings.Contains(newName, "/") {
+		return fmt.Errorf("invalid group name %q", newName)
 	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
 
+	bm, err := loadBookmarks(v.dir)
+	if err != nil {
+		return err
+	}
+	names := splitGroupPath(path)
+	if len(names) == 0 {
+		return fmt.Errorf("group name is required")
+	}
+	parent := strings.Join(names[:len(names)-1], "/")
+	if _, _, err := findGroup(&bm, parent+"/"+newName, false); err == nil {
+		return fmt.Errorf("bookmark group %q already exists", newName)
+	}
+	_, group, err := findGroup(&bm, path, false)
+	if err != nil {
+		return err
+	}
+	group.Title = newName
 	return saveBookmarks(v.dir, &bm)
 }
+
+// BookmarksDeleteGroup deletes the group at path. A group that still holds
+// bookmarks is only deleted, with its contents, when force is set.
+func (v *Vault) BookmarksDeleteGroup(path string, force bool) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	bm, err := loadBookmarks(v.dir)
+	if err != nil {
+		return err
+	}
+	names := splitGroupPath(path)
+	if len(names) == 0 {
+		return fmt.Errorf("group name is required")
+	}
+	items, _, err := findGroup(&bm, strings.Join(names[:len(names)-1], "/"), false)
+	if err != nil {
+		return err
+	}
+	for i, item := range *items {
+		if item.Type != "group" || item.Title != names[len(names)-1] {
+			continue
+		}
+		if len(item.Items) > 0 && !force {
+			return fmt.Errorf("bookmark group %q is not empty (%d item(s)); use force to delete it with its contents", path, len(item.Items))
+		}
+		*items = append((*items)[:i], (*items)[i+1:]...)
+		return saveBookmarks(v.dir, &bm)
+	}
+	return fmt.Errorf("bookmark group %q not found", path)
+}
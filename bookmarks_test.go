@@ -122,48 +122,37 @@ func TestFlattenBookmarks(t *testing.T) {
 	}
 }
 
-func TestAddBookmark(t *testing.T) {
-	bm := &bookmarksFile{
-		Items: []bookmark{
-			{Type: "file", Path: "existing.md"},
-		},
-	}
-
-	added := addBookmark(bm, "new/Note.md")
-	if !added {
-		t.Fatal("addBookmark should return true for new bookmark")
-	}
-	if len(bm.Items) != 2 {
-		t.Fatalf("got %d items, want 2", len(bm.Items))
-	}
-	if bm.Items[1].Path != "new/Note.md" {
-		t.Errorf("new item path = %q, want %q", bm.Items[1].Path, "new/Note.md")
-	}
-	if bm.Items[1].Type != "file" {
-		t.Errorf("new item type = %q, want file", bm.Items[1].Type)
-	}
-	if bm.Items[1].Ctime == 0 {
-		t.Error("new item ctime should be set")
-	}
-}
-
-func TestAddBookmarkNoDuplicate(t *testing.T) {
-	bm := &bookmarksFile{
-		Items: []bookmark{
-			{Type: "file", Path: "existing.md", Ctime: 1708300000000},
+func TestFindBookmark(t *testing.T) {
+	items := []bookmark{
+		{Type: "file", Path: "existing.md", Ctime: 1708300000000},
+		{Type: "file", Path: "Note.md", Subpath: "#Heading"},
+		{
+			Type:  "group",
+			Title: "My Group",
+			Items: []bookmark{
+				{Type: "file", Path: "nested/deep.md"},
+			},
 		},
 	}
 
-	added := addBookmark(bm, "existing.md")
-	if added {
-		t.Fatal("addBookmark should return false for duplicate")
+	tests := []struct {
+		target bookmark
+		want   bool
+	}{
+		{bookmark{Type: "file", Path: "existing.md"}, true},
+		{bookmark{Type: "file", Path: "nested/deep.md"}, true},
+		{bookmark{Type: "file", Path: "Note.md", Subpath: "#Heading"}, true},
+		{bookmark{Type: "file", Path: "Note.md"}, false},
+		{bookmark{Type: "file", Path: "missing.md"}, false},
 	}
-	if len(bm.Items) != 1 {
-		t.Fatalf("got %d items, want 1 (no duplicate)", len(bm.Items))
+	for _, tt := range tests {
+		if got := findBookmark(items, tt.target); got != tt.want {
+			t.Errorf("findBookmark(%+v) = %v, want %v", tt.target, got, tt.want)
+		}
 	}
 }
 
-func TestRemoveBookmark(t *testing.T) {
+func TestRemoveMatching(t *testing.T) {
 	bm := &bookmarksFile{
 		Items: []bookmark{
 			{Type: "file", Path: "keep.md"},
@@ -172,9 +161,9 @@ func TestRemoveBookmark(t *testing.T) {
 		},
 	}
 
-	removed := removeBookmark(bm, "remove.md")
+	removed := removeMatching(&bm.Items, bookmark{Type: "file", Path: "remove.md"})
 	if !removed {
-		t.Fatal("removeBookmark should return true when bookmark found")
+		t.Fatal("removeMatching should return true when bookmark found")
 	}
 	if len(bm.Items) != 2 {
 		t.Fatalf("got %d items, want 2", len(bm.Items))
@@ -186,7 +175,7 @@ func TestRemoveBookmark(t *testing.T) {
 	}
 }
 
-func TestRemoveBookmarkFromGroup(t *testing.T) {
+func TestRemoveMatchingFromGroup(t *testing.T) {
 	bm := &bookmarksFile{
 		Items: []bookmark{
 			{Type: "file", Path: "top.md"},
@@ -201,9 +190,9 @@ func TestRemoveBookmarkFromGroup(t *testing.T) {
 		},
 	}
 
-	removed := removeBookmark(bm, "nested/remove.md")
+	removed := removeMatching(&bm.Items, bookmark{Type: "file", Path: "nested/remove.md"})
 	if !removed {
-		t.Fatal("removeBookmark should find bookmark in group")
+		t.Fatal("removeMatching should find bookmark in group")
 	}
 	if len(bm.Items[1].Items) != 1 {
 		t.Fatalf("group has %d items, want 1", len(bm.Items[1].Items))
@@ -213,16 +202,16 @@ func TestRemoveBookmarkFromGroup(t *testing.T) {
 	}
 }
 
-func TestRemoveBookmarkNotFound(t *testing.T) {
+func TestRemoveMatchingNotFound(t *testing.T) {
 	bm := &bookmarksFile{
 		Items: []bookmark{
 			{Type: "file", Path: "keep.md"},
 		},
 	}
 
-	removed := removeBookmark(bm, "nonexistent.md")
+	removed := removeMatching(&bm.Items, bookmark{Type: "file", Path: "nonexistent.md"})
 	if removed {
-		t.Fatal("removeBookmark should return false when not found")
+		t.Fatal("removeMatching should return false when not found")
 	}
 }
 
@@ -283,7 +272,7 @@ func TestBookmarksAddIntegration(t *testing.T) {
 
 	// Add bookmark
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	_, err := v.BookmarksAdd("MyNote")
+	_, err := v.BookmarksAdd(BookmarkSpec{File: "MyNote"})
 	if err != nil {
 		t.Fatalf("BookmarksAdd: %v", err)
 	}
@@ -299,6 +288,12 @@ func TestBookmarksAddIntegration(t *testing.T) {
 	if loaded.Items[0].Path != "notes/MyNote.md" {
 		t.Errorf("bookmark path = %q, want notes/MyNote.md", loaded.Items[0].Path)
 	}
+	if loaded.Items[0].Type != "file" {
+		t.Errorf("bookmark type = %q, want file", loaded.Items[0].Type)
+	}
+	if loaded.Items[0].Ctime == 0 {
+		t.Error("bookmark ctime should be set")
+	}
 }
 
 func TestBookmarksRemoveIntegration(t *testing.T) {
@@ -321,7 +316,7 @@ func TestBookmarksRemoveIntegration(t *testing.T) {
 
 	// Remove bookmark
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	if err := v.BookmarksRemove("RemoveMe"); err != nil {
+	if err := v.BookmarksRemove(BookmarkSpec{File: "RemoveMe"}); err != nil {
 		t.Fatalf("BookmarksRemove: %v", err)
 	}
 
@@ -357,7 +352,7 @@ func TestBookmarksAddResolvesTitle(t *testing.T) {
 
 	// Add by title
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	_, err := v.BookmarksAdd("Hidden Gem")
+	_, err := v.BookmarksAdd(BookmarkSpec{File: "Hidden Gem"})
 	if err != nil {
 		t.Fatalf("BookmarksAdd: %v", err)
 	}
@@ -383,7 +378,7 @@ func TestBookmarksAddCreatesObsidianDir(t *testing.T) {
 
 	// Add bookmark -- should create .obsidian/ and bookmarks.json
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	_, err := v.BookmarksAdd("NewNote")
+	_, err := v.BookmarksAdd(BookmarkSpec{File: "NewNote"})
 	if err != nil {
 		t.Fatalf("BookmarksAdd: %v", err)
 	}
@@ -430,7 +425,7 @@ func TestBookmarksRemoveNoFile(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "Orphan.md"), []byte("# Orphan\n"), 0644)
 
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	err := v.BookmarksRemove("Orphan")
+	err := v.BookmarksRemove(BookmarkSpec{File: "Orphan"})
 	if err == nil {
 		t.Fatal("BookmarksRemove should error when bookmarks.json does not exist")
 	}
@@ -457,7 +452,7 @@ func TestBookmarksAddDuplicateIntegration(t *testing.T) {
 
 	// Add again -- should be a no-op
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	_, err := v.BookmarksAdd("Dup")
+	_, err := v.BookmarksAdd(BookmarkSpec{File: "Dup"})
 	if err != nil {
 		t.Fatalf("BookmarksAdd duplicate: %v", err)
 	}
@@ -471,3 +466,132 @@ func TestBookmarksAddDuplicateIntegration(t *testing.T) {
 		t.Fatalf("got %d items, want 1 (no duplicate)", len(loaded.Items))
 	}
 }
+
+func TestBookmarksAdd_Types(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "Roadmap.md"),
+		[]byte("# Roadmap\n\n## Q3 Goals\n\nShip it. ^decision-1\n"), 0644)
+
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	specs := []struct {
+		spec BookmarkSpec
+		want string
+	}{
+		{BookmarkSpec{File: "Roadmap", Heading: "## q3 goals"}, "bookmarked: Roadmap.md#Q3 Goals"},
+		{BookmarkSpec{File: "Roadmap", Block: "^decision-1"}, "bookmarked: Roadmap.md#^decision-1"},
+		{BookmarkSpec{Folder: "projects/"}, "bookmarked: projects/"},
+		{BookmarkSpec{Query: "tag:#urgent", Title: "Urgent"}, `bookmarked: search "tag:#urgent"`},
+		{BookmarkSpec{File: "Roadmap", Heading: "Q3 Goals"}, "already bookmarked: Roadmap.md#Q3 Goals"},
+	}
+	for _, tt := range specs {
+		msg, err := v.BookmarksAdd(tt.spec)
+		if err != nil {
+			t.Fatalf("BookmarksAdd(%+v): %v", tt.spec, err)
+		}
+		if msg != tt.want {
+			t.Errorf("BookmarksAdd(%+v) = %q, want %q", tt.spec, msg, tt.want)
+		}
+	}
+
+	loaded, _ := loadBookmarks(vaultDir)
+	if len(loaded.Items) != 4 {
+		t.Fatalf("got %d items, want 4", len(loaded.Items))
+	}
+	if b := loaded.Items[0]; b.Type != "file" || b.Subpath != "#Q3 Goals" {
+		t.Errorf("heading bookmark = %+v, want file with subpath #Q3 Goals", b)
+	}
+	if b := loaded.Items[3]; b.Type != "search" || b.Query != "tag:#urgent" || b.Title != "Urgent" {
+		t.Errorf("search bookmark = %+v", b)
+	}
+
+	paths, _ := v.Bookmarks()
+	if want := []string{"Roadmap.md#Q3 Goals", "Roadmap.md#^decision-1"}; strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("Bookmarks() = %v, want %v", paths, want)
+	}
+
+	if err := v.BookmarksRemove(BookmarkSpec{File: "Roadmap", Block: "decision-1"}); err != nil {
+		t.Fatalf("BookmarksRemove block: %v", err)
+	}
+	if err := v.BookmarksRemove(BookmarkSpec{Query: "tag:#urgent"}); err != nil {
+		t.Fatalf("BookmarksRemove search: %v", err)
+	}
+	loaded, _ = loadBookmarks(vaultDir)
+	if len(loaded.Items) != 2 {
+		t.Errorf("got %d items after removal, want 2", len(loaded.Items))
+	}
+
+	errs := []struct {
+		spec BookmarkSpec
+		want string
+	}{
+		{BookmarkSpec{File: "Roadmap", Heading: "Q4"}, `heading "Q4" not found`},
+		{BookmarkSpec{File: "Roadmap", Block: "nope"}, "block ^nope not found"},
+		{BookmarkSpec{Folder: "missing"}, `folder "missing" not found`},
+		{BookmarkSpec{File: "Roadmap", Query: "x"}, "exactly one of"},
+		{BookmarkSpec{Heading: "Q3 Goals", Query: "x"}, "need a file"},
+		{BookmarkSpec{File: "Roadmap", Group: "Nope"}, `bookmark group "Nope" not found`},
+	}
+	for _, tt := range errs {
+		_, err := v.BookmarksAdd(tt.spec)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("BookmarksAdd(%+v) error = %v, want %q", tt.spec, err, tt.want)
+		}
+	}
+}
+
+func TestBookmarksGroups(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "Roadmap.md"), []byte("# Roadmap\n"), 0644)
+
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	if err := v.BookmarksCreateGroup("Projects/Active"); err != nil {
+		t.Fatalf("BookmarksCreateGroup: %v", err)
+	}
+	if err := v.BookmarksCreateGroup("Projects"); err == nil {
+		t.Error("expected error creating an existing group")
+	}
+	if _, err := v.BookmarksAdd(BookmarkSpec{File: "Roadmap", Group: "Projects/Active"}); err != nil {
+		t.Fatalf("BookmarksAdd into group: %v", err)
+	}
+	if err := v.BookmarksCreateGroup("Projects/Done"); err != nil {
+		t.Fatalf("BookmarksCreateGroup: %v", err)
+	}
+	if err := v.BookmarksRenameGroup("Projects/Active", "Done"); err == nil {
+		t.Error("expected error renaming onto an existing sibling")
+	}
+	if err := v.BookmarksRenameGroup("Projects/Active", "Current"); err != nil {
+		t.Fatalf("BookmarksRenameGroup: %v", err)
+	}
+
+	tree, err := v.BookmarksTree()
+	if err != nil {
+		t.Fatalf("BookmarksTree: %v", err)
+	}
+	if len(tree) != 1 || tree[0].Type != "group" || tree[0].Title != "Projects" || len(tree[0].Items) != 2 {
+		t.Fatalf("tree = %+v", tree)
+	}
+	current := tree[0].Items[0]
+	if current.Title != "Current" || len(current.Items) != 1 || current.Items[0].Path != "Roadmap.md" {
+		t.Errorf("renamed group = %+v", current)
+	}
+
+	if err := v.BookmarksDeleteGroup("Projects/Current", false); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("expected not-empty error, got %v", err)
+	}
+	if err := v.BookmarksDeleteGroup("Projects/Done", false); err != nil {
+		t.Fatalf("BookmarksDeleteGroup empty: %v", err)
+	}
+	if err := v.BookmarksDeleteGroup("Projects", true); err != nil {
+		t.Fatalf("BookmarksDeleteGroup force: %v", err)
+	}
+	if err := v.BookmarksDeleteGroup("Projects", true); err == nil {
+		t.Error("expected error deleting a missing group")
+	}
+	paths, _ := v.Bookmarks()
+	if len(paths) != 0 {
+		t.Errorf("Bookmarks() = %v, want empty after deleting the group", paths)
+	}
+}
@@ -0,0 +1,576 @@
+package vlt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Canvas is the parsed content of an Obsidian Canvas (.canvas) file, which
+// follows the JSON Canvas format. Keys vlt does not model (at any level) are
+// kept and written back unchanged, in their original order.
+type Canvas struct {
+	Nodes []CanvasNode `json:"nodes"`
+	Edges []CanvasEdge `json:"edges"`
+
+	fields []jsonField // members as read, for round-tripping unknown keys
+}
+
+// CanvasNode is a single card on a canvas. Type is "text", "file", "link"
+// or "group"; only the fields relevant to the type are populated.
+type CanvasNode struct {
+	ID      string  `json:"id"`
+	Type    string  `json:"type"`
+	Text    string  `json:"text,omitempty"`    // text nodes: markdown content
+	File    string  `json:"file,omitempty"`    // file nodes: vault-relative path
+	Subpath string  `json:"subpath,omitempty"` // file nodes: #heading or #^block
+	URL     string  `json:"url,omitempty"`     // link nodes
+	Label   string  `json:"label,omitempty"`   // group nodes
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Width   float64 `json:"width"`
+	Height  float64 `json:"height"`
+	Color   string  `json:"color,omitempty"`
+
+	fields []jsonField
+}
+
+// CanvasEdge connects two canvas nodes.
+type CanvasEdge struct {
+	ID       string `json:"id"`
+	FromNode string `json:"fromNode"`
+	FromSide string `json:"fromSide,omitempty"`
+	ToNode   string `json:"toNode"`
+	ToSide   string `json:"toSide,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Color    string `json:"color,omitempty"`
+
+	fields []jsonField
+}
+
+// Keys modelled by Canvas, CanvasNode and CanvasEdge. Any other key is
+// carried through untouched (e.g. edge fromEnd/toEnd, group background).
+var (
+	canvasKeys     = []string{"nodes", "edges"}
+	canvasNodeKeys = []string{"id", "type", "text", "file", "subpath", "url", "label", "x", "y", "width", "height", "color"}
+	canvasEdgeKeys = []string{"id", "fromNode", "fromSide", "toNode", "toSide", "label", "color"}
+)
+
+func (c *Canvas) UnmarshalJSON(data []byte) error {
+	type plain Canvas
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	fields, err := decodeObjectFields(data)
+	if err != nil {
+		return err
+	}
+	*c = Canvas(p)
+	c.fields = fields
+	return nil
+}
+
+func (c Canvas) MarshalJSON() ([]byte, error) {
+	type plain Canvas
+	known, err := json.Marshal(plain(c))
+	if err != nil {
+		return nil, err
+	}
+	return mergeObjectFields(c.fields, known, canvasKeys)
+}
+
+func (n *CanvasNode) UnmarshalJSON(data []byte) error {
+	type plain CanvasNode
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	fields, err := decodeObjectFields(data)
+	if err != nil {
+		return err
+	}
+	*n = CanvasNode(p)
+	n.fields = fields
+	return nil
+}
+
+func (n CanvasNode) MarshalJSON() ([]byte, error) {
+	type plain CanvasNode
+	known, err := json.Marshal(plain(n))
+	if err != nil {
+		return nil, err
+	}
+	return mergeObjectFields(n.fields, known, canvasNodeKeys)
+}
+
+func (e *CanvasEdge) UnmarshalJSON(data []byte) error {
+	type plain CanvasEdge
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	fields, err := decodeObjectFields(data)
+	if err != nil {
+		return err
+	}
+	*e = CanvasEdge(p)
+	e.fields = fields
+	return nil
+}
+
+func (e CanvasEdge) MarshalJSON() ([]byte, error) {
+	type plain CanvasEdge
+	known, err := json.Marshal(plain(e))
+	if err != nil {
+		return nil, err
+	}
+	return mergeObjectFields(e.fields, known, canvasEdgeKeys)
+}
+
+// jsonField is one member of a JSON object.
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeObjectFields returns the members of a JSON object in document order.
+func decodeObjectFields(data []byte) ([]jsonField, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	var fields []jsonField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, jsonField{key, value})
+	}
+	return fields, nil
+}
+
+// mergeObjectFields lays the freshly marshalled known members over the
+// original ones: known keys take their new value (or are dropped when now
+// omitted), unknown keys keep their original value, and original order is
+// preserved. Known keys that were not there before are appended.
+func mergeObjectFields(original []jsonField, known []byte, knownKeys []string) ([]byte, error) {
+	updated, err := decodeObjectFields(known)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]json.RawMessage, len(updated))
+	for _, f := range updated {
+		values[f.key] = f.value
+	}
+	isKnown := make(map[string]bool, len(knownKeys))
+	for _, k := range knownKeys {
+		isKnown[k] = true
+	}
+
+	merged := make([]jsonField, 0, len(original)+len(updated))
+	used := make(map[string]bool)
+	for _, f := range original {
+		if value, ok := values[f.key]; ok {
+			merged = append(merged, jsonField{f.key, value})
+			used[f.key] = true
+		} else if !isKnown[f.key] {
+			merged = append(merged, f)
+		}
+	}
+	for _, f := range updated {
+		if !used[f.key] {
+			merged = append(merged, f)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range merged {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(f.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// CanvasNodeOptions parameterises a CanvasAddNode call.
+type CanvasNodeOptions struct {
+	Type   string   // "text" (default), "file", "link" or "group"
+	Text   string   // text nodes
+	Note   string   // file nodes: note title (or attachment name) to reference
+	URL    string   // link nodes
+	Label  string   // group nodes
+	Color  string   // optional canvas color ("1"-"6" or "#rrggbb")
+	X, Y   *float64 // position; nil places the node right of existing nodes
+	Width  float64  // default 400
+	Height float64  // default 200
+	From   string   // optional node ID to connect to the new node with an edge
+}
+
+// canvasNodeGap is the horizontal spacing used when auto-placing nodes.
+const canvasNodeGap = 40
+
+// isCanvasFile reports whether a filename is an Obsidian canvas.
+func isCanvasFile(name string) bool {
+	return strings.HasSuffix(name, ".canvas")
+}
+
+// parseCanvas decodes canvas JSON. An empty file is a valid empty canvas.
+func parseCanvas(data []byte) (Canvas, error) {
+	var c Canvas
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return c, nil
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Canvas{}, fmt.Errorf("cannot parse canvas: %w", err)
+	}
+	return c, nil
+}
+
+// marshalCanvas encodes a canvas the way Obsidian writes it (tab-indented).
+func marshalCanvas(c Canvas) ([]byte, error) {
+	if c.Nodes == nil {
+		c.Nodes = []CanvasNode{}
+	}
+	if c.Edges == nil {
+		c.Edges = []CanvasEdge{}
+	}
+	return json.MarshalIndent(c, "", "\t")
+}
+
+// CanvasLinks returns the references a canvas makes, expressed as wikilinks
+// so they can be treated like links in notes: file nodes become a link to the
+// file's title (attachments keep their extension), and wikilinks inside text
+// nodes are parsed as usual.
+func CanvasLinks(c Canvas) []Wikilink {
+	var links []Wikilink
+	for _, n := range c.Nodes {
+		switch n.Type {
+		case "file":
+			if n.File == "" {
+				continue
+			}
+			links = append(links, Wikilink{
+				Title: strings.TrimSuffix(filepath.Base(n.File), ".md"),
+				Raw:   n.File,
+				Embed: true,
+			})
+		case "text":
+			links = append(links, ParseWikilinks(n.Text)...)
+		}
+	}
+	return links
+}
+
+// noteLinks returns the wikilinks in a vault file: parsed wikilinks for
+// markdown notes and CanvasLinks for canvases. Unparseable canvases yield no
+// links.
+func noteLinks(name string, data []byte) []Wikilink {
+	if isCanvasFile(name) {
+		c, err := parseCanvas(data)
+		if err != nil {
+			return nil
+		}
+		return CanvasLinks(c)
+	}
+	return ParseWikilinks(string(data))
+}
+
+// isLinkSource reports whether a vault file can contain links: markdown
+// notes and canvases.
+func isLinkSource(name string) bool {
+	return strings.HasSuffix(name, ".md") || isCanvasFile(name)
+}
+
+// resolveCanvas finds a canvas by name, with or without the .canvas
+// extension.
+func resolveCanvas(vaultDir, name string) (string, error) {
+	if !isCanvasFile(name) {
+		name += ".canvas"
+	}
+	path, ok := resolveAttachment(vaultDir, name)
+	if !ok {
+		return "", fmt.Errorf("canvas %q not found in vault", name)
+	}
+	return path, nil
+}
+
+// newCanvasID returns a random 16-hex-digit identifier, matching the IDs
+// Obsidian generates for nodes and edges.
+func newCanvasID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// rewriteCanvas updates references in a canvas after a file moves: file nodes
+// pointing at oldRelPath are repointed to newRelPath, and wikilinks in text
+// nodes are renamed from oldTitle to newTitle. Returns true if anything changed.
+func rewriteCanvas(c *Canvas, oldRelPath, newRelPath, oldTitle, newTitle string) bool {
+	oldSlash := filepath.ToSlash(filepath.Clean(oldRelPath))
+	newSlash := filepath.ToSlash(filepath.Clean(newRelPath))
+
+	changed := false
+	for i := range c.Nodes {
+		n := &c.Nodes[i]
+		switch n.Type {
+		case "file":
+			if n.File == oldSlash && oldSlash != newSlash {
+				n.File = newSlash
+				changed = true
+			}
+		case "text":
+			if oldTitle == newTitle {
+				continue
+			}
+			updated := ReplaceWikilinks(n.Text, oldTitle, newTitle)
+			if updated != n.Text {
+				n.Text = updated
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// unlinkCanvas removes references to a deleted note: file nodes pointing at
+// relPath become text nodes holding the note's title, and wikilinks to any of
+// titles in text nodes are replaced by plain text. Returns true if anything
+// changed.
+func unlinkCanvas(c *Canvas, relPath string, titles []string) bool {
+	relSlash := filepath.ToSlash(filepath.Clean(relPath))
+	title := strings.TrimSuffix(filepath.Base(relSlash), ".md")
+
+	changed := false
+	for i := range c.Nodes {
+		n := &c.Nodes[i]
+		switch n.Type {
+		case "file":
+			if n.File == relSlash {
+				n.Type, n.Text, n.File, n.Subpath = "text", title, "", ""
+				changed = true
+			}
+		case "text":
+			for _, t := range titles {
+				if updated := UnlinkWikilinks(n.Text, t); updated != n.Text {
+					n.Text = updated
+					changed = true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// updateVaultCanvasLinks rewrites references in every canvas after a file
+// moves (see rewriteCanvas). Returns the number of canvases modified.
+// If reg is non-nil, updated canvases are registered for integrity tracking.
+func updateVaultCanvasLinks(vaultDir, oldRelPath, newRelPath, oldTitle, newTitle string, reg *Registry) (int, error) {
+	edits, err := canvasLinkEdits(vaultDir, oldRelPath, newRelPath, oldTitle, newTitle)
+	if err != nil {
+		return 0, err
+	}
+	return applyEdits(vaultDir, edits, reg)
+}
+
+// canvasLinkEdits plans the edits of updateVaultCanvasLinks without writing.
+func canvasLinkEdits(vaultDir, oldRelPath, newRelPath, oldTitle, newTitle string) ([]fileEdit, error) {
+	return canvasRewriteEdits(vaultDir, func(c *Canvas) bool {
+		return rewriteCanvas(c, oldRelPath, newRelPath, oldTitle, newTitle)
+	})
+}
+
+// canvasRewriteEdits applies rewrite to every canvas in vaultDir and returns
+// an edit for each canvas it changed. Canvases that cannot be parsed are
+// left untouched.
+func canvasRewriteEdits(vaultDir string, rewrite func(*Canvas) bool) ([]fileEdit, error) {
+	var edits []fileEdit
+
+	err := filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipHiddenDir(path, d, vaultDir) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !isCanvasFile(d.Name()) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		c, err := parseCanvas(data)
+		if err != nil {
+			return nil // leave files we cannot parse untouched
+		}
+		if !rewrite(&c) {
+			return nil
+		}
+
+		updated, err := marshalCanvas(c)
+		if err != nil {
+			return err
+		}
+		edits = append(edits, fileEdit{path, updated})
+		return nil
+	})
+
+	return edits, err
+}
+
+// CanvasRead parses a canvas resolved by name (with or without .canvas) and
+// returns it with its integrity status. Under the block policy a mismatched
+// canvas is an *IntegrityError.
+func (v *Vault) CanvasRead(name string) (Canvas, IntegrityStatus, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	path, err := resolveCanvas(v.dir, name)
+	if err != nil {
+		return Canvas{}, 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Canvas{}, 0, err
+	}
+	status := v.registry.verify(v.dir, path, data)
+	if err := v.registry.blocked(v.dir, path, status, "read"); err != nil {
+		return Canvas{}, status, err
+	}
+	c, err := parseCanvas(data)
+	return c, status, err
+}
+
+// CanvasAddNode appends a node to a canvas and returns it. File nodes
+// reference a note (or attachment) resolved by opts.Note. When opts.From is
+// set, an edge is added from that node to the new one.
+func (v *Vault) CanvasAddNode(name string, opts CanvasNodeOptions) (CanvasNode, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	path, err := resolveCanvas(v.dir, name)
+	if err != nil {
+		return CanvasNode{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CanvasNode{}, err
+	}
+	c, err := parseCanvas(data)
+	if err != nil {
+		return CanvasNode{}, err
+	}
+
+	node := CanvasNode{
+		ID:     newCanvasID(),
+		Type:   opts.Type,
+		Color:  opts.Color,
+		Width:  opts.Width,
+		Height: opts.Height,
+	}
+	if node.Type == "" {
+		node.Type = "text"
+	}
+
+	switch node.Type {
+	case "text":
+		if opts.Text == "" {
+			return CanvasNode{}, fmt.Errorf("text node requires text")
+		}
+		node.Text = opts.Text
+	case "file":
+		if opts.Note == "" {
+			return CanvasNode{}, fmt.Errorf("file node requires a note")
+		}
+		notePath, err := resolveNote(v.dir, opts.Note)
+		if err != nil {
+			return CanvasNode{}, err
+		}
+		rel, _ := filepath.Rel(v.dir, notePath)
+		node.File = filepath.ToSlash(rel)
+	case "link":
+		if opts.URL == "" {
+			return CanvasNode{}, fmt.Errorf("link node requires url")
+		}
+		node.URL = opts.URL
+	case "group":
+		node.Label = opts.Label
+	default:
+		return CanvasNode{}, fmt.Errorf("unknown canvas node type %q (use text, file, link or group)", node.Type)
+	}
+
+	if node.Width <= 0 {
+		node.Width = 400
+	}
+	if node.Height <= 0 {
+		node.Height = 200
+	}
+
+	if opts.X != nil && opts.Y != nil {
+		node.X, node.Y = *opts.X, *opts.Y
+	} else if len(c.Nodes) > 0 {
+		// Place to the right of the rightmost node, top-aligned with it.
+		right := c.Nodes[0]
+		for _, n := range c.Nodes[1:] {
+			if n.X+n.Width > right.X+right.Width {
+				right = n
+			}
+		}
+		node.X = right.X + right.Width + canvasNodeGap
+		node.Y = right.Y
+	}
+
+	if opts.From != "" {
+		found := false
+		for _, n := range c.Nodes {
+			if n.ID == opts.From {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return CanvasNode{}, fmt.Errorf("node %q not found in canvas", opts.From)
+		}
+		c.Edges = append(c.Edges, CanvasEdge{
+			ID:       newCanvasID(),
+			FromNode: opts.From,
+			FromSide: "right",
+			ToNode:   node.ID,
+			ToSide:   "left",
+		})
+	}
+
+	c.Nodes = append(c.Nodes, node)
+
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return CanvasNode{}, err
+	}
+	updated, err := marshalCanvas(c)
+	if err != nil {
+		return CanvasNode{}, err
+	}
+	if err := os.WriteFile(path, updated, 0644); err != nil {
+		return CanvasNode{}, err
+	}
+	v.registry.register(v.dir, path, updated)
+	return node, nil
+}
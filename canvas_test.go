@@ -0,0 +1,224 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCanvas = `{
+	"nodes": [
+		{"id": "a1", "type": "file", "file": "projects/Plan.md", "x": 0, "y": 0, "width": 400, "height": 400},
+		{"id": "b2", "type": "text", "text": "See [[Goals]] and ![[chart.png]]", "x": 500, "y": 100, "width": 250, "height": 60},
+		{"id": "c3", "type": "link", "url": "https://example.com", "x": 0, "y": 500, "width": 400, "height": 200},
+		{"id": "d4", "type": "file", "file": "Missing.md", "x": 900, "y": 0, "width": 400, "height": 400}
+	],
+	"edges": [
+		{"id": "e1", "fromNode": "a1", "fromSide": "right", "toNode": "b2", "toSide": "left"}
+	]
+}`
+
+func TestParseCanvas(t *testing.T) {
+	c, err := parseCanvas([]byte(testCanvas))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(c.Nodes) != 4 || len(c.Edges) != 1 {
+		t.Fatalf("got %d nodes, %d edges; want 4, 1", len(c.Nodes), len(c.Edges))
+	}
+	if c.Nodes[0].File != "projects/Plan.md" || c.Nodes[2].URL != "https://example.com" {
+		t.Errorf("unexpected nodes: %+v", c.Nodes)
+	}
+
+	empty, err := parseCanvas([]byte("  \n"))
+	if err != nil || len(empty.Nodes) != 0 {
+		t.Errorf("empty canvas: got %+v, %v", empty, err)
+	}
+
+	if _, err := parseCanvas([]byte("{not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestCanvasLinks(t *testing.T) {
+	c, _ := parseCanvas([]byte(testCanvas))
+	var titles []string
+	for _, l := range CanvasLinks(c) {
+		titles = append(titles, l.Title)
+	}
+	got := strings.Join(titles, ",")
+	want := "Plan,Goals,chart.png,Missing"
+	if got != want {
+		t.Errorf("CanvasLinks titles = %q, want %q", got, want)
+	}
+}
+
+func TestCanvas_BacklinksOrphansUnresolved(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Plan.md"), []byte("# Plan\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Goals.md"), []byte("# Goals\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Lonely.md"), []byte("# Lonely\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "chart.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Board.canvas"), []byte(testCanvas), 0644)
+
+	backlinks, err := v.Backlinks("Plan")
+	if err != nil {
+		t.Fatalf("backlinks: %v", err)
+	}
+	if len(backlinks) != 1 || backlinks[0] != "Board.canvas" {
+		t.Errorf("Backlinks(Plan) = %v, want [Board.canvas]", backlinks)
+	}
+
+	orphans, err := v.Orphans()
+	if err != nil {
+		t.Fatalf("orphans: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0] != "Lonely.md" {
+		t.Errorf("Orphans = %v, want [Lonely.md]", orphans)
+	}
+
+	unresolved, err := v.Unresolved()
+	if err != nil {
+		t.Fatalf("unresolved: %v", err)
+	}
+	if len(unresolved) != 1 || unresolved[0].Target != "Missing" || unresolved[0].Source != "Board.canvas" {
+		t.Errorf("Unresolved = %+v, want Missing in Board.canvas", unresolved)
+	}
+
+	unused, err := v.UnusedAttachments()
+	if err != nil {
+		t.Fatalf("unused: %v", err)
+	}
+	if len(unused) != 0 {
+		t.Errorf("UnusedAttachments = %v, want none (chart.png is embedded in the canvas)", unused)
+	}
+}
+
+func TestMove_RewritesCanvas(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Plan.md"), []byte("# Plan\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Goals.md"), []byte("# Goals\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Board.canvas"), []byte(testCanvas), 0644)
+
+	res, err := v.Move("projects/Plan.md", "archive/Roadmap.md")
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if res.CanvasesUpdated != 1 {
+		t.Errorf("CanvasesUpdated = %d, want 1", res.CanvasesUpdated)
+	}
+	c, _, _ := v.CanvasRead("Board")
+	if c.Nodes[0].File != "archive/Roadmap.md" {
+		t.Errorf("file node = %q, want archive/Roadmap.md", c.Nodes[0].File)
+	}
+	if len(c.Edges) != 1 {
+		t.Errorf("edges lost on rewrite: %+v", c.Edges)
+	}
+
+	if _, err := v.Move("Goals.md", "Objectives.md"); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	c, _, _ = v.CanvasRead("Board.canvas")
+	if c.Nodes[1].Text != "See [[Objectives]] and ![[chart.png]]" {
+		t.Errorf("text node = %q", c.Nodes[1].Text)
+	}
+}
+
+func TestMove_CanvasKeepsUnknownFields(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "Plan.md"), []byte("# Plan\n"), 0644)
+	board := `{
+	"nodes": [
+		{"id": "a1", "type": "file", "file": "Plan.md", "x": 10.5, "y": -20.25, "width": 400, "height": 400, "styleAttributes": {"border": "dashed"}},
+		{"id": "g1", "type": "group", "label": "Work", "x": 0, "y": 0, "width": 800, "height": 600, "background": "bg.png", "backgroundStyle": "cover"}
+	],
+	"edges": [
+		{"id": "e1", "fromNode": "g1", "fromSide": "right", "fromEnd": "arrow", "toNode": "a1", "toSide": "left", "toEnd": "none"}
+	],
+	"metadata": {"version": "1.0-1.0"}
+}`
+	os.WriteFile(filepath.Join(vaultDir, "Board.canvas"), []byte(board), 0644)
+
+	res, err := v.Move("Plan.md", "Roadmap.md")
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if res.CanvasesUpdated != 1 {
+		t.Fatalf("CanvasesUpdated = %d, want 1 (float coordinates must parse)", res.CanvasesUpdated)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Board.canvas"))
+	got := string(data)
+	for _, want := range []string{
+		`"file": "Roadmap.md"`,
+		`"x": 10.5`, `"y": -20.25`,
+		`"styleAttributes": {`, `"border": "dashed"`,
+		`"background": "bg.png"`, `"backgroundStyle": "cover"`,
+		`"fromEnd": "arrow"`, `"toEnd": "none"`,
+		`"metadata": {`, `"version": "1.0-1.0"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rewritten canvas lost %s:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, `"nodes"`) > strings.Index(got, `"metadata"`) {
+		t.Errorf("top-level key order changed:\n%s", got)
+	}
+}
+
+func TestCanvasAddNode(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "Goals.md"), []byte("# Goals\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Empty.canvas"), nil, 0644)
+
+	first, err := v.CanvasAddNode("Empty", CanvasNodeOptions{Text: "hello"})
+	if err != nil {
+		t.Fatalf("add text: %v", err)
+	}
+	if first.Type != "text" || first.X != 0 || first.Width != 400 || len(first.ID) != 16 {
+		t.Errorf("unexpected first node: %+v", first)
+	}
+
+	second, err := v.CanvasAddNode("Empty", CanvasNodeOptions{Type: "file", Note: "Goals", From: first.ID})
+	if err != nil {
+		t.Fatalf("add file: %v", err)
+	}
+	if second.File != "Goals.md" {
+		t.Errorf("file = %q, want Goals.md", second.File)
+	}
+	if second.X != 400+canvasNodeGap || second.Y != 0 {
+		t.Errorf("auto-placement = (%g, %g), want (%d, 0)", second.X, second.Y, 400+canvasNodeGap)
+	}
+
+	c, _, err := v.CanvasRead("Empty")
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(c.Nodes) != 2 || len(c.Edges) != 1 {
+		t.Fatalf("got %d nodes, %d edges; want 2, 1", len(c.Nodes), len(c.Edges))
+	}
+	if c.Edges[0].FromNode != first.ID || c.Edges[0].ToNode != second.ID {
+		t.Errorf("edge = %+v", c.Edges[0])
+	}
+
+	if _, err := v.CanvasAddNode("Empty", CanvasNodeOptions{Text: "x", From: "nope"}); err == nil {
+		t.Error("expected error for unknown from node")
+	}
+	if _, err := v.CanvasAddNode("Empty", CanvasNodeOptions{Type: "link"}); err == nil {
+		t.Error("expected error for link node without url")
+	}
+	if _, err := v.CanvasAddNode("Missing", CanvasNodeOptions{Text: "x"}); err == nil {
+		t.Error("expected error for missing canvas")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	vlt "github.com/RamXX/vlt"
@@ -28,54 +30,53 @@ func printVaults(vaults map[string]string, format string) {
 	formatVaults(names, vaults, format)
 }
 
-func dispatchRead(v *vlt.Vault, params map[string]string, flags map[string]bool) error {
+func dispatchRead(v *vlt.Vault, params map[string]string, flags map[string]bool, format string) error {
 	title := params["file"]
 	if title == "" {
 		return fmt.Errorf("read requires file=\"<title>\"")
 	}
 	heading := params["heading"]
+	policy, _ := v.IntegrityPolicy()
 
-	if flags["follow"] {
-		result, linked, err := v.ReadFollow(title, heading)
-		if err != nil {
-			return err
-		}
-		warnIntegrity(title, result.Integrity)
-		fmt.Print(result.Content)
-		for _, ln := range linked {
-			fmt.Printf("\n--- [[%s]] (%s) ---\n", ln.Title, ln.Path)
-			fmt.Print(ln.Content)
-		}
-		return nil
+	var result vlt.ReadResult
+	var linked []vlt.LinkedNote
+	var err error
+	switch {
+	case flags["follow"]:
+		result, linked, err = v.ReadFollow(title, heading)
+	case flags["backlinks"]:
+		result, linked, err = v.ReadWithBacklinks(title, heading)
+	default:
+		result, err = v.Read(title, heading)
+	}
+	if err != nil {
+		return err
 	}
 
-	if flags["backlinks"] {
-		result, linked, err := v.ReadWithBacklinks(title, heading)
-		if err != nil {
-			return err
-		}
-		warnIntegrity(title, result.Integrity)
-		fmt.Print(result.Content)
-		for _, ln := range linked {
-			fmt.Printf("\n--- [[%s]] (%s) ---\n", ln.Title, ln.Path)
-			fmt.Print(ln.Content)
-		}
+	if format == "json" {
+		formatReadJSON(title, result, linked, flags["follow"] || flags["backlinks"])
 		return nil
 	}
 
-	result, err := v.Read(title, heading)
-	if err != nil {
-		return err
-	}
-	warnIntegrity(title, result.Integrity)
+	warnIntegrity(policy, title, result.Integrity)
 	fmt.Print(result.Content)
+	for _, ln := range linked {
+		if ln.Integrity == vlt.IntegrityMismatch && policy == vlt.PolicyBlock {
+			fmt.Printf("\n--- [[%s]] (%s) --- withheld: integrity mismatch\n", ln.Title, ln.Path)
+			continue
+		}
+		warnIntegrity(policy, ln.Title, ln.Integrity)
+		fmt.Printf("\n--- [[%s]] (%s) ---\n", ln.Title, ln.Path)
+		fmt.Print(ln.Content)
+	}
 	return nil
 }
 
 // warnIntegrity prints a warning to stderr if integrity is compromised.
-// Stays silent for OK, Untracked, and NoRegistry to avoid noise.
-func warnIntegrity(title string, status vlt.IntegrityStatus) {
-	if status == vlt.IntegrityMismatch {
+// Stays silent for OK, Untracked, and NoRegistry to avoid noise, and for
+// everything under the ignore policy.
+func warnIntegrity(policy vlt.IntegrityPolicy, title string, status vlt.IntegrityStatus) {
+	if status == vlt.IntegrityMismatch && policy != vlt.PolicyIgnore {
 		fmt.Fprintf(os.Stderr, "vlt: INTEGRITY MISMATCH for %q -- file modified outside vlt\n", title)
 	}
 }
@@ -244,6 +245,9 @@ func dispatchMove(v *vlt.Vault, params map[string]string) error {
 	if result.MdLinksUpdated > 0 {
 		fmt.Printf("updated [...](%s) -> [...](%s) in %d file(s)\n", from, to, result.MdLinksUpdated)
 	}
+	if result.CanvasesUpdated > 0 {
+		fmt.Printf("updated references in %d canvas file(s)\n", result.CanvasesUpdated)
+	}
 	return nil
 }
 
@@ -253,11 +257,31 @@ func dispatchDelete(v *vlt.Vault, params map[string]string, permanent bool) erro
 	if title == "" && notePath == "" {
 		return fmt.Errorf("delete requires file=\"<title>\" or path=\"<path>\"")
 	}
-	msg, err := v.Delete(title, notePath, permanent)
+	opts := vlt.DeleteOptions{
+		Permanent:  permanent,
+		Links:      params["links"],
+		RedirectTo: params["redirect"],
+	}
+	if opts.RedirectTo != "" && opts.Links == "" {
+		opts.Links = "redirect"
+	}
+	result, err := v.Delete(title, notePath, opts)
 	if err != nil {
 		return err
 	}
-	fmt.Println(msg)
+	if result.Dest == "" {
+		fmt.Printf("deleted: %s\n", result.Path)
+	} else {
+		fmt.Printf("trashed: %s -> %s\n", result.Path, result.Dest)
+	}
+	switch {
+	case opts.Links == "unlink" && result.LinksUpdated > 0:
+		fmt.Printf("unlinked references in %d file(s)\n", result.LinksUpdated)
+	case opts.Links == "redirect" && result.LinksUpdated > 0:
+		fmt.Printf("redirected references to [[%s]] in %d file(s)\n", opts.RedirectTo, result.LinksUpdated)
+	case len(result.Backlinks) > 0:
+		fmt.Fprintf(os.Stderr, "warning: %d file(s) still link to %s\n", len(result.Backlinks), result.Path)
+	}
 	return nil
 }
 
@@ -293,12 +317,16 @@ func dispatchProperties(v *vlt.Vault, params map[string]string, format string) e
 	if title == "" {
 		return fmt.Errorf("properties requires file=\"<title>\"")
 	}
-	fm, err := v.Properties(title)
+	result, err := v.Properties(title)
 	if err != nil {
 		return err
 	}
-	if fm != "" {
-		formatProperties(fm, format)
+	if format != "json" {
+		policy, _ := v.IntegrityPolicy()
+		warnIntegrity(policy, title, result.Integrity)
+	}
+	if result.Content != "" || format == "json" {
+		formatProperties(result.Content, result.Integrity, format)
 	}
 	return nil
 }
@@ -349,7 +377,161 @@ func dispatchUnresolved(v *vlt.Vault, format string) error {
 	return nil
 }
 
+func dispatchAttachmentsUnused(v *vlt.Vault, format string) error {
+	unused, err := v.UnusedAttachments()
+	if err != nil {
+		return err
+	}
+	if len(unused) > 0 || format == "json" {
+		formatAttachments(unused, format)
+	}
+	return nil
+}
+
+func dispatchAttachmentsClean(v *vlt.Vault) error {
+	trashed, err := v.CleanAttachments()
+	var total int64
+	for _, a := range trashed {
+		total += a.Size
+		fmt.Printf("trashed: %s\n", a.Path)
+	}
+	if err != nil {
+		return err
+	}
+	if len(trashed) == 0 {
+		fmt.Println("no unused attachments")
+		return nil
+	}
+	fmt.Printf("trashed %d attachment(s), %s\n", len(trashed), formatBytes(total))
+	return nil
+}
+
+func dispatchTrash(v *vlt.Vault, format string) error {
+	entries, err := v.TrashList()
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 || format == "json" {
+		formatTrash(entries, format)
+	}
+	return nil
+}
+
+func dispatchTrashRestore(v *vlt.Vault, params map[string]string) error {
+	name := params["path"]
+	if name == "" {
+		name = params["file"]
+	}
+	if name == "" {
+		return fmt.Errorf("trash:restore requires path=\"<trash or original path>\" or file=\"<title>\"")
+	}
+	entry, restored, err := v.TrashRestore(name, params["to"])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored: .trash/%s -> %s\n", entry.Path, restored)
+	return nil
+}
+
+func dispatchTrashEmpty(v *vlt.Vault, params map[string]string) error {
+	var olderThan time.Duration
+	if s := params["older-than"]; s != "" {
+		d, err := parseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid older-than duration: %v", err)
+		}
+		olderThan = d
+	}
+	purged, err := v.TrashEmpty(olderThan)
+	var total int64
+	for _, e := range purged {
+		total += e.Size
+		fmt.Printf("deleted: .trash/%s\n", e.Path)
+	}
+	if err != nil {
+		return err
+	}
+	if len(purged) == 0 {
+		fmt.Println("nothing to delete")
+		return nil
+	}
+	fmt.Printf("deleted %d file(s) from trash, %s\n", len(purged), formatBytes(total))
+	return nil
+}
+
+func dispatchCanvasRead(v *vlt.Vault, params map[string]string, format string) error {
+	name := params["file"]
+	if name == "" {
+		return fmt.Errorf("canvas:read requires file=\"<canvas>\"")
+	}
+	c, status, err := v.CanvasRead(name)
+	if err != nil {
+		return err
+	}
+	if format != "json" {
+		policy, _ := v.IntegrityPolicy()
+		warnIntegrity(policy, name, status)
+	}
+	formatCanvas(c, status, format)
+	return nil
+}
+
+func dispatchCanvasAddNode(v *vlt.Vault, params map[string]string) error {
+	name := params["file"]
+	if name == "" {
+		return fmt.Errorf("canvas:add-node requires file=\"<canvas>\"")
+	}
+
+	opts := vlt.CanvasNodeOptions{
+		Type:  params["type"],
+		Text:  params["text"],
+		Note:  params["note"],
+		URL:   params["url"],
+		Label: params["label"],
+		Color: params["color"],
+		From:  params["from"],
+	}
+	nums := map[string]*float64{}
+	for _, key := range []string{"x", "y", "width", "height"} {
+		s, ok := params[key]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: must be a number", key, s)
+		}
+		nums[key] = &n
+	}
+	if (nums["x"] == nil) != (nums["y"] == nil) {
+		return fmt.Errorf("canvas:add-node requires both x= and y= when positioning a node")
+	}
+	opts.X, opts.Y = nums["x"], nums["y"]
+	if w := nums["width"]; w != nil {
+		opts.Width = *w
+	}
+	if h := nums["height"]; h != nil {
+		opts.Height = *h
+	}
+
+	node, err := v.CanvasAddNode(name, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("added %s node %s at (%g, %g)\n", node.Type, node.ID, node.X, node.Y)
+	return nil
+}
+
 func dispatchTags(v *vlt.Vault, params map[string]string, showCounts bool, format string) error {
+	if format == "tree" {
+		counts, err := v.TagRollup()
+		if err != nil {
+			return err
+		}
+		renderTagTree(counts)
+		return nil
+	}
+
 	tags, counts, err := v.Tags(params["sort"])
 	if err != nil {
 		return err
@@ -382,6 +564,108 @@ func dispatchTag(v *vlt.Vault, params map[string]string, format string) error {
 	return nil
 }
 
+func dispatchTagsRelated(v *vlt.Vault, params map[string]string, format string) error {
+	tag := params["tag"]
+	if tag == "" {
+		return fmt.Errorf("tags:related requires tag=\"<tagname>\"")
+	}
+	related, counts, err := v.RelatedTags(tag)
+	if err != nil {
+		return err
+	}
+	if len(related) > 0 || format == "json" {
+		formatTagCounts(related, counts, format)
+	}
+	return nil
+}
+
+func dispatchTagRename(v *vlt.Vault, params map[string]string, format string) error {
+	from, to := params["from"], params["to"]
+	if from == "" || to == "" {
+		return fmt.Errorf("tag:rename requires from=\"<tag>\" to=\"<tag>\"")
+	}
+	results, err := v.TagRename(from, to)
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		formatTagRenames(results, format)
+		return nil
+	}
+	total := 0
+	for _, r := range results {
+		total += r.Count
+		fmt.Printf("%s\t%d\n", r.Path, r.Count)
+	}
+	fmt.Printf("renamed #%s -> #%s: %d occurrence(s) in %d file(s)\n",
+		strings.TrimPrefix(from, "#"), strings.TrimPrefix(to, "#"), total, len(results))
+	return nil
+}
+
+func dispatchTagAdd(v *vlt.Vault, params map[string]string) error {
+	title, tag := params["file"], params["tag"]
+	if title == "" || tag == "" {
+		return fmt.Errorf("tag:add requires file=\"<title>\" tag=\"<tag>\"")
+	}
+	added, err := v.TagAdd(title, tag)
+	if err != nil {
+		return err
+	}
+	tag = strings.TrimPrefix(tag, "#")
+	if !added {
+		fmt.Printf("already tagged: %s #%s\n", title, tag)
+		return nil
+	}
+	fmt.Printf("tagged: %s #%s\n", title, tag)
+	return nil
+}
+
+func dispatchTagRemove(v *vlt.Vault, params map[string]string, inline bool) error {
+	title, tag := params["file"], params["tag"]
+	if title == "" || tag == "" {
+		return fmt.Errorf("tag:remove requires file=\"<title>\" tag=\"<tag>\"")
+	}
+	removed, err := v.TagRemove(title, tag, inline)
+	if err != nil {
+		return err
+	}
+	tag = strings.TrimPrefix(tag, "#")
+	if removed == 0 {
+		fmt.Printf("not tagged: %s #%s\n", title, tag)
+		return nil
+	}
+	fmt.Printf("untagged: %s #%s (%d occurrence(s))\n", title, tag, removed)
+	return nil
+}
+
+func dispatchLintTags(v *vlt.Vault, format string) error {
+	violations, err := v.LintTags()
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 || format == "json" {
+		formatLintViolations(violations, format)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d tag violation(s)", len(violations))
+	}
+	return nil
+}
+
+func dispatchValidate(v *vlt.Vault, params map[string]string, format string) error {
+	violations, err := v.Validate(params["file"])
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 || format == "json" {
+		formatSchemaViolations(violations, format)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d schema violation(s)", len(violations))
+	}
+	return nil
+}
+
 func dispatchFiles(v *vlt.Vault, params map[string]string, showTotal bool, format string) error {
 	files, err := v.Files(params["folder"], params["ext"])
 	if err != nil {
@@ -398,10 +682,17 @@ func dispatchFiles(v *vlt.Vault, params map[string]string, showTotal bool, forma
 func dispatchTasks(v *vlt.Vault, params map[string]string, flags map[string]bool) error {
 	format := outputFormat(flags)
 	tasks, err := v.Tasks(vlt.TaskOptions{
-		File:    params["file"],
-		Path:    params["path"],
-		Done:    flags["done"],
-		Pending: flags["pending"],
+		File:      params["file"],
+		Path:      params["path"],
+		Done:      flags["done"],
+		Pending:   flags["pending"],
+		Status:    splitList(params["status"]),
+		Priority:  splitList(params["priority"]),
+		Due:       params["due"],
+		Scheduled: params["scheduled"],
+		Overdue:   flags["overdue"],
+		Sort:      params["sort"],
+		Section:   params["section"],
 	})
 	if err != nil {
 		return err
@@ -410,8 +701,86 @@ func dispatchTasks(v *vlt.Vault, params map[string]string, flags map[string]bool
 	return nil
 }
 
-func dispatchDaily(v *vlt.Vault, params map[string]string) error {
-	result, err := v.Daily(params["date"])
+// taskRef builds a task address from line= or match= parameters.
+func taskRef(params map[string]string) (vlt.TaskRef, error) {
+	ref := vlt.TaskRef{Match: params["match"]}
+	if s := params["line"]; s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return ref, fmt.Errorf("invalid line %q", s)
+		}
+		ref.Line = n
+	}
+	return ref, nil
+}
+
+// taskUpdate builds a task update from due=, scheduled=, start= and
+// priority= parameters.
+func taskUpdate(params map[string]string) vlt.TaskUpdate {
+	return vlt.TaskUpdate{
+		Due:       params["due"],
+		Scheduled: params["scheduled"],
+		Start:     params["start"],
+		Priority:  params["priority"],
+	}
+}
+
+func dispatchTaskEdit(v *vlt.Vault, params map[string]string, action string, timestamps bool, format string) error {
+	title := params["file"]
+	if title == "" {
+		return fmt.Errorf("task:%s requires file=\"<title>\"", action)
+	}
+	ref, err := taskRef(params)
+	if err != nil {
+		return err
+	}
+
+	var result vlt.TaskEditResult
+	switch action {
+	case "done":
+		result, err = v.TaskDone(title, ref, timestamps)
+	case "undo":
+		result, err = v.TaskUndo(title, ref, timestamps)
+	case "set":
+		result, err = v.TaskSet(title, ref, taskUpdate(params), timestamps)
+	}
+	if err != nil {
+		return err
+	}
+	formatTaskEdit(result, format)
+	return nil
+}
+
+func dispatchTaskAdd(v *vlt.Vault, params map[string]string, timestamps bool, format string) error {
+	title := params["file"]
+	if title == "" {
+		return fmt.Errorf("task:add requires file=\"<title>\"")
+	}
+	result, err := v.TaskAdd(title, params["heading"], params["text"], taskUpdate(params), timestamps)
+	if err != nil {
+		return err
+	}
+	formatTaskEdit(result, format)
+	return nil
+}
+
+func dispatchPeriodic(v *vlt.Vault, period string, params map[string]string, flags map[string]bool) error {
+	offset := 0
+	if s := params["offset"]; s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid offset %q: expected an integer like -1 or 2", s)
+		}
+		offset = n
+	}
+	if flags["previous"] {
+		offset--
+	}
+	if flags["next"] {
+		offset++
+	}
+
+	result, err := v.Periodic(period, params["date"], offset)
 	if err != nil {
 		return err
 	}
@@ -432,6 +801,75 @@ func dispatchTemplates(v *vlt.Vault, params map[string]string, format string) er
 	return nil
 }
 
+func dispatchTemplatesInsert(v *vlt.Vault, params map[string]string, timestamps bool) error {
+	templateName := params["template"]
+	title := params["file"]
+	if templateName == "" || title == "" {
+		return fmt.Errorf("templates:insert requires template=\"<name>\" file=\"<title>\"")
+	}
+
+	opts := vlt.TemplateInsertOptions{
+		Heading:    params["heading"],
+		Position:   params["position"],
+		Timestamps: timestamps,
+	}
+	if s := params["line"]; s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid line %q", s)
+		}
+		opts.Line = n
+	}
+	vars, err := templateVars(params)
+	if err != nil {
+		return err
+	}
+	opts.Vars = vars
+
+	if err := v.TemplatesInsert(templateName, title, opts); err != nil {
+		return err
+	}
+	fmt.Printf("inserted: template %q into %q\n", templateName, title)
+	return nil
+}
+
+// templateVars collects template variables from a JSON vars= file and
+// var.<name>= parameters, which take precedence.
+func templateVars(params map[string]string) (map[string]string, error) {
+	vars := make(map[string]string)
+	if file := params["vars"]; file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("vars file: %w", err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("vars file %s: %w", file, err)
+		}
+		for name, value := range raw {
+			switch val := value.(type) {
+			case string:
+				vars[name] = val
+			case []any:
+				items := make([]string, len(val))
+				for i, item := range val {
+					items[i] = fmt.Sprint(item)
+				}
+				vars[name] = strings.Join(items, ", ")
+			case nil:
+			default:
+				vars[name] = fmt.Sprint(val)
+			}
+		}
+	}
+	for key, value := range params {
+		if name, ok := strings.CutPrefix(key, "var."); ok && name != "" {
+			vars[name] = value
+		}
+	}
+	return vars, nil
+}
+
 func dispatchTemplatesApply(v *vlt.Vault, params map[string]string) error {
 	templateName := params["template"]
 	noteName := params["name"]
@@ -444,7 +882,11 @@ func dispatchTemplatesApply(v *vlt.Vault, params map[string]string) error {
 		return fmt.Errorf("templates:apply requires name=\"<title>\" path=\"<path>\"")
 	}
 
-	if err := v.TemplatesApply(templateName, noteName, notePath); err != nil {
+	vars, err := templateVars(params)
+	if err != nil {
+		return err
+	}
+	if err := v.TemplatesApply(templateName, noteName, notePath, vars); err != nil {
 		return err
 	}
 	fmt.Printf("created: %s (from template %q)\n", notePath, templateName)
@@ -452,6 +894,14 @@ func dispatchTemplatesApply(v *vlt.Vault, params map[string]string) error {
 }
 
 func dispatchBookmarks(v *vlt.Vault, format string) error {
+	if format == "tree" {
+		entries, err := v.BookmarksTree()
+		if err != nil {
+			return err
+		}
+		renderBookmarkTree(entries)
+		return nil
+	}
 	paths, err := v.Bookmarks()
 	if err != nil {
 		return err
@@ -460,12 +910,30 @@ func dispatchBookmarks(v *vlt.Vault, format string) error {
 	return nil
 }
 
+// bookmarkSpec builds a bookmark description from file=, heading=,
+// block=, folder=, query=, title= and group= params.
+func bookmarkSpec(cmd string, params map[string]string) (vlt.BookmarkSpec, error) {
+	spec := vlt.BookmarkSpec{
+		File:    params["file"],
+		Heading: params["heading"],
+		Block:   params["block"],
+		Folder:  params["folder"],
+		Query:   params["query"],
+		Title:   params["title"],
+		Group:   params["group"],
+	}
+	if spec.File == "" && spec.Folder == "" && spec.Query == "" {
+		return spec, fmt.Errorf("%s requires file=\"<title>\" (optionally with heading= or block=), folder=\"<path>\" or query=\"<search>\"", cmd)
+	}
+	return spec, nil
+}
+
 func dispatchBookmarksAdd(v *vlt.Vault, params map[string]string) error {
-	title := params["file"]
-	if title == "" {
-		return fmt.Errorf("bookmarks:add requires file=\"<title>\"")
+	spec, err := bookmarkSpec("bookmarks:add", params)
+	if err != nil {
+		return err
 	}
-	msg, err := v.BookmarksAdd(title)
+	msg, err := v.BookmarksAdd(spec)
 	if err != nil {
 		return err
 	}
@@ -474,19 +942,66 @@ func dispatchBookmarksAdd(v *vlt.Vault, params map[string]string) error {
 }
 
 func dispatchBookmarksRemove(v *vlt.Vault, params map[string]string) error {
-	title := params["file"]
-	if title == "" {
-		return fmt.Errorf("bookmarks:remove requires file=\"<title>\"")
+	spec, err := bookmarkSpec("bookmarks:remove", params)
+	if err != nil {
+		return err
+	}
+	if err := v.BookmarksRemove(spec); err != nil {
+		return err
+	}
+	switch {
+	case spec.Query != "":
+		fmt.Printf("unbookmarked: search %q\n", spec.Query)
+	case spec.Folder != "":
+		fmt.Printf("unbookmarked: %s/\n", strings.TrimSuffix(spec.Folder, "/"))
+	case spec.Heading != "":
+		fmt.Printf("unbookmarked: %s#%s\n", spec.File, strings.TrimLeft(spec.Heading, "# "))
+	case spec.Block != "":
+		fmt.Printf("unbookmarked: %s#^%s\n", spec.File, strings.TrimPrefix(spec.Block, "^"))
+	default:
+		fmt.Printf("unbookmarked: %s\n", spec.File)
+	}
+	return nil
+}
+
+func dispatchBookmarksAddGroup(v *vlt.Vault, params map[string]string) error {
+	group := params["group"]
+	if group == "" {
+		return fmt.Errorf("bookmarks:add-group requires group=\"<name>\"")
+	}
+	if err := v.BookmarksCreateGroup(group); err != nil {
+		return err
+	}
+	fmt.Printf("created group: %s\n", group)
+	return nil
+}
+
+func dispatchBookmarksRenameGroup(v *vlt.Vault, params map[string]string) error {
+	group, to := params["group"], params["to"]
+	if group == "" || to == "" {
+		return fmt.Errorf("bookmarks:rename-group requires group=\"<name>\" and to=\"<new name>\"")
+	}
+	if err := v.BookmarksRenameGroup(group, to); err != nil {
+		return err
+	}
+	fmt.Printf("renamed group: %s -> %s\n", group, to)
+	return nil
+}
+
+func dispatchBookmarksDeleteGroup(v *vlt.Vault, params map[string]string, flags map[string]bool) error {
+	group := params["group"]
+	if group == "" {
+		return fmt.Errorf("bookmarks:delete-group requires group=\"<name>\"")
 	}
-	if err := v.BookmarksRemove(title); err != nil {
+	if err := v.BookmarksDeleteGroup(group, flags["force"]); err != nil {
 		return err
 	}
-	fmt.Printf("unbookmarked: %s\n", title)
+	fmt.Printf("deleted group: %s\n", group)
 	return nil
 }
 
-func dispatchIntegrityBaseline(v *vlt.Vault) error {
-	if err := v.IntegrityBaseline(); err != nil {
+func dispatchIntegrityBaseline(v *vlt.Vault, flags map[string]bool) error {
+	if err := v.IntegrityBaseline(flags["force"]); err != nil {
 		return err
 	}
 	fmt.Println("integrity baseline registered for all vault files")
@@ -562,6 +1077,107 @@ func dispatchIntegrityStatus(v *vlt.Vault, format string) error {
 	return nil
 }
 
+func dispatchIntegrityAudit(v *vlt.Vault, format string) error {
+	report, err := v.IntegrityAudit()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else if len(report.Problems) == 0 {
+		signed := "unsigned"
+		if report.Signed {
+			signed = "signatures valid"
+		}
+		fmt.Printf("audit log intact: %d record(s), %s, registry consistent\n", report.Records, signed)
+	} else {
+		for _, p := range report.Problems {
+			fmt.Println(p)
+		}
+		fmt.Printf("\n%d record(s), %d problem(s)\n", report.Records, len(report.Problems))
+	}
+	if len(report.Problems) > 0 {
+		return fmt.Errorf("integrity audit failed: %d problem(s)", len(report.Problems))
+	}
+	return nil
+}
+
+func dispatchIntegrityDiff(v *vlt.Vault, params map[string]string) error {
+	title := params["file"]
+	if title == "" {
+		return fmt.Errorf("integrity:diff requires file=\"<title>\"")
+	}
+	diff, err := v.IntegrityDiff(title)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Printf("no changes: %s matches the registered version\n", title)
+		return nil
+	}
+	fmt.Print(diff)
+	return nil
+}
+
+func dispatchIntegrityRestore(v *vlt.Vault, params map[string]string) error {
+	title := params["file"]
+	if title == "" {
+		return fmt.Errorf("integrity:restore requires file=\"<title>\"")
+	}
+	if err := v.IntegrityRestore(title); err != nil {
+		return err
+	}
+	fmt.Printf("restored: %s\n", title)
+	return nil
+}
+
+func dispatchIntegrityPolicy(v *vlt.Vault, params map[string]string) error {
+	if set := params["set"]; set != "" {
+		p, err := vlt.ParseIntegrityPolicy(set)
+		if err != nil {
+			return err
+		}
+		if err := v.SetIntegrityPolicy(p); err != nil {
+			return err
+		}
+	}
+	policy, err := v.IntegrityPolicy()
+	if err != nil {
+		return err
+	}
+	if os.Getenv("VLT_INTEGRITY") != "" {
+		fmt.Printf("integrity policy: %s (from VLT_INTEGRITY)\n", policy)
+	} else {
+		fmt.Printf("integrity policy: %s\n", policy)
+	}
+	return nil
+}
+
+func dispatchIntegrityMigrate(v *vlt.Vault, params map[string]string, flags map[string]bool) error {
+	from, to := params["from"], params["to"]
+	var dir string
+	var err error
+	switch {
+	case from != "" && to != "":
+		return fmt.Errorf("integrity:migrate takes either from= or to=, not both")
+	case from != "":
+		dir, err = v.IntegrityMigrate(from, flags["force"])
+	case to == "vault" || to == "external":
+		dir, err = v.IntegrityMoveRegistry(to == "vault", flags["force"])
+	case to != "":
+		return fmt.Errorf("invalid to=%q (use vault or external)", to)
+	default:
+		return fmt.Errorf("integrity:migrate requires from=\"<old vault path or ID>\" or to=vault|external")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("registry migrated to %s\n", dir)
+	return nil
+}
+
 func formatIntegrityStatusJSON(statuses map[string]vlt.IntegrityStatus) {
 	type jsonEntry struct {
 		Path   string `json:"path"`
@@ -576,10 +1192,84 @@ func formatIntegrityStatusJSON(statuses map[string]vlt.IntegrityStatus) {
 	fmt.Println(string(data))
 }
 
+// lockOptions builds the vault lock options for cmd from lock-timeout=
+// and --no-wait.
+func lockOptions(cmd string, params map[string]string, flags map[string]bool) (vlt.LockOptions, error) {
+	opts := vlt.LockOptions{
+		Exclusive: vlt.IsWriteCommand(cmd),
+		NoWait:    flags["--no-wait"],
+		Command:   cmd,
+	}
+	if s := params["lock-timeout"]; s != "" {
+		d, err := parseDuration(s)
+		if err != nil {
+			return opts, fmt.Errorf("invalid lock-timeout %q (use a positive duration like \"5s\")", s)
+		}
+		opts.Timeout = d
+	}
+	return opts, nil
+}
+
+func dispatchLockStatus(v *vlt.Vault, format string) error {
+	status, err := vlt.VaultLockStatus(v.Dir())
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	h := status.Holder
+	switch {
+	case !status.Locked && h == nil:
+		fmt.Println("vault is not locked")
+	case !status.Locked:
+		fmt.Printf("vault is not locked (stale record: pid %d (%s) exited without releasing it)\n", h.PID, h.Command)
+	case h == nil:
+		fmt.Println("vault is locked (holder not recorded: a reader using --strict-flock, an older vlt or another tool)")
+	default:
+		fmt.Printf("vault is locked by pid %d\n", h.PID)
+		fmt.Printf("  command: %s\n", h.Command)
+		fmt.Printf("  started: %s (%s ago)\n", h.Started.Format(time.RFC3339), time.Since(h.Started).Truncate(time.Second))
+		if status.Alive {
+			fmt.Println("  process: running")
+		} else {
+			fmt.Println("  process: not running (a child process may have inherited the lock)")
+		}
+	}
+	return nil
+}
+
 // parseDuration parses a human-friendly duration string.
-// Supports Go's time.ParseDuration format (e.g., "1h", "30m", "2h30m").
+// Supports Go's time.ParseDuration format (e.g., "1h", "30m", "2h30m") plus
+// whole days and weeks ("7d", "2w"). Durations must be positive.
 func parseDuration(s string) (time.Duration, error) {
-	return time.ParseDuration(s)
+	d, err := time.ParseDuration(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, convErr := strconv.Atoi(strings.TrimSuffix(s, suffix)); convErr == nil && strings.HasSuffix(s, suffix) {
+			d, err = time.Duration(n)*unit, nil
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration %q must be positive", s)
+	}
+	return d, nil
+}
+
+// splitList splits a comma-separated parameter value, dropping blanks.
+func splitList(s string) []string {
+	var result []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
 }
 
 func dispatchURI(v *vlt.Vault, vaultName string, params map[string]string) error {
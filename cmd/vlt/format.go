@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	vlt "github.com/RamXX/vlt"
 )
@@ -152,6 +154,94 @@ func formatTagCounts(tags []string, counts map[string]int, format string) {
 	}
 }
 
+// formatTagRenames outputs per-file tag rename counts in the requested format.
+func formatTagRenames(results []vlt.TagRenameResult, format string) {
+	switch format {
+	case "json":
+		if results == nil {
+			results = []vlt.TagRenameResult{}
+		}
+		data, _ := json.Marshal(results)
+		fmt.Println(string(data))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"path", "count"})
+		for _, r := range results {
+			w.Write([]string{r.Path, fmt.Sprintf("%d", r.Count)})
+		}
+		w.Flush()
+	case "tsv":
+		fmt.Println("path\tcount")
+		for _, r := range results {
+			fmt.Printf("%s\t%d\n", r.Path, r.Count)
+		}
+	case "yaml":
+		for _, r := range results {
+			fmt.Printf("- path: %s\n  count: %d\n", yamlEscapeValue(r.Path), r.Count)
+		}
+	case "tree":
+		paths := make([]string, len(results))
+		for i, r := range results {
+			paths[i] = r.Path
+		}
+		renderTree(paths)
+	default:
+		for _, r := range results {
+			fmt.Printf("%s\t%d\n", r.Path, r.Count)
+		}
+	}
+}
+
+// formatLintViolations outputs lint violations in the requested format.
+// Plain text uses the compiler-style "path:line: message" layout.
+func formatLintViolations(violations []vlt.LintViolation, format string) {
+	switch format {
+	case "json":
+		if violations == nil {
+			violations = []vlt.LintViolation{}
+		}
+		data, _ := json.Marshal(violations)
+		fmt.Println(string(data))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"path", "line", "tag", "rule", "message"})
+		for _, lv := range violations {
+			w.Write([]string{lv.Path, fmt.Sprintf("%d", lv.Line), lv.Tag, lv.Rule, lv.Message})
+		}
+		w.Flush()
+	case "tsv":
+		fmt.Println("path\tline\ttag\trule\tmessage")
+		for _, lv := range violations {
+			fmt.Printf("%s\t%d\t%s\t%s\t%s\n", lv.Path, lv.Line, lv.Tag, lv.Rule, lv.Message)
+		}
+	case "yaml":
+		for _, lv := range violations {
+			fmt.Printf("- path: %s\n  line: %d\n  tag: %s\n  rule: %s\n  message: %s\n",
+				yamlEscapeValue(lv.Path), lv.Line, yamlEscapeValue(lv.Tag), lv.Rule, yamlEscapeValue(lv.Message))
+		}
+	default:
+		for _, lv := range violations {
+			fmt.Printf("%s:%d: %s\n", lv.Path, lv.Line, lv.Message)
+		}
+	}
+}
+
+// formatSchemaViolations outputs frontmatter schema violations as a table.
+func formatSchemaViolations(violations []vlt.SchemaViolation, format string) {
+	rows := make([]map[string]string, len(violations))
+	for i, sv := range violations {
+		rows[i] = map[string]string{
+			"path":    sv.Path,
+			"line":    fmt.Sprintf("%d", sv.Line),
+			"schema":  sv.Schema,
+			"key":     sv.Key,
+			"rule":    sv.Rule,
+			"message": sv.Message,
+		}
+	}
+	formatTable(rows, []string{"path", "line", "schema", "key", "rule", "message"}, format)
+}
+
 // formatVaults outputs vault name-path pairs in the requested format.
 func formatVaults(names []string, vaults map[string]string, format string) {
 	switch format {
@@ -194,12 +284,13 @@ func formatSearchResults(results []vlt.SearchResult, format string) {
 	switch format {
 	case "json":
 		type jsonResult struct {
-			Title string `json:"title"`
-			Path  string `json:"path"`
+			Title     string              `json:"title"`
+			Path      string              `json:"path"`
+			Integrity vlt.IntegrityStatus `json:"integrity"`
 		}
 		entries := make([]jsonResult, len(results))
 		for i, r := range results {
-			entries[i] = jsonResult{Title: r.Title, Path: r.RelPath}
+			entries[i] = jsonResult{Title: r.Title, Path: r.RelPath, Integrity: r.Integrity}
 		}
 		data, _ := json.Marshal(entries)
 		fmt.Println(string(data))
@@ -231,10 +322,11 @@ func formatSearchWithContext(matches []vlt.ContextMatch, format string) {
 	switch format {
 	case "json":
 		type jsonContextMatch struct {
-			File    string   `json:"file"`
-			Line    int      `json:"line"`
-			Match   string   `json:"match"`
-			Context []string `json:"context"`
+			File      string              `json:"file"`
+			Line      int                 `json:"line"`
+			Match     string              `json:"match"`
+			Context   []string            `json:"context"`
+			Integrity vlt.IntegrityStatus `json:"integrity"`
 		}
 		entries := make([]jsonContextMatch, len(matches))
 		for i, m := range matches {
@@ -243,10 +335,11 @@ func formatSearchWithContext(matches []vlt.ContextMatch, format string) {
 				ctx = []string{}
 			}
 			entries[i] = jsonContextMatch{
-				File:    m.File,
-				Line:    m.Line,
-				Match:   m.Match,
-				Context: ctx,
+				File:      m.File,
+				Line:      m.Line,
+				Match:     m.Match,
+				Context:   ctx,
+				Integrity: m.Integrity,
 			}
 		}
 		data, _ := json.Marshal(entries)
@@ -418,8 +511,150 @@ func formatUnresolved(results []vlt.UnresolvedLink, format string) {
 	}
 }
 
+// formatAttachments outputs attachment path-size pairs in the requested format.
+// Plain text shows human-readable sizes; structured formats use raw bytes.
+func formatAttachments(items []vlt.AttachmentInfo, format string) {
+	switch format {
+	case "json":
+		if items == nil {
+			items = []vlt.AttachmentInfo{}
+		}
+		data, _ := json.Marshal(items)
+		fmt.Println(string(data))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"path", "size"})
+		for _, a := range items {
+			w.Write([]string{a.Path, fmt.Sprintf("%d", a.Size)})
+		}
+		w.Flush()
+	case "tsv":
+		fmt.Println("path\tsize")
+		for _, a := range items {
+			fmt.Printf("%s\t%d\n", a.Path, a.Size)
+		}
+	case "yaml":
+		for _, a := range items {
+			fmt.Printf("- path: %s\n  size: %d\n", yamlEscapeValue(a.Path), a.Size)
+		}
+	case "tree":
+		paths := make([]string, len(items))
+		for i, a := range items {
+			paths[i] = a.Path
+		}
+		renderTree(paths)
+	default:
+		for _, a := range items {
+			fmt.Printf("%s\t%s\n", a.Path, formatBytes(a.Size))
+		}
+	}
+}
+
+// formatTrash outputs trash entries in the requested format. Plain text shows
+// the trash path, original location, deletion time and human-readable size.
+func formatTrash(entries []vlt.TrashEntry, format string) {
+	switch format {
+	case "json":
+		if entries == nil {
+			entries = []vlt.TrashEntry{}
+		}
+		data, _ := json.Marshal(entries)
+		fmt.Println(string(data))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"path", "original", "deleted", "size"})
+		for _, e := range entries {
+			w.Write([]string{e.Path, e.Original, e.Deleted.Format(time.RFC3339), fmt.Sprintf("%d", e.Size)})
+		}
+		w.Flush()
+	case "tsv":
+		fmt.Println("path\toriginal\tdeleted\tsize")
+		for _, e := range entries {
+			fmt.Printf("%s\t%s\t%s\t%d\n", e.Path, e.Original, e.Deleted.Format(time.RFC3339), e.Size)
+		}
+	case "yaml":
+		for _, e := range entries {
+			fmt.Printf("- path: %s\n  original: %s\n  deleted: %s\n  size: %d\n",
+				yamlEscapeValue(e.Path), yamlEscapeValue(e.Original), e.Deleted.Format(time.RFC3339), e.Size)
+		}
+	case "tree":
+		paths := make([]string, len(entries))
+		for i, e := range entries {
+			paths[i] = e.Path
+		}
+		renderTree(paths)
+	default:
+		for _, e := range entries {
+			fmt.Printf("%s\t%s\t%s\t%s\n", e.Path, e.Original, e.Deleted.Local().Format("2006-01-02 15:04"), formatBytes(e.Size))
+		}
+	}
+}
+
+// formatCanvas outputs a canvas in the requested format. JSON emits the full
+// canvas (nodes and edges) under "canvas" with its "integrity"; other formats list one row per node with its
+// content: text, file path, URL or group label depending on the node type.
+func formatCanvas(c vlt.Canvas, integrity vlt.IntegrityStatus, format string) {
+	if format == "json" {
+		if c.Nodes == nil {
+			c.Nodes = []vlt.CanvasNode{}
+		}
+		if c.Edges == nil {
+			c.Edges = []vlt.CanvasEdge{}
+		}
+		data, _ := json.Marshal(struct {
+			Canvas    vlt.Canvas          `json:"canvas"`
+			Integrity vlt.IntegrityStatus `json:"integrity"`
+		}{c, integrity})
+		fmt.Println(string(data))
+		return
+	}
+
+	rows := make([]map[string]string, len(c.Nodes))
+	for i, n := range c.Nodes {
+		content := n.Text
+		switch n.Type {
+		case "file":
+			content = n.File + n.Subpath
+		case "link":
+			content = n.URL
+		case "group":
+			content = n.Label
+		}
+		if format == "" {
+			content = strings.ReplaceAll(content, "\n", " ")
+		}
+		rows[i] = map[string]string{
+			"id":      n.ID,
+			"type":    n.Type,
+			"content": content,
+			"x":       strconv.FormatFloat(n.X, 'f', -1, 64),
+			"y":       strconv.FormatFloat(n.Y, 'f', -1, 64),
+		}
+	}
+	fields := []string{"id", "type", "content", "x", "y"}
+	if format == "" {
+		fields = fields[:3]
+	}
+	formatTable(rows, fields, format)
+}
+
+// formatBytes renders a byte count with a binary unit suffix (e.g., "1.5 MB").
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 // formatProperties outputs frontmatter properties in the requested format.
-func formatProperties(text string, format string) {
+// JSON nests them under "properties" next to the note's "integrity".
+func formatProperties(text string, integrity vlt.IntegrityStatus, format string) {
 	if format == "" {
 		fmt.Println(text)
 		return
@@ -445,7 +680,10 @@ func formatProperties(text string, format string) {
 
 	switch format {
 	case "json":
-		data, _ := json.Marshal(props)
+		data, _ := json.Marshal(struct {
+			Properties map[string]string   `json:"properties"`
+			Integrity  vlt.IntegrityStatus `json:"integrity"`
+		}{props, integrity})
 		fmt.Println(string(data))
 	case "csv":
 		w := csv.NewWriter(os.Stdout)
@@ -473,33 +711,79 @@ func outputTasks(tasks []vlt.Task, format string) {
 		data, _ := json.Marshal(tasks)
 		fmt.Println(string(data))
 	case "csv":
-		fmt.Println("done,text,line,file")
+		fmt.Println("done,text,line,file,status,priority,due,scheduled")
 		for _, t := range tasks {
 			done := "false"
 			if t.Done {
 				done = "true"
 			}
-			fmt.Printf("%s,%q,%d,%s\n", done, t.Text, t.Line, t.File)
+			fmt.Printf("%s,%q,%d,%s,%s,%s,%s,%s\n", done, t.Text, t.Line, t.File, t.Status, t.Priority, t.Due, t.Scheduled)
 		}
 	case "yaml":
 		for _, t := range tasks {
-			fmt.Printf("- text: %s\n  done: %v\n  line: %d\n  file: %s\n", yamlEscapeValue(t.Text), t.Done, t.Line, t.File)
+			fmt.Printf("- text: %s\n  done: %v\n  line: %d\n  file: %s\n  status: %s\n", yamlEscapeValue(t.Text), t.Done, t.Line, t.File, t.Status)
+			for _, f := range [][2]string{{"priority", t.Priority}, {"due", t.Due}, {"scheduled", t.Scheduled}, {"start", t.Start}, {"done_date", t.DoneDate}, {"recurrence", t.Recurrence}} {
+				if f[1] != "" {
+					fmt.Printf("  %s: %s\n", f[0], yamlEscapeValue(f[1]))
+				}
+			}
 		}
+	case "tree":
+		renderTaskTree(tasks)
 	default:
 		for _, t := range tasks {
-			check := " "
-			if t.Done {
-				check = "x"
-			}
-			fmt.Printf("- [%s] %s (%s:%d)\n", check, t.Text, t.File, t.Line)
+			fmt.Printf("- [%s] %s (%s:%d)\n", t.Symbol, t.Text, t.File, t.Line)
+		}
+	}
+}
+
+// formatTaskEdit outputs the task line written by a task command. Plain
+// text shows "path:line: task", preceded by the next occurrence of a
+// recurring task.
+func formatTaskEdit(r vlt.TaskEditResult, format string) {
+	switch format {
+	case "json":
+		data, _ := json.Marshal(r)
+		fmt.Println(string(data))
+	case "yaml":
+		fmt.Printf("file: %s\nline: %d\ntext: %s\n", yamlEscapeValue(r.File), r.Line, yamlEscapeValue(r.Text))
+		if r.Next != "" {
+			fmt.Printf("next: %s\n", yamlEscapeValue(r.Next))
 		}
+	default:
+		if r.Next != "" {
+			fmt.Printf("%s:%d: %s\n", r.File, r.Line-1, r.Next)
+		}
+		fmt.Printf("%s:%d: %s\n", r.File, r.Line, r.Text)
+	}
+}
+
+// formatReadJSON outputs a read result as a JSON object with the note's
+// integrity status; with follow or backlinks, linked notes are included
+// with their own status.
+func formatReadJSON(title string, result vlt.ReadResult, linked []vlt.LinkedNote, withLinked bool) {
+	out := struct {
+		Title     string              `json:"title"`
+		Content   string              `json:"content"`
+		Integrity vlt.IntegrityStatus `json:"integrity"`
+		Linked    *[]vlt.LinkedNote   `json:"linked,omitempty"`
+	}{Title: title, Content: result.Content, Integrity: result.Integrity}
+	if withLinked {
+		if linked == nil {
+			linked = []vlt.LinkedNote{}
+		}
+		out.Linked = &linked
 	}
+	data, _ := json.Marshal(out)
+	fmt.Println(string(data))
 }
 
 // treeNode represents a node in a directory tree for tree-format rendering.
+// label, when set, replaces the displayed name.
 type treeNode struct {
 	name     string
 	isDir    bool
+	label    string
 	children []*treeNode
 }
 
@@ -540,6 +824,127 @@ func renderTree(items []string) {
 	}
 }
 
+// renderTagTree outputs the tag hierarchy as a tree in the style of
+// renderTree, labelling each tag with its rolled-up note count. counts must
+// contain every parent tag (see Vault.TagRollup).
+func renderTagTree(counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	root := &treeNode{name: "#"}
+	nodes := map[string]*treeNode{"": root}
+
+	tags := make([]string, 0, len(counts))
+	for t := range counts {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags) // parents sort before their subtags
+
+	for _, tag := range tags {
+		parent, name := "", tag
+		if i := strings.LastIndex(tag, "/"); i >= 0 {
+			parent, name = tag[:i], tag[i+1:]
+		}
+		label := fmt.Sprintf("%s (%d)", name, counts[tag])
+		if parent == "" {
+			label = "#" + label
+		}
+		node := &treeNode{name: name, label: label}
+		nodes[tag] = node
+		if p := nodes[parent]; p != nil {
+			p.children = append(p.children, node)
+		}
+	}
+
+	sortTree(root)
+
+	for i, child := range root.children {
+		printTreeNode(child, "", i == len(root.children)-1)
+	}
+}
+
+// renderTaskTree outputs tasks grouped by file and heading, with subtasks
+// nested under their parent task. Tasks whose parent was filtered out are
+// shown at the top of their section. Order follows the notes.
+func renderTaskTree(tasks []vlt.Task) {
+	if len(tasks) == 0 {
+		return
+	}
+
+	root := &treeNode{name: "."}
+	files := make(map[string]*treeNode)
+	sections := make(map[string]*treeNode)
+	byLine := make(map[string]*treeNode)
+
+	for _, t := range tasks {
+		file := files[t.File]
+		if file == nil {
+			file = &treeNode{name: t.File}
+			files[t.File] = file
+			root.children = append(root.children, file)
+		}
+
+		parent := file
+		if t.Heading != "" {
+			key := t.File + "\x00" + t.Heading
+			if sections[key] == nil {
+				sections[key] = &treeNode{name: fmt.Sprintf("%08d", t.Line), label: "# " + t.Heading}
+				file.children = append(file.children, sections[key])
+			}
+			parent = sections[key]
+		}
+		if p := byLine[fmt.Sprintf("%s:%d", t.File, t.Parent)]; t.Parent > 0 && p != nil {
+			parent = p
+		}
+
+		node := &treeNode{
+			name:  fmt.Sprintf("%08d", t.Line),
+			label: fmt.Sprintf("[%s] %s (%d)", t.Symbol, t.Text, t.Line),
+		}
+		byLine[fmt.Sprintf("%s:%d", t.File, t.Line)] = node
+		parent.children = append(parent.children, node)
+	}
+
+	sortTree(root) // names are zero-padded lines, so this restores note order
+
+	for i, child := range root.children {
+		printTreeNode(child, "", i == len(root.children)-1)
+	}
+}
+
+// renderBookmarkTree outputs bookmarks in their stored order, with
+// groups as branches. Headings and blocks show as "path#subpath", searches
+// as "search: query".
+func renderBookmarkTree(entries []vlt.BookmarkEntry) {
+	root := bookmarkTreeNode(vlt.BookmarkEntry{Items: entries})
+	for i, child := range root.children {
+		printTreeNode(child, "", i == len(root.children)-1)
+	}
+}
+
+func bookmarkTreeNode(e vlt.BookmarkEntry) *treeNode {
+	node := &treeNode{name: e.Title, isDir: e.Type == "group"}
+	switch e.Type {
+	case "group":
+	case "search":
+		node.label = "search: " + e.Query
+	case "folder":
+		node.label = e.Path + "/"
+	case "url":
+		node.label = e.URL
+	default:
+		node.label = e.Path + e.Subpath
+	}
+	if e.Title != "" && node.label != "" {
+		node.label = fmt.Sprintf("%s (%s)", e.Title, node.label)
+	}
+	for _, item := range e.Items {
+		node.children = append(node.children, bookmarkTreeNode(item))
+	}
+	return node
+}
+
 func sortTree(node *treeNode) {
 	sort.Slice(node.children, func(i, j int) bool {
 		a, b := node.children[i], node.children[j]
@@ -563,6 +968,9 @@ func printTreeNode(node *treeNode, prefix string, isLast bool) {
 	if node.isDir {
 		displayName += "/"
 	}
+	if node.label != "" {
+		displayName = node.label
+	}
 
 	fmt.Printf("%s%s%s\n", prefix, connector, displayName)
 
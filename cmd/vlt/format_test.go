@@ -111,12 +111,13 @@ func TestFormatTable_YAML(t *testing.T) {
 
 func TestFormatSearchResults_JSON(t *testing.T) {
 	results := []vlt.SearchResult{
-		{Title: "Note A", RelPath: "folder/Note A.md"},
+		{Title: "Note A", RelPath: "folder/Note A.md", Integrity: vlt.IntegrityMismatch},
 	}
 	got := captureStdout(func() {
 		formatSearchResults(results, "json")
 	})
-	if !strings.Contains(got, `"title":"Note A"`) || !strings.Contains(got, `"path":"folder/Note A.md"`) {
+	if !strings.Contains(got, `"title":"Note A"`) || !strings.Contains(got, `"path":"folder/Note A.md"`) ||
+		!strings.Contains(got, `"integrity":"mismatch"`) {
 		t.Errorf("json search results: %q", got)
 	}
 }
@@ -335,10 +336,26 @@ func TestFormatVaultsTSV(t *testing.T) {
 	}
 }
 
+func TestFormatPropertiesAndCanvas_JSONIntegrity(t *testing.T) {
+	got := captureStdout(func() {
+		formatProperties("---\nstatus: active\n---", vlt.IntegrityMismatch, "json")
+	})
+	if strings.TrimSpace(got) != `{"properties":{"status":"active"},"integrity":"mismatch"}` {
+		t.Errorf("json properties: %q", got)
+	}
+
+	got = captureStdout(func() {
+		formatCanvas(vlt.Canvas{}, vlt.IntegrityOK, "json")
+	})
+	if strings.TrimSpace(got) != `{"canvas":{"nodes":[],"edges":[]},"integrity":"ok"}` {
+		t.Errorf("json canvas: %q", got)
+	}
+}
+
 func TestFormatPropertiesTSV(t *testing.T) {
 	text := "---\nstatus: active\ntype: decision\n---"
 	got := captureStdout(func() {
-		formatProperties(text, "tsv")
+		formatProperties(text, vlt.IntegrityOK, "tsv")
 	})
 	lines := strings.Split(strings.TrimSpace(got), "\n")
 	if len(lines) != 3 {
@@ -452,3 +469,101 @@ func TestFormatListTreeEmpty(t *testing.T) {
 		t.Errorf("empty tree should produce no output, got %q", got)
 	}
 }
+
+func TestRenderTagTree(t *testing.T) {
+	counts := map[string]int{
+		"project":        3,
+		"project/api":    2,
+		"project/api/v2": 1,
+		"project/web":    1,
+		"meeting":        4,
+	}
+	got := captureStdout(func() {
+		renderTagTree(counts)
+	})
+	want := "\u251c\u2500\u2500 #meeting (4)\n" +
+		"\u2514\u2500\u2500 #project (3)\n" +
+		"    \u251c\u2500\u2500 api (2)\n" +
+		"    \u2502   \u2514\u2500\u2500 v2 (1)\n" +
+		"    \u2514\u2500\u2500 web (1)\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTaskTree(t *testing.T) {
+	tasks := vlt.ParseTasks("- [ ] Intro\n# Plan\n- [ ] Ship\n  - [x] Build\n  - [ ] Test\n# Later\n* [ ] Docs\n")
+	for i := range tasks {
+		tasks[i].File = "Plan.md"
+	}
+	got := captureStdout(func() {
+		renderTaskTree(tasks)
+	})
+	want := "└── Plan.md\n" +
+		"    ├── [ ] Intro (1)\n" +
+		"    ├── # Plan\n" +
+		"    │   └── [ ] Ship (3)\n" +
+		"    │       ├── [x] Build (4)\n" +
+		"    │       └── [ ] Test (5)\n" +
+		"    └── # Later\n" +
+		"        └── [ ] Docs (7)\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderBookmarkTree(t *testing.T) {
+	entries := []vlt.BookmarkEntry{
+		{Type: "group", Title: "Projects", Items: []vlt.BookmarkEntry{
+			{Type: "group", Title: "Active", Items: []vlt.BookmarkEntry{
+				{Type: "heading", Path: "Roadmap.md", Subpath: "#Q3 Goals"},
+			}},
+			{Type: "search", Title: "Urgent", Query: "tag:#urgent"},
+		}},
+		{Type: "folder", Path: "archive"},
+		{Type: "file", Path: "Inbox.md"},
+	}
+	got := captureStdout(func() { renderBookmarkTree(entries) })
+	want := "├── Projects/\n" +
+		"│   ├── Active/\n" +
+		"│   │   └── Roadmap.md#Q3 Goals\n" +
+		"│   └── Urgent (search: tag:#urgent)\n" +
+		"├── archive/\n" +
+		"└── Inbox.md\n"
+	if got != want {
+		t.Errorf("renderBookmarkTree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatReadJSON(t *testing.T) {
+	result := vlt.ReadResult{Content: "# Note\n", Integrity: vlt.IntegrityMismatch}
+	got := captureStdout(func() { formatReadJSON("Note", result, nil, false) })
+	want := `{"title":"Note","content":"# Note\n","integrity":"mismatch"}` + "\n"
+	if got != want {
+		t.Errorf("formatReadJSON = %s, want %s", got, want)
+	}
+
+	linked := []vlt.LinkedNote{{Title: "Other", Path: "Other.md", Integrity: vlt.IntegrityMismatch}}
+	got = captureStdout(func() { formatReadJSON("Note", vlt.ReadResult{Content: "x"}, linked, true) })
+	if !strings.Contains(got, `"linked":[{"title":"Other","path":"Other.md","content":"","integrity":"mismatch"}]`) {
+		t.Errorf("formatReadJSON with links = %s", got)
+	}
+}
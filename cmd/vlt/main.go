@@ -23,16 +23,32 @@ var version = "dev"
 var knownCommands = map[string]bool{
 	"read": true, "search": true, "create": true,
 	"append": true, "prepend": true, "write": true, "patch": true, "move": true, "delete": true,
-	"property:set": true, "property:remove": true, "properties": true,
+	"property:set": true, "property:remove": true, "properties": true, "validate": true,
 	"backlinks": true, "links": true, "orphans": true, "unresolved": true,
-	"tags": true, "tag": true, "files": true,
-	"tasks": true, "daily": true, "templates": true, "templates:apply": true,
+	"attachments:unused": true, "attachments:clean": true,
+	"canvas:read": true, "canvas:add-node": true,
+	"trash": true, "trash:restore": true, "trash:empty": true,
+	"tags": true, "tags:related": true, "tag": true, "tag:rename": true,
+	"tag:add": true, "tag:remove": true, "lint:tags": true, "files": true,
+	"tasks": true, "task:done": true, "task:undo": true, "task:set": true, "task:add": true,
+	"daily": true, "weekly": true, "monthly": true, "quarterly": true, "yearly": true,
+	"templates": true, "templates:apply": true, "templates:insert": true,
 	"bookmarks": true, "bookmarks:add": true, "bookmarks:remove": true,
-	"integrity:baseline": true, "integrity:acknowledge": true, "integrity:status": true,
-	"uri":    true,
+	"bookmarks:add-group": true, "bookmarks:rename-group": true, "bookmarks:delete-group": true,
+	"integrity:baseline": true, "integrity:acknowledge": true, "integrity:status": true, "integrity:audit": true,
+	"integrity:diff": true, "integrity:restore": true, "integrity:policy": true, "integrity:migrate": true,
+	"lock:status": true, "uri": true,
 	"vaults": true, "help": true, "version": true,
 }
 
+// dateCommands resolve "today" or date expressions, so they refuse to run
+// with an invalid VLT_TIMEZONE instead of silently using the system zone.
+var dateCommands = map[string]bool{
+	"daily": true, "weekly": true, "monthly": true, "quarterly": true, "yearly": true,
+	"tasks": true, "task:done": true, "task:set": true, "task:add": true,
+	"templates:apply": true, "templates:insert": true,
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		usage()
@@ -76,14 +92,44 @@ func main() {
 	if err != nil {
 		die("%v", err)
 	}
+	if dateCommands[cmd] {
+		if _, err := vlt.TimeZone(); err != nil {
+			die("%v", err)
+		}
+	}
+	policy, err := v.IntegrityPolicy()
+	if err != nil {
+		die("%v", err)
+	}
+	if err := v.RegistryError(); err != nil {
+		// Under block nothing can be verified, so only the integrity
+		// commands (which inspect and rebuild the registry) may run.
+		if policy == vlt.PolicyBlock && !strings.HasPrefix(cmd, "integrity:") && cmd != "lock:status" {
+			die("%v -- the block policy refuses all reads and writes until integrity:baseline rebuilds it", err)
+		}
+		fmt.Fprintf(os.Stderr, "vlt: WARNING: %v -- writes are not tracked until integrity:baseline rebuilds it\n", err)
+	}
+	v.SetAuditContext(cmd, "")
+
+	// lock:status probes the vault lock, so it must not hold it itself.
+	if cmd == "lock:status" {
+		if err := dispatchLockStatus(v, format); err != nil {
+			die("%v", err)
+		}
+		return
+	}
 
 	// Write commands always acquire an exclusive lock. Read commands skip
 	// locking by default so they are never blocked by a concurrent writer.
 	// Pass --strict-flock to restore shared-lock behaviour for reads.
 	unlock := func() {} // no-op for lock-free reads
 	if vlt.IsWriteCommand(cmd) || flags["--strict-flock"] {
+		lockOpts, err := lockOptions(cmd, params, flags)
+		if err != nil {
+			die("%v", err)
+		}
 		var lockErr error
-		unlock, lockErr = vlt.LockVault(v.Dir(), vlt.IsWriteCommand(cmd))
+		unlock, lockErr = vlt.LockVaultWith(v.Dir(), lockOpts)
 		if lockErr != nil {
 			die("cannot lock vault: %v", lockErr)
 		}
@@ -95,7 +141,7 @@ func main() {
 	// Dispatch
 	switch cmd {
 	case "read":
-		err = dispatchRead(v, params, flags)
+		err = dispatchRead(v, params, flags, format)
 	case "search":
 		err = dispatchSearch(v, params, format)
 	case "create":
@@ -126,38 +172,96 @@ func main() {
 		err = dispatchOrphans(v, format)
 	case "unresolved":
 		err = dispatchUnresolved(v, format)
+	case "attachments:unused":
+		err = dispatchAttachmentsUnused(v, format)
+	case "attachments:clean":
+		err = dispatchAttachmentsClean(v)
+	case "trash":
+		err = dispatchTrash(v, format)
+	case "trash:restore":
+		err = dispatchTrashRestore(v, params)
+	case "trash:empty":
+		err = dispatchTrashEmpty(v, params)
+	case "canvas:read":
+		err = dispatchCanvasRead(v, params, format)
+	case "canvas:add-node":
+		err = dispatchCanvasAddNode(v, params)
 	case "tags":
 		err = dispatchTags(v, params, flags["counts"], format)
+	case "tags:related":
+		err = dispatchTagsRelated(v, params, format)
 	case "tag":
 		err = dispatchTag(v, params, format)
+	case "tag:rename":
+		err = dispatchTagRename(v, params, format)
+	case "lint:tags":
+		err = dispatchLintTags(v, format)
+	case "validate":
+		err = dispatchValidate(v, params, format)
+	case "tag:add":
+		err = dispatchTagAdd(v, params)
+	case "tag:remove":
+		err = dispatchTagRemove(v, params, flags["inline"])
 	case "files":
 		err = dispatchFiles(v, params, flags["total"], format)
+	case "task:done":
+		err = dispatchTaskEdit(v, params, "done", ts, format)
+	case "task:undo":
+		err = dispatchTaskEdit(v, params, "undo", ts, format)
+	case "task:set":
+		err = dispatchTaskEdit(v, params, "set", ts, format)
+	case "task:add":
+		err = dispatchTaskAdd(v, params, ts, format)
 	case "tasks":
 		err = dispatchTasks(v, params, flags)
-	case "daily":
-		err = dispatchDaily(v, params)
+	case "daily", "weekly", "monthly", "quarterly", "yearly":
+		err = dispatchPeriodic(v, cmd, params, flags)
 	case "templates":
 		err = dispatchTemplates(v, params, format)
 	case "templates:apply":
 		err = dispatchTemplatesApply(v, params)
+	case "templates:insert":
+		err = dispatchTemplatesInsert(v, params, ts)
 	case "bookmarks":
 		err = dispatchBookmarks(v, format)
 	case "bookmarks:add":
 		err = dispatchBookmarksAdd(v, params)
 	case "bookmarks:remove":
 		err = dispatchBookmarksRemove(v, params)
+	case "bookmarks:add-group":
+		err = dispatchBookmarksAddGroup(v, params)
+	case "bookmarks:rename-group":
+		err = dispatchBookmarksRenameGroup(v, params)
+	case "bookmarks:delete-group":
+		err = dispatchBookmarksDeleteGroup(v, params, flags)
 	case "integrity:baseline":
-		err = dispatchIntegrityBaseline(v)
+		err = dispatchIntegrityBaseline(v, flags)
 	case "integrity:acknowledge":
 		err = dispatchIntegrityAcknowledge(v, params)
 	case "integrity:status":
 		err = dispatchIntegrityStatus(v, format)
+	case "integrity:audit":
+		err = dispatchIntegrityAudit(v, format)
+	case "integrity:diff":
+		err = dispatchIntegrityDiff(v, params)
+	case "integrity:restore":
+		err = dispatchIntegrityRestore(v, params)
+	case "integrity:policy":
+		err = dispatchIntegrityPolicy(v, params)
+	case "integrity:migrate":
+		err = dispatchIntegrityMigrate(v, params, flags)
 	case "uri":
 		err = dispatchURI(v, vaultName, params)
 	default:
 		die("unknown command: %s", cmd)
 	}
 
+	if auditErr := v.AuditError(); auditErr != nil {
+		fmt.Fprintf(os.Stderr, "vlt: WARNING: %v -- changes made by this command are missing from the audit log\n", auditErr)
+	}
+	if snapErr := v.SnapshotError(); snapErr != nil {
+		fmt.Fprintf(os.Stderr, "vlt: WARNING: %v -- integrity:diff and integrity:restore cannot use this version\n", snapErr)
+	}
 	if err != nil {
 		die("%v", err)
 	}
@@ -234,13 +338,19 @@ File commands:
   patch          file="<title>" old="<text>" new="<text>" [heading=|line=] [timestamps]       Find and replace
   move           path="<from>" to="<to>"                     Move/rename (updates wiki + md links)
   delete         file="<title>" [permanent]                  Trash (or permanently delete)
+                 [links=leave|unlink] [redirect="<note>"]    Handle inbound links (default: refuse)
   files          [folder="<dir>"] [ext="<ext>"] [total]      List vault files
-  daily          [date="YYYY-MM-DD"]                         Create or read daily note
+  daily          [date="<date>"] [offset=N] [previous|next]  Create or read daily note
+  weekly         [date="<date>"] [offset=N] [previous|next]  Create or read weekly note
+  monthly        [date="<date>"] [offset=N] [previous|next]  Create or read monthly note
+  quarterly      [date="<date>"] [offset=N] [previous|next]  Create or read quarterly note
+  yearly         [date="<date>"] [offset=N] [previous|next]  Create or read yearly note
 
 Property commands:
   properties     file="<title>"                              Show all frontmatter
   property:set   file="<title>" name="<key>" value="<val>"   Set a frontmatter property
   property:remove file="<title>" name="<key>"                Remove a frontmatter property
+  validate       [file="<title>"]                            Check frontmatter against .vlt/lint.yaml schemas
 
 Link commands:
   backlinks      file="<title>"                              Notes linking to this note
@@ -248,28 +358,69 @@ Link commands:
   orphans                                                    Notes with no incoming links
   unresolved                                                 Broken links across vault
 
+Attachment commands:
+  attachments:unused                                         Attachments no note references
+  attachments:clean                                          Move unused attachments to .trash
+
+Trash commands:
+  trash                                                      List .trash with original paths
+  trash:restore  path="<path>" [to="<path>"]                 Restore to the original location
+  trash:empty    [older-than="30d"]                          Permanently delete trashed files
+
+Canvas commands:
+  canvas:read    file="<canvas>"                             List canvas nodes (json: full canvas)
+  canvas:add-node file="<canvas>" [type=] [text=] [note=]    Add a text/file/link/group node
+                 [url=] [label=] [color=] [x= y=] [from=]    (from= adds an edge from a node)
+
 Tag commands:
   tags           [sort="count"] [counts]                     List all tags in vault
+  tags:related   tag="<tagname>"                             Co-occurring tags ranked by frequency
   tag            tag="<tagname>"                             Find notes with tag (+ subtags)
+  tag:rename     from="<tag>" to="<tag>"                     Rename tag (+ subtags) vault-wide
+  tag:add        file="<title>" tag="<tag>"                  Add tag to frontmatter tags
+  tag:remove     file="<title>" tag="<tag>" [inline]         Remove tag (inline: body #tags too)
+  lint:tags                                                  Check tags against .vlt/lint.yaml (exit 1 on violations)
 
 Task commands:
   tasks          [file="<title>"] [path="<dir>"] [done] [pending]  List tasks (checkboxes)
+                 [status=] [priority=] [due=] [scheduled=] [overdue] [sort=]  Tasks-plugin filters
+                 [section="<heading>"] [--tree]               Filter by heading; tree shows subtasks
+  task:done      file="<title>" line="<N>"|match="<text>" [timestamps]  Complete task (✅ date, next recurrence)
+  task:undo      file="<title>" line="<N>"|match="<text>" [timestamps]  Reopen a completed task
+  task:set       file="<title>" line="<N>"|match="<text>" [due=] [scheduled=] [start=] [priority=]  Reschedule/reprioritise ("none" clears)
+  task:add       file="<title>" text="<task>" [heading="<heading>"] [due=] [priority=]  Add a task
 
 Template commands:
   templates                                                    List available templates
   templates:apply template="<name>" name="<title>" path="<path>"  Create note from template
+                 [var.<key>="<value>"] [vars="<file.json>"]    Template variables
+  templates:insert template="<name>" file="<title>" [heading="<heading>"] [position=top|bottom] [line=<N>]
+                                                              Render a template into an existing note
 
 Bookmark commands:
-  bookmarks                                                    List bookmarked file paths
-  bookmarks:add  file="<title>"                                Add a bookmark for a note
-  bookmarks:remove file="<title>"                              Remove a bookmark
+  bookmarks                                                    List bookmarked file paths (--tree for groups)
+  bookmarks:add  file="<title>" [heading=|block=] [group=]     Add a note, heading or block bookmark
+  bookmarks:add  folder="<path>"|query="<search>" [group=]     Add a folder or search bookmark
+  bookmarks:remove file="<title>" [heading=|block=]            Remove a bookmark (also folder=, query=)
+  bookmarks:add-group group="<A/B>"                            Create a bookmark group
+  bookmarks:rename-group group="<A/B>" to="<name>"             Rename a bookmark group
+  bookmarks:delete-group group="<A/B>" [force]                 Delete a group (force if not empty)
 
 Integrity commands:
-  integrity:baseline                                             Register all vault files for integrity tracking
+  integrity:baseline [force]                                     Register all vault files (force: re-seed a missing audit log)
   integrity:status                                               Show integrity status of all registered files
+  integrity:audit                                                Verify the hash-chained audit log against the registry
+  integrity:diff file="<title>"                                  Diff the registered version against the current file
+  integrity:restore file="<title>"                               Roll a file back to its registered version
+  integrity:policy [set=warn|block|ignore]                       Show or set the vault's mismatch policy
+  integrity:migrate from="<old vault path>" [force]              Re-key the registry after the vault moved
+  integrity:migrate to=vault|external [force]                    Move the registry into the vault (.vlt/) or out
   integrity:acknowledge file="<title>"                           Re-register a file after external modification
   integrity:acknowledge since="<duration>"                       Re-register files modified within duration (e.g., "1h")
 
+Lock commands:
+  lock:status                                                    Show who holds the vault lock and whether it is still running
+
 URI commands:
   uri            file="<title>" [heading="<H>"] [block="<B>"]  Generate obsidian:// URI for a note
 
@@ -290,21 +441,53 @@ Options:
   heading          Accepts "## Section" (exact level) or "Section" (any level).
   timestamps       Auto-manage created_at/updated_at frontmatter (or set VLT_TIMESTAMPS=1).
   counts           Show note counts with tags.
+  inline           Also strip matching inline #tags (tag:remove).
+  previous/next    Periodic note one period before/after (daily, weekly, ...).
   total            Show count instead of listing files.
   done             Show only completed tasks.
   pending          Show only pending tasks.
+  overdue          Show only open tasks due before today.
   follow           Include full content of forward-linked notes (read only).
   backlinks        Include full content of notes linking to this one (read only).
   --strict-flock   Acquire advisory flock for reads too (default: writes only).
+  lock-timeout=    Give up waiting for the vault lock after a duration (e.g., "5s").
+  --no-wait        Fail at once if another process holds the vault lock.
   --json           Output in JSON format.
   --yaml           Output in YAML format.
   --csv            Output in CSV format.
   --tsv            Output in TSV (tab-separated values) format.
-  --tree           Output file lists as a hierarchical directory tree.
+  --tree           Output file lists as a hierarchical directory tree (tags: tag hierarchy).
 
 Content from stdin:
   If content= is omitted for create/append/prepend/write, content is read from stdin.
 
+Dates:
+  date=, due= and scheduled= accept YYYY-MM-DD, today, yesterday, tomorrow,
+  offsets (+3d, -1w, +2m, -1y) and next/last <weekday> (next monday).
+  "Today" follows VLT_TIMEZONE (IANA name, e.g. Europe/Berlin) or the system zone.
+  Templates: {{date tomorrow}}, {{date +7d:YYYY-MM-DD}}.
+  Periodic notes also take a note name in their format: weekly date="2025-W03".
+
+Templates:
+  {{title}}, {{date}}, {{time}} and {{date:FORMAT}} use Moment.js formats.
+  Templater tags are evaluated without JavaScript: <% tp.file.title %>,
+  <% tp.date.now("YYYY-MM-DD", 7) %>, <% tp.file.folder(true) %>,
+  <% tp.frontmatter.key %>. Other expressions and <%* %> blocks are errors.
+  Other {{name}} placeholders and tp.system.prompt("name") take var.name= or
+  vars=<file.json>; defaults come from a variables: map in the template's
+  frontmatter. Missing variables are reported and nothing is created.
+
+Integrity:
+  Registered changes are logged (hash-chained) with the command and actor
+  (VLT_ACTOR or the OS user); integrity:audit verifies the log.
+  VLT_SNAPSHOTS=1 keeps compressed snapshots of registered content, which
+  integrity:diff and integrity:restore need.
+  VLT_INTEGRITY=warn|block|ignore overrides the vault's policy (integrity:policy).
+  block: reading a mismatched note fails and writes refuse to touch it until
+  integrity:acknowledge; read --json always includes the integrity status.
+  The registry lives in <vault>/.vlt/ when .vlt/registry.json exists, else in
+  $VLT_REGISTRY_DIR/<vault-id>/ (default ~/.vlt/registries/<vault-id>/).
+
 Search filters:
   Property filters can be embedded in search queries: query="term [key:value]"
   Multiple filters: query="architecture [status:active] [type:decision]"
@@ -347,22 +530,42 @@ Examples:
   vlt vault="AgentVault" move path="_inbox/Old.md" to="decisions/New.md"
   vlt vault="AgentVault" delete file="Old Draft"
   vlt vault="AgentVault" delete file="Old Draft" permanent
+  vlt vault="AgentVault" delete file="Old Draft" redirect="New Draft"
   vlt vault="ProjectVault" properties file="My Decision"
+  vlt vault="ProjectVault" validate --json
   vlt vault="ProjectVault" property:set file="Note" name="status" value="archived"
   vlt vault="ProjectVault" property:remove file="Note" name="confidence"
   vlt vault="AgentVault" backlinks file="Operating Mode"
   vlt vault="ProjectVault" links file="Developer Guide"
   vlt vault="ProjectVault" orphans
   vlt vault="ProjectVault" unresolved
+  vlt vault="ProjectVault" attachments:unused
+  vlt vault="ProjectVault" attachments:clean
+  vlt vault="AgentVault" trash:restore file="Old Draft"
+  vlt vault="AgentVault" trash:empty older-than="30d"
+  vlt vault="ProjectVault" canvas:read file="Roadmap" --json
+  vlt vault="ProjectVault" canvas:add-node file="Roadmap" type="file" note="Q3 Plan"
   vlt vault="AgentVault" tags counts sort="count"
+  vlt vault="AgentVault" tags --tree
+  vlt vault="AgentVault" tags:related tag="project"
   vlt vault="AgentVault" tag tag="project"
+  vlt vault="AgentVault" tag:rename from="proj" to="project"
+  vlt vault="AgentVault" tag:add file="My Note" tag="review"
+  vlt vault="AgentVault" lint:tags
   vlt vault="ProjectVault" files folder="docs"
   vlt vault="ProjectVault" files total
   vlt vault="ProjectVault" tasks
   vlt vault="ProjectVault" tasks file="Project Plan" pending
   vlt vault="ProjectVault" tasks path="projects" --json
+  vlt vault="ProjectVault" tasks due="2025-06-01..2025-06-30" priority="high,highest" sort="due"
+  vlt vault="ProjectVault" tasks due="today..+7d" pending
+  vlt vault="ProjectVault" task:done file="Project Plan" match="Ship release"
+  vlt vault="ProjectVault" task:add file="Project Plan" heading="Next" text="Write docs" due="2025-07-01"
   vlt vault="AgentVault" daily
   vlt vault="AgentVault" daily date="2025-01-15"
+  vlt vault="AgentVault" daily date="last friday"
+  vlt vault="AgentVault" weekly previous
+  vlt vault="AgentVault" monthly offset=-2
   vlt vault="ProjectVault" orphans --json
   vlt vault="ProjectVault" search query="architecture" --csv
   vlt vault="ProjectVault" search query="architecture" context="2"
@@ -376,10 +579,16 @@ Examples:
   vlt vault="ProjectVault" templates
   vlt vault="ProjectVault" templates --json
   vlt vault="ProjectVault" templates:apply template="Meeting Notes" name="Q1 Planning" path="meetings/Q1 Planning.md"
+  vlt vault="ProjectVault" templates:apply template="Project" name="Apollo" path="projects/Apollo.md" var.owner=Ana
+  vlt vault="ProjectVault" templates:insert template="Decision" file="Apollo" heading="## Decisions"
   vlt vault="AgentVault" bookmarks
   vlt vault="AgentVault" bookmarks --json
   vlt vault="AgentVault" bookmarks:add file="Important Note"
   vlt vault="AgentVault" bookmarks:remove file="Old Note"
+  vlt vault="AgentVault" bookmarks:add-group group="Projects/Active"
+  vlt vault="AgentVault" bookmarks:add file="Roadmap" heading="Q3" group="Projects/Active"
+  vlt vault="AgentVault" bookmarks:add query="tag:#urgent" title="Urgent" group="Projects"
+  vlt vault="AgentVault" bookmarks --tree
   vlt vault="ProjectVault" uri file="Design Doc"
   vlt vault="ProjectVault" uri file="Design Doc" heading="Architecture"
   vlt vault="ProjectVault" uri file="Note" block="block-id"
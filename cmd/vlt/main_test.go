@@ -3,8 +3,10 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	vlt "github.com/RamXX/vlt"
 )
@@ -194,3 +196,104 @@ func TestDispatchCreateAcceptsFrontmatterOnly(t *testing.T) {
 		t.Error("frontmatter not written")
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"1h", time.Hour},
+		{"30m", 30 * time.Minute},
+		{"7d", 7 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := parseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"soon", "-3d", "-1w", "0d", "-1h", "0s"} {
+		if _, err := parseDuration(bad); err == nil {
+			t.Errorf("parseDuration(%q): expected error", bad)
+		}
+	}
+}
+
+func TestDateCommands(t *testing.T) {
+	for _, cmd := range []string{"daily", "tasks", "task:add", "templates:apply"} {
+		if !dateCommands[cmd] {
+			t.Errorf("%s should validate VLT_TIMEZONE", cmd)
+		}
+	}
+	for _, cmd := range []string{"read", "search", "write", "vaults", "help", "version"} {
+		if dateCommands[cmd] {
+			t.Errorf("%s should not depend on VLT_TIMEZONE", cmd)
+		}
+	}
+}
+
+func TestLockOptions(t *testing.T) {
+	opts, err := lockOptions("append", map[string]string{"lock-timeout": "5s"}, map[string]bool{"--no-wait": true})
+	if err != nil {
+		t.Fatalf("lockOptions: %v", err)
+	}
+	if !opts.Exclusive || opts.Timeout != 5*time.Second || !opts.NoWait || opts.Command != "append" {
+		t.Errorf("opts = %+v", opts)
+	}
+	if opts, _ := lockOptions("read", nil, nil); opts.Exclusive || opts.Timeout != 0 || opts.NoWait {
+		t.Errorf("read opts = %+v, want blocking shared lock", opts)
+	}
+	for _, bad := range []string{"soon", "0s", "-1s"} {
+		if _, err := lockOptions("write", map[string]string{"lock-timeout": bad}, nil); err == nil {
+			t.Errorf("lock-timeout=%q accepted", bad)
+		}
+	}
+}
+
+func TestDispatchLockStatus(t *testing.T) {
+	dir := t.TempDir()
+	v, err := vlt.Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if got := captureStdout(func() { dispatchLockStatus(v, "") }); got != "vault is not locked\n" {
+		t.Errorf("unlocked output = %q", got)
+	}
+
+	unlock, err := vlt.LockVaultWith(dir, vlt.LockOptions{Exclusive: true, Command: "patch"})
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	defer unlock()
+	got := captureStdout(func() { dispatchLockStatus(v, "") })
+	for _, want := range []string{"vault is locked by pid " + strconv.Itoa(os.Getpid()), "command: patch", "process: running"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("locked output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestTemplateVars(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "vars.json")
+	os.WriteFile(file, []byte(`{"project":"Apollo","owner":"Ana","attendees":["Ana","Bo"],"budget":1500,"draft":false}`), 0644)
+
+	vars, err := templateVars(map[string]string{"vars": file, "var.owner": "Cy", "name": "Sync"})
+	if err != nil {
+		t.Fatalf("templateVars: %v", err)
+	}
+	want := map[string]string{"project": "Apollo", "owner": "Cy", "attendees": "Ana, Bo", "budget": "1500", "draft": "false"}
+	if len(vars) != len(want) {
+		t.Errorf("vars = %v, want %v", vars, want)
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("vars[%s] = %q, want %q", k, vars[k], v)
+		}
+	}
+
+	if _, err := templateVars(map[string]string{"vars": filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("expected error for missing vars file")
+	}
+}
@@ -13,16 +13,18 @@ import (
 
 // SearchResult holds a single search match.
 type SearchResult struct {
-	Title   string
-	RelPath string
+	Title     string
+	RelPath   string
+	Integrity IntegrityStatus
 }
 
 // ContextMatch holds a single line-level match with surrounding context.
 type ContextMatch struct {
-	File    string   // relative path
-	Line    int      // 1-based line number of the match
-	Match   string   // the matched line text
-	Context []string // surrounding lines including the match line
+	File      string          // relative path
+	Line      int             // 1-based line number of the match
+	Match     string          // the matched line text
+	Context   []string        // surrounding lines including the match line
+	Integrity IntegrityStatus // integrity status of File
 }
 
 // lineRange represents an inclusive range of 0-based line indices.
@@ -69,11 +71,31 @@ type MoveResult struct {
 	NewTitle         string
 	WikilinksUpdated int
 	MdLinksUpdated   int
+	CanvasesUpdated  int
 }
 
 // ErrNoteExists is returned by Create when a note already exists at the target path.
 var ErrNoteExists = fmt.Errorf("note already exists")
 
+// DeleteOptions controls how Delete removes a note and treats inbound links.
+type DeleteOptions struct {
+	Permanent  bool   // remove the file instead of trashing it
+	Links      string // inbound links: "" (refuse), "leave", "unlink" or "redirect"
+	RedirectTo string // note title that links are redirected to (Links "redirect")
+}
+
+// DeleteResult is returned by Delete and reports what happened.
+type DeleteResult struct {
+	Path         string   // vault-relative path of the deleted note
+	Dest         string   // trash location, "system trash", or "" if deleted permanently
+	Backlinks    []string // files that referenced the note
+	LinksUpdated int      // files rewritten by unlink or redirect
+}
+
+// ErrHasBacklinks is returned by Delete when the note is still referenced
+// and no DeleteOptions.Links handling was chosen.
+var ErrHasBacklinks = fmt.Errorf("note has inbound links")
+
 // sectionBounds holds the line range of a section identified by findSection.
 // HeadingLine is the 0-based index of the heading line itself.
 // ContentStart is the 0-based index of the first content line after the heading.
@@ -364,6 +386,9 @@ func (v *Vault) Read(title, heading string) (ReadResult, error) {
 	}
 
 	status := v.registry.verify(v.dir, path, data)
+	if err := v.registry.blocked(v.dir, path, status, "read"); err != nil {
+		return ReadResult{Integrity: status}, err
+	}
 
 	if heading == "" {
 		return ReadResult{Content: string(data), Integrity: status}, nil
@@ -389,11 +414,13 @@ func (v *Vault) Read(title, heading string) (ReadResult, error) {
 }
 
 // LinkedNote holds a related note's title and content, returned by ReadFollow
-// and ReadWithBacklinks.
+// and ReadWithBacklinks. Under the block policy a mismatched note's Content
+// is withheld (empty).
 type LinkedNote struct {
-	Title   string // note title (stem of filename)
-	Path    string // vault-relative path
-	Content string // full file content
+	Title     string          `json:"title"`     // note title (stem of filename)
+	Path      string          `json:"path"`      // vault-relative path
+	Content   string          `json:"content"`   // full file content
+	Integrity IntegrityStatus `json:"integrity"` // integrity status of the linked note
 }
 
 // ReadFollow returns the content of the requested note (with integrity status)
@@ -414,6 +441,9 @@ func (v *Vault) ReadFollow(title, heading string) (ReadResult, []LinkedNote, err
 	}
 
 	status := v.registry.verify(v.dir, path, data)
+	if err := v.registry.blocked(v.dir, path, status, "read"); err != nil {
+		return ReadResult{Integrity: status}, nil, err
+	}
 
 	primary := string(data)
 	if heading != "" {
@@ -443,21 +473,31 @@ func (v *Vault) ReadFollow(title, heading string) (ReadResult, []LinkedNote, err
 		if resolveErr != nil {
 			continue // skip broken links
 		}
+		if !strings.HasSuffix(linkedPath, ".md") {
+			continue // skip attachments (images, PDFs, ...)
+		}
 		linkedData, readErr := os.ReadFile(linkedPath)
 		if readErr != nil {
 			continue
 		}
 		relPath, _ := filepath.Rel(v.dir, linkedPath)
-		linked = append(linked, LinkedNote{
-			Title:   wl.Title,
-			Path:    relPath,
-			Content: string(linkedData),
-		})
+		linked = append(linked, v.linkedNote(wl.Title, linkedPath, relPath, linkedData))
 	}
 
 	return ReadResult{Content: primary, Integrity: status}, linked, nil
 }
 
+// linkedNote builds a LinkedNote with its integrity status, withholding
+// the content of a mismatched note under the block policy.
+func (v *Vault) linkedNote(title, absPath, relPath string, data []byte) LinkedNote {
+	status := v.registry.verify(v.dir, absPath, data)
+	content := string(data)
+	if v.registry.blocked(v.dir, absPath, status, "read") != nil {
+		content = ""
+	}
+	return LinkedNote{Title: title, Path: relPath, Content: content, Integrity: status}
+}
+
 // ReadWithBacklinks returns the content of the requested note (with integrity
 // status) plus the full content of every note that links TO it (depth 1 backlinks).
 func (v *Vault) ReadWithBacklinks(title, heading string) (ReadResult, []LinkedNote, error) {
@@ -475,6 +515,9 @@ func (v *Vault) ReadWithBacklinks(title, heading string) (ReadResult, []LinkedNo
 	}
 
 	status := v.registry.verify(v.dir, path, data)
+	if err := v.registry.blocked(v.dir, path, status, "read"); err != nil {
+		return ReadResult{Integrity: status}, nil, err
+	}
 
 	primary := string(data)
 	if heading != "" {
@@ -503,11 +546,7 @@ func (v *Vault) ReadWithBacklinks(title, heading string) (ReadResult, []LinkedNo
 			continue
 		}
 		blTitle := strings.TrimSuffix(filepath.Base(relPath), ".md")
-		linked = append(linked, LinkedNote{
-			Title:   blTitle,
-			Path:    relPath,
-			Content: string(blData),
-		})
+		linked = append(linked, v.linkedNote(blTitle, absPath, relPath, blData))
 	}
 
 	return ReadResult{Content: primary, Integrity: status}, linked, nil
@@ -616,7 +655,7 @@ func (v *Vault) Search(opts SearchOptions) ([]SearchResult, error) {
 
 		// If no text query, property filters already passed.
 		if !hasTextQuery {
-			results = append(results, SearchResult{Title: title, RelPath: relPath})
+			results = append(results, SearchResult{Title: title, RelPath: relPath, Integrity: v.registry.verify(v.dir, path, data)})
 			return nil
 		}
 
@@ -634,7 +673,7 @@ func (v *Vault) Search(opts SearchOptions) ([]SearchResult, error) {
 			return nil
 		}
 
-		results = append(results, SearchResult{Title: title, RelPath: relPath})
+		results = append(results, SearchResult{Title: title, RelPath: relPath, Integrity: v.registry.verify(v.dir, path, data)})
 		return nil
 	})
 
@@ -647,6 +686,8 @@ func (v *Vault) Search(opts SearchOptions) ([]SearchResult, error) {
 
 // SearchWithContext finds notes matching opts.Query or opts.Regex and returns
 // line-level matches with opts.ContextN surrounding lines on each side.
+// Under the block policy a matching note that fails its integrity check is
+// an *IntegrityError, since its lines would be returned.
 func (v *Vault) SearchWithContext(opts SearchOptions) ([]ContextMatch, error) {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
@@ -731,6 +772,8 @@ func (v *Vault) SearchWithContext(opts SearchOptions) ([]ContextMatch, error) {
 			return nil
 		}
 		content := string(data)
+		status := v.registry.verify(v.dir, path, data)
+		blockErr := v.registry.blocked(v.dir, path, status, "read")
 
 		// Check property filters first if present.
 		if hasFilters {
@@ -756,13 +799,18 @@ func (v *Vault) SearchWithContext(opts SearchOptions) ([]ContextMatch, error) {
 			contentMatches = strings.Contains(strings.ToLower(content), queryLower)
 		}
 
+		if blockErr != nil && (!hasTextQuery || titleMatches || contentMatches) {
+			return blockErr
+		}
+
 		if !hasTextQuery {
 			// Filters matched but no text query -- synthetic title match.
 			contextResults = append(contextResults, ContextMatch{
-				File:    relPath,
-				Line:    0,
-				Match:   title,
-				Context: nil,
+				File:      relPath,
+				Line:      0,
+				Match:     title,
+				Context:   nil,
+				Integrity: status,
 			})
 			return nil
 		}
@@ -805,10 +853,11 @@ func (v *Vault) SearchWithContext(opts SearchOptions) ([]ContextMatch, error) {
 							ctxLines = append(ctxLines, lines[j])
 						}
 						contextResults = append(contextResults, ContextMatch{
-							File:    relPath,
-							Line:    i + 1, // 1-based
-							Match:   lines[i],
-							Context: ctxLines,
+							File:      relPath,
+							Line:      i + 1, // 1-based
+							Match:     lines[i],
+							Context:   ctxLines,
+							Integrity: status,
 						})
 					}
 				}
@@ -816,10 +865,11 @@ func (v *Vault) SearchWithContext(opts SearchOptions) ([]ContextMatch, error) {
 		} else if titleMatches {
 			// Title matched but no content match -- synthetic context match.
 			contextResults = append(contextResults, ContextMatch{
-				File:    relPath,
-				Line:    0,
-				Match:   title,
-				Context: nil,
+				File:      relPath,
+				Line:      0,
+				Match:     title,
+				Context:   nil,
+				Integrity: status,
 			})
 		}
 
@@ -854,6 +904,9 @@ func (v *Vault) Create(name, path, content string, silent, timestamps bool) erro
 	if _, err := os.Stat(fullPath); err == nil {
 		return ErrNoteExists
 	}
+	if err := v.registry.guard(v.dir, fullPath); err != nil {
+		return err
+	}
 
 	if timestampsEnabled(timestamps) {
 		content = ensureTimestamps(content, true, time.Now())
@@ -882,6 +935,9 @@ func (v *Vault) Append(title, content string, timestamps bool) error {
 	if err != nil {
 		return err
 	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return err
+	}
 
 	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
@@ -927,6 +983,9 @@ func (v *Vault) Prepend(title, content string, timestamps bool) error {
 	if err != nil {
 		return err
 	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return err
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -970,6 +1029,9 @@ func (v *Vault) Write(title, content string, timestamps bool) error {
 	if err != nil {
 		return err
 	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return err
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -1014,6 +1076,9 @@ func (v *Vault) Patch(title string, opts PatchOptions) error {
 	if err != nil {
 		return err
 	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return err
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -1178,7 +1243,9 @@ func (v *Vault) patchOldNew(path, text string, lines []string, opts PatchOptions
 
 // Move moves a note from one path to another within the vault.
 // If the filename changes (rename, not just folder move), all wikilinks
-// referencing the old title are updated vault-wide.
+// referencing the old title are updated vault-wide. Attachments (any
+// non-.md file) have their embeds rewritten by filename and, for
+// path-qualified references like ![[assets/old.png]], by path.
 // Returns a MoveResult describing what was updated.
 func (v *Vault) Move(from, to string) (MoveResult, error) {
 	v.mu.Lock()
@@ -1196,22 +1263,45 @@ func (v *Vault) Move(from, to string) (MoveResult, error) {
 	if _, err := os.Stat(fromPath); os.IsNotExist(err) {
 		return MoveResult{}, fmt.Errorf("source not found: %s", from)
 	}
-
-	if err := os.MkdirAll(filepath.Dir(toPath), 0755); err != nil {
+	if err := v.registry.guard(v.dir, fromPath); err != nil {
 		return MoveResult{}, err
 	}
 
 	oldTitle := strings.TrimSuffix(filepath.Base(from), ".md")
 	newTitle := strings.TrimSuffix(filepath.Base(to), ".md")
+	isNote := strings.HasSuffix(fromPath, ".md")
+
+	// Check every file the link updates will touch before moving anything.
+	linkPlan := func() ([]fileEdit, error) {
+		if !isNote {
+			return attachmentLinkEdits(v.dir, from, to)
+		}
+		if oldTitle != newTitle {
+			return vaultLinkEdits(v.dir, oldTitle, newTitle)
+		}
+		return nil, nil
+	}
+	if err := v.registry.guardEdits(v.dir, linkPlan,
+		func() ([]fileEdit, error) { return mdLinkEdits(v.dir, from, to) },
+		func() ([]fileEdit, error) { return canvasLinkEdits(v.dir, from, to, oldTitle, newTitle) },
+	); err != nil {
+		return MoveResult{}, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(toPath), 0755); err != nil {
+		return MoveResult{}, err
+	}
 
 	if err := os.Rename(fromPath, toPath); err != nil {
 		return MoveResult{}, err
 	}
 
-	// Deregister old path, register new path.
+	// Deregister old path, register new path. Attachments are not tracked.
 	v.registry.deregister(v.dir, fromPath)
-	if newData, readErr := os.ReadFile(toPath); readErr == nil {
-		v.registry.register(v.dir, toPath, newData)
+	if isNote {
+		if newData, readErr := os.ReadFile(toPath); readErr == nil {
+			v.registry.register(v.dir, toPath, newData)
+		}
 	}
 
 	res := MoveResult{
@@ -1219,8 +1309,14 @@ func (v *Vault) Move(from, to string) (MoveResult, error) {
 		NewTitle: newTitle,
 	}
 
-	// If the filename changed, update wikilinks across the vault.
-	if oldTitle != newTitle {
+	if !isNote {
+		count, err := updateVaultAttachmentLinks(v.dir, from, to, v.registry)
+		if err != nil {
+			return res, fmt.Errorf("moved file but failed updating embeds: %w", err)
+		}
+		res.WikilinksUpdated = count
+	} else if oldTitle != newTitle {
+		// If the filename changed, update wikilinks across the vault.
 		count, err := updateVaultLinks(v.dir, oldTitle, newTitle, v.registry)
 		if err != nil {
 			return res, fmt.Errorf("moved file but failed updating links: %w", err)
@@ -1235,76 +1331,200 @@ func (v *Vault) Move(from, to string) (MoveResult, error) {
 	}
 	res.MdLinksUpdated = mdCount
 
+	// Update canvas file nodes and text-node wikilinks.
+	canvasCount, canvasErr := updateVaultCanvasLinks(v.dir, from, to, oldTitle, newTitle, v.registry)
+	if canvasErr != nil {
+		return res, fmt.Errorf("moved file but failed updating canvases: %w", canvasErr)
+	}
+	res.CanvasesUpdated = canvasCount
+
 	return res, nil
 }
 
-// Delete moves a note to .trash/ (or permanently deletes with permanent=true).
-// Returns a human-readable message describing what happened.
-func (v *Vault) Delete(title, notePath string, permanent bool) (string, error) {
+// Delete moves a note to .trash/ (or permanently deletes with Permanent).
+// Obsidian's trashOption setting is respected: "system" uses the OS trash and
+// "none" deletes permanently (see discardFile).
+//
+// Inbound references (wikilinks and embeds to the note's title or aliases,
+// including from canvases) are checked first. With no Links option, a note
+// that is still referenced is not deleted and ErrHasBacklinks is returned
+// along with the referencing files. "leave" deletes anyway, "unlink" replaces
+// the links with their display text, and "redirect" points them at
+// RedirectTo via ReplaceWikilinks.
+func (v *Vault) Delete(title, notePath string, opts DeleteOptions) (DeleteResult, error) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
+	var res DeleteResult
 	var fullPath string
 
 	if notePath != "" {
 		var pathErr error
 		fullPath, pathErr = safePath(v.dir, notePath)
 		if pathErr != nil {
-			return "", fmt.Errorf("delete: %w", pathErr)
+			return res, fmt.Errorf("delete: %w", pathErr)
 		}
 	} else if title != "" {
 		resolved, err := resolveNote(v.dir, title)
 		if err != nil {
-			return "", err
+			return res, err
 		}
 		fullPath = resolved
 	} else {
-		return "", fmt.Errorf("delete requires file or path to be specified")
+		return res, fmt.Errorf("delete requires file or path to be specified")
 	}
 
 	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("file not found: %s", fullPath)
+		return res, fmt.Errorf("file not found: %s", fullPath)
+	}
+	if err := v.registry.guard(v.dir, fullPath); err != nil {
+		return res, err
 	}
 
 	relPath, _ := filepath.Rel(v.dir, fullPath)
+	res.Path = relPath
 
-	if permanent {
-		if err := os.Remove(fullPath); err != nil {
-			return "", err
+	switch opts.Links {
+	case "", "leave", "unlink", "redirect":
+	default:
+		return res, fmt.Errorf("unknown links option %q (use leave, unlink or redirect)", opts.Links)
+	}
+
+	// Titles other notes may use to reach this one: the filename and aliases.
+	noteTitle := strings.TrimSuffix(filepath.Base(fullPath), ".md")
+	titles := []string{noteTitle}
+	if data, err := os.ReadFile(fullPath); err == nil && strings.HasSuffix(fullPath, ".md") {
+		if yaml, _, hasFM := ExtractFrontmatter(string(data)); hasFM {
+			titles = append(titles, FrontmatterGetList(yaml, "aliases")...)
 		}
-		v.registry.deregister(v.dir, fullPath)
-		return fmt.Sprintf("deleted: %s", relPath), nil
 	}
 
-	trashDir := filepath.Join(v.dir, ".trash")
-	if err := os.MkdirAll(trashDir, 0755); err != nil {
-		return "", err
+	seen := map[string]bool{relPath: true} // self-links don't count
+	for _, t := range titles {
+		refs, err := FindBacklinks(v.dir, t)
+		if err != nil {
+			return res, err
+		}
+		for _, ref := range refs {
+			if !seen[ref] {
+				seen[ref] = true
+				res.Backlinks = append(res.Backlinks, ref)
+			}
+		}
 	}
-	trashPath := filepath.Join(trashDir, filepath.Base(fullPath))
-	if err := os.Rename(fullPath, trashPath); err != nil {
-		return "", err
+	sort.Strings(res.Backlinks)
+
+	if len(res.Backlinks) == 0 {
+		opts.Links = "leave"
+	}
+	var targetRel, targetTitle string
+	switch opts.Links {
+	case "":
+		return res, fmt.Errorf("%w: %s is referenced by %d file(s): %s (use links=leave, links=unlink or redirect=\"<note>\")",
+			ErrHasBacklinks, relPath, len(res.Backlinks), strings.Join(res.Backlinks, ", "))
+	case "redirect":
+		if opts.RedirectTo == "" {
+			return res, fmt.Errorf("links=redirect requires a note to redirect to")
+		}
+		target, err := resolveNote(v.dir, opts.RedirectTo)
+		if err != nil {
+			return res, fmt.Errorf("redirect target: %w", err)
+		}
+		if target == fullPath {
+			return res, fmt.Errorf("cannot redirect links to the note being deleted")
+		}
+		targetRel, _ = filepath.Rel(v.dir, target)
+		targetTitle = strings.TrimSuffix(filepath.Base(target), ".md")
+	}
+
+	// Check every file the link updates will touch before deleting.
+	var plans []func() ([]fileEdit, error)
+	switch opts.Links {
+	case "unlink":
+		for _, t := range titles {
+			plans = append(plans, func() ([]fileEdit, error) { return unlinkEdits(v.dir, t) })
+		}
+		plans = append(plans, func() ([]fileEdit, error) { return canvasUnlinkEdits(v.dir, relPath, titles) })
+	case "redirect":
+		for _, t := range titles {
+			plans = append(plans, func() ([]fileEdit, error) { return vaultLinkEdits(v.dir, t, targetTitle) })
+		}
+		plans = append(plans, func() ([]fileEdit, error) {
+			return canvasLinkEdits(v.dir, relPath, targetRel, noteTitle, targetTitle)
+		})
+	}
+	if err := v.registry.guardEdits(v.dir, plans...); err != nil {
+		return res, err
+	}
+
+	// Remove the note before touching any links, so a failed delete leaves
+	// the referencing files unchanged.
+	if opts.Permanent {
+		if err := os.Remove(fullPath); err != nil {
+			return res, err
+		}
+	} else {
+		dest, err := discardFile(v.dir, fullPath)
+		if err != nil {
+			return res, err
+		}
+		res.Dest = dest
 	}
 	v.registry.deregister(v.dir, fullPath)
-	return fmt.Sprintf("trashed: %s -> .trash/%s", relPath, filepath.Base(fullPath)), nil
+
+	switch opts.Links {
+	case "unlink":
+		for _, t := range titles {
+			n, err := unlinkVaultLinks(v.dir, t, v.registry)
+			res.LinksUpdated += n
+			if err != nil {
+				return res, fmt.Errorf("deleted note but failed unlinking references: %w", err)
+			}
+		}
+		n, err := unlinkVaultCanvasLinks(v.dir, relPath, titles, v.registry)
+		res.LinksUpdated += n
+		if err != nil {
+			return res, fmt.Errorf("deleted note but failed unlinking canvas references: %w", err)
+		}
+	case "redirect":
+		for _, t := range titles {
+			n, err := updateVaultLinks(v.dir, t, targetTitle, v.registry)
+			res.LinksUpdated += n
+			if err != nil {
+				return res, fmt.Errorf("deleted note but failed redirecting references: %w", err)
+			}
+		}
+		n, err := updateVaultCanvasLinks(v.dir, relPath, targetRel, noteTitle, targetTitle, v.registry)
+		res.LinksUpdated += n
+		if err != nil {
+			return res, fmt.Errorf("deleted note but failed redirecting canvas references: %w", err)
+		}
+	}
+	return res, nil
 }
 
-// Properties returns the YAML frontmatter block of a note (with --- delimiters).
-func (v *Vault) Properties(title string) (string, error) {
+// Properties returns the YAML frontmatter block of a note (with ---
+// delimiters) as the result's Content, along with the note's integrity
+// status. Under the block policy a mismatched note is an *IntegrityError.
+func (v *Vault) Properties(title string) (ReadResult, error) {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 
 	path, err := resolveNote(v.dir, title)
 	if err != nil {
-		return "", err
+		return ReadResult{}, err
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", err
+		return ReadResult{}, err
 	}
 
-	fm := frontmatterReadAll(string(data))
-	return fm, nil
+	status := v.registry.verify(v.dir, path, data)
+	if err := v.registry.blocked(v.dir, path, status, "read"); err != nil {
+		return ReadResult{Integrity: status}, err
+	}
+	return ReadResult{Content: frontmatterReadAll(string(data)), Integrity: status}, nil
 }
 
 // PropertySet sets or adds a YAML frontmatter property in a note.
@@ -1316,6 +1536,9 @@ func (v *Vault) PropertySet(title, name, value string) error {
 	if err != nil {
 		return err
 	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return err
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -1378,6 +1601,9 @@ func (v *Vault) PropertyRemove(title, name string) error {
 	if err != nil {
 		return err
 	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return err
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -1448,7 +1674,8 @@ func (v *Vault) Links(title string) ([]LinkInfo, error) {
 	return results, nil
 }
 
-// Orphans finds notes that have no incoming wikilinks or embeds.
+// Orphans finds notes that have no incoming wikilinks, embeds or canvas
+// references.
 func (v *Vault) Orphans() ([]string, error) {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
@@ -1490,7 +1717,7 @@ func (v *Vault) Orphans() ([]string, error) {
 		return nil
 	})
 
-	// Collect all referenced titles (from wikilinks and embeds).
+	// Collect all referenced titles (from wikilinks, embeds and canvases).
 	referenced := make(map[string]bool)
 
 	filepath.WalkDir(v.dir, func(path string, d os.DirEntry, err error) error {
@@ -1501,7 +1728,7 @@ func (v *Vault) Orphans() ([]string, error) {
 			return filepath.SkipDir
 		}
 		name := d.Name()
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
+		if d.IsDir() || !isLinkSource(name) {
 			return nil
 		}
 
@@ -1510,7 +1737,7 @@ func (v *Vault) Orphans() ([]string, error) {
 			return nil
 		}
 
-		for _, link := range ParseWikilinks(string(data)) {
+		for _, link := range noteLinks(name, data) {
 			referenced[strings.ToLower(link.Title)] = true
 		}
 		return nil
@@ -1538,14 +1765,18 @@ func (v *Vault) Orphans() ([]string, error) {
 	return orphans, nil
 }
 
-// Unresolved finds all broken wikilinks across the vault.
+// Unresolved finds all broken wikilinks across the vault, including those in
+// canvas text nodes and canvas file nodes pointing at missing files.
 func (v *Vault) Unresolved() ([]UnresolvedLink, error) {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 
-	// Build sets of resolvable titles and aliases.
+	// Build sets of resolvable titles and aliases. Attachments (referenced
+	// with their extension, e.g. ![[diagram.png]]) go through
+	// resolveAttachment like read and move do.
 	titles := make(map[string]bool)
 	aliases := make(map[string]bool)
+	attachments := make(map[string]bool) // lowercased target -> resolved
 
 	filepath.WalkDir(v.dir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -1575,7 +1806,7 @@ func (v *Vault) Unresolved() ([]UnresolvedLink, error) {
 		return nil
 	})
 
-	// Find links that don't resolve.
+	// Find links (in notes and canvases) that don't resolve.
 	var results []UnresolvedLink
 	seenTargets := make(map[string]bool)
 
@@ -1587,7 +1818,7 @@ func (v *Vault) Unresolved() ([]UnresolvedLink, error) {
 			return filepath.SkipDir
 		}
 		name := d.Name()
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
+		if d.IsDir() || !isLinkSource(name) {
 			return nil
 		}
 
@@ -1598,15 +1829,26 @@ func (v *Vault) Unresolved() ([]UnresolvedLink, error) {
 
 		relPath, _ := filepath.Rel(v.dir, path)
 
-		for _, link := range ParseWikilinks(string(data)) {
+		for _, link := range noteLinks(name, data) {
 			lower := strings.ToLower(link.Title)
 			if seenTargets[lower] {
 				continue
 			}
-			if !titles[lower] && !aliases[lower] {
-				seenTargets[lower] = true
-				results = append(results, UnresolvedLink{Target: link.Title, Source: relPath})
+			if titles[lower] || aliases[lower] {
+				continue
+			}
+			if hasFileExtension(link.Title) {
+				resolved, ok := attachments[lower]
+				if !ok {
+					_, resolved = resolveAttachment(v.dir, link.Title)
+					attachments[lower] = resolved
+				}
+				if resolved {
+					continue
+				}
 			}
+			seenTargets[lower] = true
+			results = append(results, UnresolvedLink{Target: link.Title, Source: relPath})
 		}
 		return nil
 	})
@@ -9,142 +9,192 @@ import (
 	"time"
 )
 
-// DailyResult is returned by Daily and reports what happened.
+// DailyResult is returned by Daily and Periodic and reports what happened.
 type DailyResult struct {
-	RelPath string // vault-relative path of the daily note
+	RelPath string // vault-relative path of the periodic note
 	Content string // file content (if the note already existed)
 	Created bool   // true if a new note was created
 }
 
-// dailyConfig holds the daily note configuration.
-type dailyConfig struct {
-	Folder   string // subfolder for daily notes (default: "")
-	Format   string // Go time format (default: "2006-01-02")
+// periodConfig holds the settings for one kind of periodic note.
+type periodConfig struct {
+	Folder   string // subfolder for the notes (default: "")
+	Format   string // Moment.js filename format
 	Template string // template note path (default: "")
 }
 
-// loadDailyConfig reads Obsidian's daily note settings from the vault's
-// .obsidian directory. Falls back to defaults.
-func loadDailyConfig(vaultDir string) dailyConfig {
-	config := dailyConfig{
-		Format: "2006-01-02",
-	}
+// Periods lists the periodic note kinds, shortest first.
+var Periods = []string{"daily", "weekly", "monthly", "quarterly", "yearly"}
+
+// periodDefaultFormats are the periodic-notes plugin's default formats.
+var periodDefaultFormats = map[string]string{
+	"daily":     "YYYY-MM-DD",
+	"weekly":    "gggg-[W]ww",
+	"monthly":   "YYYY-MM",
+	"quarterly": "YYYY-[Q]Q",
+	"yearly":    "YYYY",
+}
+
+// loadPeriodConfig reads the settings for a period from the vault's
+// .obsidian directory. Daily notes prefer the core daily-notes plugin;
+// every period can be configured in the periodic-notes plugin, which nests
+// settings under the period name. Falls back to defaults.
+func loadPeriodConfig(vaultDir, period string) periodConfig {
+	config := periodConfig{Format: periodDefaultFormats[period]}
 
 	// Try core daily-notes plugin first
-	corePath := filepath.Join(vaultDir, ".obsidian", "daily-notes.json")
-	if data, err := os.ReadFile(corePath); err == nil {
-		parseDailyJSON(data, &config)
-		return config
+	if period == "daily" {
+		corePath := filepath.Join(vaultDir, ".obsidian", "daily-notes.json")
+		if data, err := os.ReadFile(corePath); err == nil {
+			parsePeriodJSON(data, period, &config)
+			return config
+		}
 	}
 
 	// Try periodic-notes plugin
 	periodicPath := filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes", "data.json")
 	if data, err := os.ReadFile(periodicPath); err == nil {
-		parseDailyJSON(data, &config)
-		return config
+		parsePeriodJSON(data, period, &config)
 	}
 
 	return config
 }
 
-// parseDailyJSON extracts daily note settings from an Obsidian plugin config.
-func parseDailyJSON(data []byte, config *dailyConfig) {
+// parsePeriodJSON extracts a period's settings from an Obsidian plugin
+// config. Top-level keys (core daily-notes, older periodic-notes) apply to
+// daily notes only.
+func parsePeriodJSON(data []byte, period string, config *periodConfig) {
 	var raw map[string]any
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return
 	}
 
-	if folder, ok := raw["folder"].(string); ok && folder != "" {
-		config.Folder = folder
-	}
-	if format, ok := raw["format"].(string); ok && format != "" {
-		config.Format = MomentToGoFormat(format)
-	}
-	if template, ok := raw["template"].(string); ok && template != "" {
-		config.Template = template
-	}
-
-	// periodic-notes nests under "daily" key
-	if daily, ok := raw["daily"].(map[string]any); ok {
-		if folder, ok := daily["folder"].(string); ok && folder != "" {
+	apply := func(settings map[string]any) {
+		if folder, ok := settings["folder"].(string); ok && folder != "" {
 			config.Folder = folder
 		}
-		if format, ok := daily["format"].(string); ok && format != "" {
-			config.Format = MomentToGoFormat(format)
+		if format, ok := settings["format"].(string); ok && format != "" {
+			config.Format = format
 		}
-		if template, ok := daily["template"].(string); ok && template != "" {
+		if template, ok := settings["template"].(string); ok && template != "" {
 			config.Template = template
 		}
 	}
+
+	if period == "daily" {
+		apply(raw)
+	}
+	// periodic-notes nests under the period key
+	if settings, ok := raw[period].(map[string]any); ok {
+		apply(settings)
+	}
+}
+
+// periodStart returns the first day of the period containing date. Weeks
+// start on Monday when the format uses ISO week tokens (W, GGGG) and on
+// Sunday otherwise, matching Moment's default locale.
+func periodStart(period, format string, date time.Time) time.Time {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch period {
+	case "weekly":
+		weekStart := time.Sunday
+		for _, tok := range tokenizeMoment(format) {
+			if !tok.literal && (tok.value[0] == 'W' || tok.value[0] == 'G') {
+				weekStart = time.Monday
+			}
+		}
+		return date.AddDate(0, 0, -((7 + int(date.Weekday()) - int(weekStart)) % 7))
+	case "monthly":
+		return date.AddDate(0, 0, 1-date.Day())
+	case "quarterly":
+		return time.Date(date.Year(), (date.Month()-1)/3*3+1, 1, 0, 0, 0, 0, date.Location())
+	case "yearly":
+		return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location())
+	}
+	return date
+}
+
+// shiftPeriod moves a period start by n periods.
+func shiftPeriod(period string, start time.Time, n int) time.Time {
+	switch period {
+	case "weekly":
+		return start.AddDate(0, 0, 7*n)
+	case "monthly":
+		return start.AddDate(0, n, 0)
+	case "quarterly":
+		return start.AddDate(0, 3*n, 0)
+	case "yearly":
+		return start.AddDate(n, 0, 0)
+	}
+	return start.AddDate(0, 0, n)
 }
 
-// MomentToGoFormat translates common Moment.js date format tokens to Go's
-// reference time format. Uses a two-pass approach with placeholders to avoid
-// earlier replacements being corrupted by later ones (e.g., "a" inside "January").
-func MomentToGoFormat(moment string) string {
-	// Order matters: longest tokens first to avoid partial matches
-	replacements := []struct {
-		moment string
-		goFmt  string
-	}{
-		{"YYYY", "2006"},
-		{"YY", "06"},
-		{"MMMM", "January"},
-		{"MMM", "Jan"},
-		{"MM", "01"},
-		{"M", "1"},
-		{"DD", "02"},
-		{"D", "2"},
-		{"dddd", "Monday"},
-		{"ddd", "Mon"},
-		{"dd", "Mo"},
-		{"HH", "15"},
-		{"hh", "03"},
-		{"mm", "04"},
-		{"ss", "05"},
-		{"A", "PM"},
-		{"a", "pm"},
-	}
-
-	// Pass 1: replace Moment tokens with unique placeholders
-	result := moment
-	for i, r := range replacements {
-		placeholder := fmt.Sprintf("\x00%d\x00", i)
-		result = strings.ReplaceAll(result, r.moment, placeholder)
-	}
-
-	// Pass 2: replace placeholders with Go format strings
-	for i, r := range replacements {
-		placeholder := fmt.Sprintf("\x00%d\x00", i)
-		result = strings.ReplaceAll(result, placeholder, r.goFmt)
-	}
-
-	return result
+// periodNoteDate parses a periodic note's name or vault-relative path
+// back into the date it was created for, using the period's format.
+func periodNoteDate(config periodConfig, name string, loc *time.Location) (time.Time, error) {
+	name = strings.TrimSuffix(filepath.ToSlash(name), ".md")
+	if config.Folder != "" {
+		name = strings.TrimPrefix(name, strings.Trim(filepath.ToSlash(config.Folder), "/")+"/")
+	}
+	return ParseMoment(name, config.Format, loc)
+}
+
+// PeriodicDate returns the date a periodic note stands for, parsed from
+// its name or vault-relative path ("daily/2025-01-15.md") with the
+// period's configured format. Week, month, quarter and year notes map to
+// the first day of the period.
+func (v *Vault) PeriodicDate(period, name string) (time.Time, error) {
+	if _, ok := periodDefaultFormats[period]; !ok {
+		return time.Time{}, fmt.Errorf("unknown period %q (use %s)", period, strings.Join(Periods, ", "))
+	}
+	loc, err := TimeZone()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return periodNoteDate(loadPeriodConfig(v.dir, period), name, loc)
 }
 
 // Daily creates or reads a daily note.
 // With no date parameter (empty string), uses today.
-// With date="2025-01-15", uses that date.
+// With date="2025-01-15" or an expression such as "yesterday", uses that
+// date (see ResolveDate).
 func (v *Vault) Daily(dateStr string) (DailyResult, error) {
+	return v.Periodic("daily", dateStr, 0)
+}
+
+// Periodic creates or reads the periodic note ("daily", "weekly",
+// "monthly", "quarterly" or "yearly") for the period containing dateStr,
+// moved by offset periods (-1 for the previous one). dateStr is anything
+// ResolveDate accepts or a note name in the period's format ("2025-W03");
+// it defaults to today in the configured time zone. Folder, filename
+// format and template come from the periodic-notes plugin settings.
+func (v *Vault) Periodic(period, dateStr string, offset int) (DailyResult, error) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
-	config := loadDailyConfig(v.dir)
+	if _, ok := periodDefaultFormats[period]; !ok {
+		return DailyResult{}, fmt.Errorf("unknown period %q (use %s)", period, strings.Join(Periods, ", "))
+	}
+	config := loadPeriodConfig(v.dir, period)
 
 	// Determine the date
-	var date time.Time
+	now := vaultNow()
+	date := now
 	if dateStr != "" {
 		var err error
-		date, err = time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			return DailyResult{}, fmt.Errorf("invalid date format %q, expected YYYY-MM-DD", dateStr)
+		if date, err = ResolveDate(dateStr, now); err != nil {
+			noteDate, noteErr := periodNoteDate(config, dateStr, now.Location())
+			if noteErr != nil {
+				return DailyResult{}, err
+			}
+			date = noteDate
 		}
-	} else {
-		date = time.Now()
 	}
+	date = shiftPeriod(period, periodStart(period, config.Format, date), offset)
+	title := FormatMoment(date, config.Format)
 
 	// Compute filename from config format
-	filename := date.Format(config.Format) + ".md"
+	filename := title + ".md"
 	relPath := filename
 	if config.Folder != "" {
 		relPath = filepath.Join(config.Folder, filename)
@@ -152,7 +202,7 @@ func (v *Vault) Daily(dateStr string) (DailyResult, error) {
 
 	fullPath, pathErr := safePath(v.dir, relPath)
 	if pathErr != nil {
-		return DailyResult{}, fmt.Errorf("daily note path: %w", pathErr)
+		return DailyResult{}, fmt.Errorf("%s note path: %w", period, pathErr)
 	}
 
 	// If note exists, read and return it
@@ -173,16 +223,22 @@ func (v *Vault) Daily(dateStr string) (DailyResult, error) {
 		}
 		if tmplPath, tmplErr := safePath(v.dir, tmplRel); tmplErr == nil {
 			if tmplData, err := os.ReadFile(tmplPath); err == nil {
-				content = string(tmplData)
-				// Replace common template variables
-				content = strings.ReplaceAll(content, "{{date}}", date.Format("2006-01-02"))
-				content = strings.ReplaceAll(content, "{{title}}", date.Format(config.Format))
+				// {{date}} is relative to the period and {{time}} the current
+				// time; Templater's tp.date.now is always the current date.
+				ref := time.Date(date.Year(), date.Month(), date.Day(),
+					now.Hour(), now.Minute(), now.Second(), 0, date.Location())
+				content, err = renderTemplate(string(tmplData), templateContext{
+					VaultDir: v.dir, Title: title, RelPath: relPath, Date: ref, Now: now,
+				})
+				if err != nil {
+					return DailyResult{}, fmt.Errorf("%s template %s: %w", period, config.Template, err)
+				}
 			}
 		}
 	}
 
 	if content == "" {
-		content = fmt.Sprintf("# %s\n\n", date.Format(config.Format))
+		content = fmt.Sprintf("# %s\n\n", title)
 	}
 
 	// Ensure parent directory exists
@@ -8,36 +8,13 @@ import (
 	"time"
 )
 
-func TestMomentToGoFormat(t *testing.T) {
-	tests := []struct {
-		moment string
-		want   string
-	}{
-		{"YYYY-MM-DD", "2006-01-02"},
-		{"YY-M-D", "06-1-2"},
-		{"YYYY/MM/DD", "2006/01/02"},
-		{"dddd, MMMM D, YYYY", "Monday, January 2, 2006"},
-		{"ddd MMM DD", "Mon Jan 02"},
-		{"YYYY-MM-DD HH:mm", "2006-01-02 15:04"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.moment, func(t *testing.T) {
-			got := MomentToGoFormat(tt.moment)
-			if got != tt.want {
-				t.Errorf("MomentToGoFormat(%q) = %q, want %q", tt.moment, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestLoadDailyConfig_Default(t *testing.T) {
 	vaultDir := t.TempDir()
 
-	config := loadDailyConfig(vaultDir)
+	config := loadPeriodConfig(vaultDir, "daily")
 
-	if config.Format != "2006-01-02" {
-		t.Errorf("default format = %q, want %q", config.Format, "2006-01-02")
+	if config.Format != "YYYY-MM-DD" {
+		t.Errorf("default format = %q, want %q", config.Format, "YYYY-MM-DD")
 	}
 	if config.Folder != "" {
 		t.Errorf("default folder = %q, want empty", config.Folder)
@@ -57,13 +34,13 @@ func TestLoadDailyConfig_FromFile(t *testing.T) {
 		0644,
 	)
 
-	config := loadDailyConfig(vaultDir)
+	config := loadPeriodConfig(vaultDir, "daily")
 
 	if config.Folder != "daily" {
 		t.Errorf("folder = %q, want %q", config.Folder, "daily")
 	}
-	if config.Format != "2006/01/02" {
-		t.Errorf("format = %q, want %q", config.Format, "2006/01/02")
+	if config.Format != "YYYY/MM/DD" {
+		t.Errorf("format = %q, want %q", config.Format, "YYYY/MM/DD")
 	}
 	if config.Template != "_templates/daily" {
 		t.Errorf("template = %q, want %q", config.Template, "_templates/daily")
@@ -80,13 +57,13 @@ func TestLoadDailyConfig_PeriodicNotes(t *testing.T) {
 		0644,
 	)
 
-	config := loadDailyConfig(vaultDir)
+	config := loadPeriodConfig(vaultDir, "daily")
 
 	if config.Folder != "journal" {
 		t.Errorf("folder = %q, want %q", config.Folder, "journal")
 	}
-	if config.Format != "2006-01-02" {
-		t.Errorf("format = %q, want %q", config.Format, "2006-01-02")
+	if config.Format != "YYYY-MM-DD" {
+		t.Errorf("format = %q, want %q", config.Format, "YYYY-MM-DD")
 	}
 }
 
@@ -243,3 +220,128 @@ func TestDaily_InvalidDate(t *testing.T) {
 		t.Fatal("expected error for invalid date")
 	}
 }
+
+func TestLoadPeriodConfig_PerPeriod(t *testing.T) {
+	vaultDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes"), 0755)
+	os.WriteFile(
+		filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes", "data.json"),
+		[]byte(`{"daily":{"folder":"journal"},"weekly":{"folder":"reviews/weekly","format":"GGGG-[W]WW","template":"_templates/weekly"}}`),
+		0644,
+	)
+
+	weekly := loadPeriodConfig(vaultDir, "weekly")
+	if weekly.Folder != "reviews/weekly" || weekly.Format != "GGGG-[W]WW" || weekly.Template != "_templates/weekly" {
+		t.Errorf("weekly = %+v", weekly)
+	}
+	if monthly := loadPeriodConfig(vaultDir, "monthly"); monthly.Folder != "" || monthly.Format != "YYYY-MM" {
+		t.Errorf("monthly = %+v, want defaults", monthly)
+	}
+}
+
+func TestPeriodic(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	tests := []struct {
+		period, date string
+		offset       int
+		want         string
+	}{
+		{"weekly", "2025-01-01", 0, "2025-W01.md"},
+		{"weekly", "2024-12-28", 0, "2024-W52.md"},
+		{"weekly", "2024-12-28", 1, "2025-W01.md"},
+		{"monthly", "2025-01-31", 1, "2025-02.md"},
+		{"quarterly", "2025-05-20", -1, "2025-Q1.md"},
+		{"yearly", "2025-05-20", 0, "2025.md"},
+		{"daily", "2025-03-01", -1, "2025-02-28.md"},
+	}
+	for _, tt := range tests {
+		result, err := v.Periodic(tt.period, tt.date, tt.offset)
+		if err != nil {
+			t.Errorf("%s %s %+d: %v", tt.period, tt.date, tt.offset, err)
+			continue
+		}
+		if result.RelPath != tt.want {
+			t.Errorf("%s %s %+d = %s, want %s", tt.period, tt.date, tt.offset, result.RelPath, tt.want)
+		}
+	}
+
+	if _, err := v.Periodic("hourly", "", 0); err == nil {
+		t.Error("expected error for unknown period")
+	}
+
+	// A note name in the period's format selects that period.
+	result, err := v.Periodic("weekly", "2025-W10", 1)
+	if err != nil {
+		t.Fatalf("Periodic(2025-W10): %v", err)
+	}
+	if result.RelPath != "2025-W11.md" {
+		t.Errorf("Periodic(2025-W10, +1) = %s, want 2025-W11.md", result.RelPath)
+	}
+}
+
+func TestPeriodicDate(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes"), 0755)
+	os.WriteFile(
+		filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes", "data.json"),
+		[]byte(`{"daily":{"folder":"journal","format":"YYYY/MM/dddd, MMMM Do"},"weekly":{"format":"GGGG-[W]WW"}}`),
+		0644,
+	)
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	tests := []struct {
+		period, name string
+		want         string
+	}{
+		{"daily", "journal/2025/03/Saturday, March 1st.md", "2025-03-01"},
+		{"daily", "2025/03/Saturday, March 1st", "2025-03-01"},
+		{"weekly", "2025-W01.md", "2024-12-30"},
+		{"monthly", "2025-07", "2025-07-01"},
+		{"quarterly", "2025-Q3", "2025-07-01"},
+	}
+	for _, tt := range tests {
+		got, err := v.PeriodicDate(tt.period, tt.name)
+		if err != nil {
+			t.Errorf("PeriodicDate(%s, %q): %v", tt.period, tt.name, err)
+			continue
+		}
+		if got.Format("2006-01-02") != tt.want {
+			t.Errorf("PeriodicDate(%s, %q) = %s, want %s", tt.period, tt.name, got.Format("2006-01-02"), tt.want)
+		}
+	}
+
+	for _, name := range []string{"2025/03/Friday, March 1st", "2025-03-01", "Inbox"} {
+		if _, err := v.PeriodicDate("daily", name); err == nil {
+			t.Errorf("PeriodicDate(daily, %q): expected error", name)
+		}
+	}
+}
+
+func TestPeriodic_TemplateDate(t *testing.T) {
+	vaultDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "_templates"), 0755)
+	os.WriteFile(
+		filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes", "data.json"),
+		[]byte(`{"weekly":{"folder":"weekly","format":"GGGG-[W]WW","template":"_templates/weekly"}}`),
+		0644,
+	)
+	os.WriteFile(filepath.Join(vaultDir, "_templates", "weekly.md"), []byte("# {{title}}\nWeek of {{date}}\n"), 0644)
+
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	result, err := v.Periodic("weekly", "2025-06-19", 0)
+	if err != nil {
+		t.Fatalf("Periodic: %v", err)
+	}
+	if result.RelPath != filepath.Join("weekly", "2025-W25.md") {
+		t.Errorf("RelPath = %q", result.RelPath)
+	}
+	// ISO weeks start on Monday.
+	if result.Content != "# 2025-W25\nWeek of 2025-06-16\n" {
+		t.Errorf("content = %q", result.Content)
+	}
+}
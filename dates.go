@@ -0,0 +1,89 @@
+package vlt
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeZone returns the time zone used to decide what "today" is: the IANA
+// zone named by VLT_TIMEZONE (e.g. "America/New_York"), or the system's
+// local zone when unset.
+func TimeZone() (*time.Location, error) {
+	name := os.Getenv("VLT_TIMEZONE")
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("VLT_TIMEZONE: unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// vaultNow returns the current time in the configured time zone, falling
+// back to local time when VLT_TIMEZONE is invalid (the CLI rejects that
+// case up front).
+func vaultNow() time.Time {
+	loc, err := TimeZone()
+	if err != nil {
+		loc = time.Local
+	}
+	return time.Now().In(loc)
+}
+
+// relativeDatePattern matches offsets such as "+3d", "-1w", "+2m", "-1y".
+var relativeDatePattern = regexp.MustCompile(`^([+-]\d+)([dwmy])$`)
+
+// ResolveDate turns a date expression into a date (midnight, in now's time
+// zone). It accepts YYYY-MM-DD, "today", "yesterday", "tomorrow", offsets
+// from today ("+3d", "-1w", "+2m", "-1y") and weekdays relative to today
+// ("next monday" is the first Monday after today, "last friday" the most
+// recent Friday before it).
+func ResolveDate(expr string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	s := strings.ToLower(strings.Join(strings.Fields(expr), " "))
+
+	switch s {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if m := relativeDatePattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "d":
+			return today.AddDate(0, 0, n), nil
+		case "w":
+			return today.AddDate(0, 0, 7*n), nil
+		case "m":
+			return today.AddDate(0, n, 0), nil
+		default:
+			return today.AddDate(n, 0, 0), nil
+		}
+	}
+
+	if dir, day, ok := strings.Cut(s, " "); ok && (dir == "next" || dir == "last") {
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			if day != strings.ToLower(wd.String()) && day != strings.ToLower(wd.String()[:3]) {
+				continue
+			}
+			if dir == "next" {
+				return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+6)%7+1), nil
+			}
+			return today.AddDate(0, 0, -((int(today.Weekday())-int(wd)+6)%7 + 1)), nil
+		}
+	}
+
+	if d, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return d, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, today, yesterday, tomorrow, +3d, -1w, next monday or last friday)", expr)
+}
@@ -0,0 +1,64 @@
+package vlt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveDate(t *testing.T) {
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	now := time.Date(2025, 6, 18, 23, 30, 0, 0, loc) // a Wednesday
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"today", "2025-06-18"},
+		{"yesterday", "2025-06-17"},
+		{"Tomorrow", "2025-06-19"},
+		{"+3d", "2025-06-21"},
+		{"-1w", "2025-06-11"},
+		{"+2m", "2025-08-18"},
+		{"-1y", "2024-06-18"},
+		{"next monday", "2025-06-23"},
+		{"next wednesday", "2025-06-25"},
+		{"next thu", "2025-06-19"},
+		{"last friday", "2025-06-13"},
+		{"last  Wednesday", "2025-06-11"},
+		{"last tuesday", "2025-06-17"},
+		{"2025-01-15", "2025-01-15"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveDate(tt.expr, now)
+		if err != nil {
+			t.Errorf("ResolveDate(%q): %v", tt.expr, err)
+			continue
+		}
+		if got.Format("2006-01-02") != tt.want || got.Location() != loc || got.Hour() != 0 {
+			t.Errorf("ResolveDate(%q) = %v, want %s midnight in %s", tt.expr, got, tt.want, loc)
+		}
+	}
+
+	for _, bad := range []string{"", "someday", "next month", "+3x", "2025-13-01"} {
+		if _, err := ResolveDate(bad, now); err == nil {
+			t.Errorf("ResolveDate(%q): expected error", bad)
+		}
+	}
+}
+
+func TestTimeZone(t *testing.T) {
+	t.Setenv("VLT_TIMEZONE", "")
+	if loc, err := TimeZone(); err != nil || loc != time.Local {
+		t.Errorf("unset: %v, %v", loc, err)
+	}
+
+	t.Setenv("VLT_TIMEZONE", "UTC")
+	if loc, err := TimeZone(); err != nil || loc.String() != "UTC" {
+		t.Errorf("UTC: %v, %v", loc, err)
+	}
+
+	t.Setenv("VLT_TIMEZONE", "Mars/Olympus_Mons")
+	if _, err := TimeZone(); err == nil {
+		t.Error("expected error for unknown zone")
+	}
+}
@@ -0,0 +1,173 @@
+package vlt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp is one line of an edit script: ' ' kept, '-' deleted from a,
+// '+' inserted from b.
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// noNewline marks a last line that has no newline. No other line can end in
+// "\n", so "x" and "x\n" at the end of a file compare as different lines.
+const noNewline = "\n"
+
+// splitDiffLines splits text into lines without their newlines. A trailing
+// newline does not produce an empty last line; a missing one is recorded by
+// appending noNewline to the last line.
+func splitDiffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if !strings.HasSuffix(text, "\n") {
+		lines[len(lines)-1] += noNewline
+	}
+	return lines
+}
+
+// diffLines computes a shortest edit script turning a into b (Myers'
+// O((N+M)D) algorithm).
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil
+	}
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace backwards from (n, m), collecting operations in
+	// reverse.
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{'+', b[y-1]})
+			} else {
+				ops = append(ops, diffOp{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// unifiedDiff renders the changes from a to b as a unified diff with the
+// given lines of context, or "" when the texts are equal.
+func unifiedDiff(aName, bName, a, b string, context int) string {
+	ops := diffLines(splitDiffLines(a), splitDiffLines(b))
+
+	var sb strings.Builder
+	// aPos/bPos are the 0-based line numbers of ops[i] in a and b.
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if op.kind != '+' {
+			aPos[i+1]++
+		}
+		if op.kind != '-' {
+			bPos[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are at most 2*context lines apart.
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j
+			} else if j-end > 2*context {
+				break
+			}
+		}
+		stop := end + context + 1
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+		}
+		aCount, bCount := aPos[stop]-aPos[start], bPos[stop]-bPos[start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aPos[start], aCount), hunkRange(bPos[start], bCount))
+		for _, op := range ops[start:stop] {
+			sb.WriteByte(op.kind)
+			if text, ok := strings.CutSuffix(op.text, noNewline); ok {
+				sb.WriteString(text + "\n\\ No newline at end of file\n")
+				continue
+			}
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		i = stop
+	}
+	return sb.String()
+}
+
+// hunkRange formats a hunk range: "start,count" with a 1-based start, or
+// the preceding line number when count is zero.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
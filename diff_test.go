@@ -0,0 +1,67 @@
+package vlt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\nTWO\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+	got := unifiedDiff("a/n.md", "b/n.md", a, b, 1)
+	want := "--- a/n.md\n+++ b/n.md\n" +
+		"@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n" +
+		"@@ -10 +10,2 @@\n ten\n+eleven\n"
+	if got != want {
+		t.Errorf("unifiedDiff:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Changes close together share a hunk.
+	got = unifiedDiff("a", "b", "1\n2\n3\n4\n", "1\nx\n3\ny\n", 1)
+	if strings.Count(got, "@@ -") != 1 {
+		t.Errorf("expected a single hunk, got:\n%s", got)
+	}
+
+	if got := unifiedDiff("a", "b", a, a, 3); got != "" {
+		t.Errorf("equal texts: got %q, want empty", got)
+	}
+	if got := unifiedDiff("a", "b", "", "new\n", 3); got != "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n" {
+		t.Errorf("from empty: got %q", got)
+	}
+	if got := unifiedDiff("a", "b", "", "", 3); got != "" {
+		t.Errorf("both empty: got %q, want empty", got)
+	}
+
+	// A newline-only change is shown with diff -u's marker.
+	got = unifiedDiff("a", "b", "one\ntwo\n", "one\ntwo", 3)
+	want = "--- a\n+++ b\n@@ -1,2 +1,2 @@\n one\n-two\n+two\n\\ No newline at end of file\n"
+	if got != want {
+		t.Errorf("missing final newline:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffLines_RoundTrip(t *testing.T) {
+	cases := [][2]string{
+		{"a\nb\nc\n", "c\nb\na\n"},
+		{"x\n", ""},
+		{"a\nb\na\nb\n", "b\na\nb\na\nc\n"},
+		{"same\n", "same\n"},
+		{"", ""},
+		{"a\nb", "a\nb\n"},
+	}
+	for _, c := range cases {
+		var a, b []string
+		for _, op := range diffLines(splitDiffLines(c[0]), splitDiffLines(c[1])) {
+			if op.kind != '+' {
+				a = append(a, op.text)
+			}
+			if op.kind != '-' {
+				b = append(b, op.text)
+			}
+		}
+		if strings.Join(a, "\n") != strings.Join(splitDiffLines(c[0]), "\n") ||
+			strings.Join(b, "\n") != strings.Join(splitDiffLines(c[1]), "\n") {
+			t.Errorf("edit script for %q -> %q does not reproduce both sides", c[0], c[1])
+		}
+	}
+}
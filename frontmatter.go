@@ -162,6 +162,85 @@ func frontmatterRemoveKey(text, key string) string {
 	return strings.Join(result, "\n")
 }
 
+// frontmatterSetList sets key to a list of values in the note's frontmatter.
+// An existing key keeps its style: inline lists stay inline ([a, b]), block
+// lists keep their item indentation, and a scalar stays scalar when one value
+// remains. A missing key is added as a block list before the closing ---, and
+// frontmatter is created when the note has none.
+func frontmatterSetList(text, key string, values []string) string {
+	block := func(indent string) []string {
+		items := make([]string, len(values))
+		for i, v := range values {
+			items[i] = indent + "- " + v
+		}
+		return items
+	}
+
+	lines := strings.Split(text, "\n")
+	fmEnd := -1
+	if len(lines) >= 2 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				fmEnd = i
+				break
+			}
+		}
+	}
+	if fmEnd == -1 {
+		fm := append([]string{"---", key + ":"}, block("  ")...)
+		fm = append(fm, "---")
+		return strings.Join(fm, "\n") + "\n" + text
+	}
+
+	// Only top-level keys count: an indented "key:" belongs to a nested map.
+	prefix := key + ":"
+	keyLine := -1
+	for i := 1; i < fmEnd; i++ {
+		if strings.HasPrefix(lines[i], prefix) {
+			keyLine = i
+			break
+		}
+	}
+
+	var replacement []string
+	removeEnd := keyLine + 1
+	switch {
+	case keyLine == -1:
+		keyLine, removeEnd = fmEnd, fmEnd
+		replacement = append([]string{key + ":"}, block("  ")...)
+	default:
+		value := strings.TrimSpace(strings.TrimPrefix(lines[keyLine], prefix))
+		inline := prefix + " [" + strings.Join(values, ", ") + "]"
+		switch {
+		case strings.HasPrefix(value, "["):
+			replacement = []string{inline}
+		case value != "" && len(values) == 1:
+			replacement = []string{prefix + " " + values[0]}
+		case value != "":
+			replacement = []string{inline}
+		default:
+			itemIndent := "  "
+			for j := keyLine + 1; j < fmEnd; j++ {
+				t := strings.TrimSpace(lines[j])
+				if !strings.HasPrefix(t, "- ") {
+					break
+				}
+				if removeEnd == keyLine+1 {
+					itemIndent = lines[j][:len(lines[j])-len(strings.TrimLeft(lines[j], " \t"))]
+				}
+				removeEnd = j + 1
+			}
+			replacement = append([]string{prefix}, block(itemIndent)...)
+		}
+	}
+
+	result := make([]string, 0, len(lines)+len(replacement))
+	result = append(result, lines[:keyLine]...)
+	result = append(result, replacement...)
+	result = append(result, lines[removeEnd:]...)
+	return strings.Join(result, "\n")
+}
+
 // frontmatterReadAll returns the raw frontmatter block including --- delimiters.
 // Returns empty string if no frontmatter found.
 func frontmatterReadAll(text string) string {
@@ -195,6 +195,73 @@ func TestFrontmatterRemoveKey(t *testing.T) {
 	}
 }
 
+func TestFrontmatterSetList(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		values []string
+		want   string
+	}{
+		{
+			name:   "inline list stays inline",
+			text:   "---\ntags: [a, b]\n---\nbody\n",
+			values: []string{"a", "c"},
+			want:   "---\ntags: [a, c]\n---\nbody\n",
+		},
+		{
+			name:   "block list keeps indentation",
+			text:   "---\ntags:\n    - a\n    - b\nstatus: x\n---\n",
+			values: []string{"c"},
+			want:   "---\ntags:\n    - c\nstatus: x\n---\n",
+		},
+		{
+			name:   "scalar stays scalar with one value",
+			text:   "---\ntags: a\n---\n",
+			values: []string{"b"},
+			want:   "---\ntags: b\n---\n",
+		},
+		{
+			name:   "scalar becomes inline list",
+			text:   "---\ntags: a\n---\n",
+			values: []string{"a", "b"},
+			want:   "---\ntags: [a, b]\n---\n",
+		},
+		{
+			name:   "nested and prefixed keys are not the key",
+			text:   "---\nmeta:\n  tags: [x]\ntags_old: y\ntags: [a]\n---\n",
+			values: []string{"b"},
+			want:   "---\nmeta:\n  tags: [x]\ntags_old: y\ntags: [b]\n---\n",
+		},
+		{
+			name:   "nested key only: top-level key added",
+			text:   "---\nmeta:\n  tags: [x]\n---\n",
+			values: []string{"b"},
+			want:   "---\nmeta:\n  tags: [x]\ntags:\n  - b\n---\n",
+		},
+		{
+			name:   "missing key appended as block list",
+			text:   "---\ntype: note\n---\n",
+			values: []string{"a"},
+			want:   "---\ntype: note\ntags:\n  - a\n---\n",
+		},
+		{
+			name:   "frontmatter created",
+			text:   "# Note\n",
+			values: []string{"a", "b"},
+			want:   "---\ntags:\n  - a\n  - b\n---\n# Note\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := frontmatterSetList(tt.text, "tags", tt.values)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFrontmatterReadAll(t *testing.T) {
 	tests := []struct {
 		name string
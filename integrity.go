@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -41,10 +42,15 @@ func (s IntegrityStatus) String() string {
 	}
 }
 
+// MarshalJSON encodes the status as its label (e.g. "mismatch").
+func (s IntegrityStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
 // ReadResult wraps the content of a Read operation with its integrity status.
 type ReadResult struct {
-	Content   string
-	Integrity IntegrityStatus
+	Content   string          `json:"content"`
+	Integrity IntegrityStatus `json:"integrity"`
 }
 
 // registryEntry stores the hash and timestamp for a single tracked file.
@@ -55,10 +61,17 @@ type registryEntry struct {
 
 // Registry tracks content hashes for vault files written through vlt.
 type Registry struct {
-	dir     string                   // ~/.vlt/registries/<vault-id>/
-	entries map[string]registryEntry // keyed by vault-relative path
-	exists  bool                     // true if the registry file was loaded from disk
-	mu      sync.Mutex
+	dir       string                   // see registryDir
+	entries   map[string]registryEntry // keyed by vault-relative path
+	exists    bool                     // true if the registry file was loaded from disk
+	loadErr   error                    // set when registry.json exists but cannot be parsed
+	auditErr  error                    // first failure to append to the audit log
+	snapErr   error                    // first failure to save a snapshot
+	command   string                   // command recorded in the audit log
+	actor     string                   // actor recorded in the audit log
+	snapshots bool                     // keep compressed content snapshots (VLT_SNAPSHOTS=1)
+	policy    IntegrityPolicy          // what to do on mismatch (see resolvePolicy)
+	mu        sync.Mutex
 }
 
 // vaultID computes a stable identifier from a vault's absolute path.
@@ -72,23 +85,15 @@ func vaultID(vaultDir string) string {
 	return hex.EncodeToString(h[:8])
 }
 
-// registryDir returns the registry directory for a vault.
-// Layout: ~/.vlt/registries/<vault-id>/
-func registryDir(vaultDir string) string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		home = os.TempDir()
-	}
-	return filepath.Join(home, ".vlt", "registries", vaultID(vaultDir))
-}
-
 // openRegistry loads (or creates) a Registry for the given vault directory.
 func openRegistry(vaultDir string) *Registry {
 	dir := registryDir(vaultDir)
 	r := &Registry{
-		dir:     dir,
-		entries: make(map[string]registryEntry),
+		dir:       dir,
+		entries:   make(map[string]registryEntry),
+		snapshots: snapshotsEnabled(),
 	}
+	r.policy, _ = resolvePolicy(dir) // invalid settings fall back to warn; the CLI reports them
 
 	data, err := os.ReadFile(filepath.Join(dir, "registry.json"))
 	if err != nil {
@@ -96,7 +101,11 @@ func openRegistry(vaultDir string) *Registry {
 	}
 
 	if err := json.Unmarshal(data, &r.entries); err != nil {
-		return r // corrupted, start fresh
+		// Keep the corrupted file on disk for inspection: flush refuses to
+		// overwrite it until integrity:baseline rebuilds the registry.
+		r.entries = make(map[string]registryEntry)
+		r.loadErr = fmt.Errorf("integrity registry %s is corrupted: %v", filepath.Join(dir, "registry.json"), err)
+		return r
 	}
 	r.exists = true
 	return r
@@ -110,7 +119,8 @@ func contentHash(content []byte) string {
 
 // register records the hash of content written to absPath.
 // Must be called after a successful write, passing the content that was written
-// (not re-read from disk, to avoid TOCTOU).
+// (not re-read from disk, to avoid TOCTOU). Hash changes are appended to the
+// audit log, and with snapshots enabled the content itself is kept.
 func (r *Registry) register(vaultDir, absPath string, content []byte) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -120,8 +130,17 @@ func (r *Registry) register(vaultDir, absPath string, content []byte) {
 		return
 	}
 
+	hash := contentHash(content)
+	if old := r.entries[rel].Hash; old != hash {
+		r.logChange(rel, old, hash)
+	}
+	if r.snapshots {
+		if err := r.saveSnapshot(hash, content); err != nil && r.snapErr == nil {
+			r.snapErr = fmt.Errorf("cannot save snapshot of %s: %w", filepath.ToSlash(rel), err)
+		}
+	}
 	r.entries[rel] = registryEntry{
-		Hash: contentHash(content),
+		Hash: hash,
 		Ts:   time.Now().UTC().Format(time.RFC3339),
 	}
 	r.exists = true
@@ -138,10 +157,21 @@ func (r *Registry) deregister(vaultDir, absPath string) {
 		return
 	}
 
+	if old, ok := r.entries[rel]; ok {
+		r.logChange(rel, old.Hash, "")
+	}
 	delete(r.entries, rel)
 	r.flush()
 }
 
+// logChange appends a change to the audit log, keeping the first failure
+// for AuditError so unlogged writes are reported. Caller must hold r.mu.
+func (r *Registry) logChange(rel, oldHash, newHash string) {
+	if err := r.appendAudit(rel, oldHash, newHash); err != nil && r.auditErr == nil {
+		r.auditErr = fmt.Errorf("cannot append to the integrity audit log: %w", err)
+	}
+}
+
 // verify checks whether the content matches the registered hash.
 func (r *Registry) verify(vaultDir, absPath string, content []byte) IntegrityStatus {
 	r.mu.Lock()
@@ -168,8 +198,11 @@ func (r *Registry) verify(vaultDir, absPath string, content []byte) IntegritySta
 }
 
 // flush writes the registry to disk atomically (write temp + rename).
-// Caller must hold r.mu.
+// A corrupted registry is left untouched. Caller must hold r.mu.
 func (r *Registry) flush() {
+	if r.loadErr != nil {
+		return
+	}
 	if err := os.MkdirAll(r.dir, 0700); err != nil {
 		return
 	}
@@ -231,11 +264,64 @@ func (v *Vault) VerifyIntegrity(paths ...string) map[string]IntegrityStatus {
 	return results
 }
 
+// RegistryError reports why the integrity registry could not be loaded
+// (nil when it loaded or does not exist yet).
+func (v *Vault) RegistryError() error {
+	v.registry.mu.Lock()
+	defer v.registry.mu.Unlock()
+	return v.registry.loadErr
+}
+
+// AuditError reports the first write made through this vault that could not
+// be appended to the audit log (nil when every change was logged). The
+// registry is still updated in that case, so callers should surface it.
+func (v *Vault) AuditError() error {
+	v.registry.mu.Lock()
+	defer v.registry.mu.Unlock()
+	return v.registry.auditErr
+}
+
+// SnapshotError reports the first snapshot that could not be saved by a
+// write made through this vault (nil when all were saved or snapshots are
+// off). integrity:diff and integrity:restore cannot use that version.
+func (v *Vault) SnapshotError() error {
+	v.registry.mu.Lock()
+	defer v.registry.mu.Unlock()
+	return v.registry.snapErr
+}
+
 // IntegrityBaseline walks all .md files in the vault and registers each one.
-func (v *Vault) IntegrityBaseline() error {
+// A corrupted registry is moved aside to registry.json.corrupt and rebuilt.
+// Files whose hash the audit log has not recorded yet (e.g. registered
+// before the log existed) are recorded too. When a registry exists but its
+// audit log is missing, that could be a deleted log being re-seeded, so
+// force is required.
+func (v *Vault) IntegrityBaseline(force bool) error {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
+	v.registry.mu.Lock()
+	logPath := filepath.Join(v.registry.dir, auditLogName)
+	// A corrupted registry has no entries loaded, but it had some on disk.
+	hadEntries := len(v.registry.entries) > 0 || v.registry.loadErr != nil
+	if _, err := os.Stat(logPath); os.IsNotExist(err) && hadEntries && !force {
+		v.registry.mu.Unlock()
+		return fmt.Errorf("the registry has entries but no audit log at %s (deleted?); use force to start a new log from the current files", logPath)
+	}
+	if v.registry.loadErr != nil {
+		regPath := filepath.Join(v.registry.dir, "registry.json")
+		if err := os.Rename(regPath, regPath+".corrupt"); err != nil {
+			v.registry.mu.Unlock()
+			return err
+		}
+		v.registry.loadErr = nil
+	}
+	logged, err := v.registry.auditLatest()
+	v.registry.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
 	return filepath.WalkDir(v.dir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
@@ -251,7 +337,24 @@ func (v *Vault) IntegrityBaseline() error {
 		if readErr != nil {
 			return nil
 		}
+		rel, relErr := filepath.Rel(v.dir, path)
+		if relErr != nil {
+			return nil
+		}
+		hash := contentHash(data)
+		v.registry.mu.Lock()
+		unchanged := v.registry.entries[rel].Hash == hash
+		v.registry.mu.Unlock()
+
 		v.registry.register(v.dir, path, data)
+
+		// register only logs hash changes; also log files the audit log
+		// has never seen at this hash.
+		if last := logged[filepath.ToSlash(rel)]; unchanged && last != hash {
+			v.registry.mu.Lock()
+			v.registry.logChange(rel, last, hash)
+			v.registry.mu.Unlock()
+		}
 		return nil
 	})
 }
@@ -155,7 +155,7 @@ func TestReadIntegrity(t *testing.T) {
 	}
 
 	// Run baseline
-	if err := v.IntegrityBaseline(); err != nil {
+	if err := v.IntegrityBaseline(false); err != nil {
 		t.Fatalf("IntegrityBaseline: %v", err)
 	}
 
@@ -181,6 +181,50 @@ func TestReadIntegrity(t *testing.T) {
 	}
 }
 
+func TestSearchAndTasksIntegrity(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+
+	for _, name := range []string{"Clean.md", "Tampered.md"} {
+		path := filepath.Join(vaultDir, name)
+		os.WriteFile(path, []byte("- [ ] ship it\n"), 0644)
+		v.registry.register(vaultDir, path, []byte("- [ ] ship it\n"))
+	}
+	os.WriteFile(filepath.Join(vaultDir, "Tampered.md"), []byte("- [ ] ship it now\n"), 0644)
+	want := map[string]IntegrityStatus{"Clean.md": IntegrityOK, "Tampered.md": IntegrityMismatch}
+
+	results, err := v.Search(SearchOptions{Query: "ship"})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Search = %v, %v", results, err)
+	}
+	for _, r := range results {
+		if r.Integrity != want[r.RelPath] {
+			t.Errorf("Search %s: integrity %s, want %s", r.RelPath, r.Integrity, want[r.RelPath])
+		}
+	}
+
+	matches, err := v.SearchWithContext(SearchOptions{Query: "ship", ContextN: 1})
+	if err != nil || len(matches) != 2 {
+		t.Fatalf("SearchWithContext = %v, %v", matches, err)
+	}
+	for _, m := range matches {
+		if m.Integrity != want[m.File] {
+			t.Errorf("SearchWithContext %s: integrity %s, want %s", m.File, m.Integrity, want[m.File])
+		}
+	}
+
+	tasks, err := v.Tasks(TaskOptions{})
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("Tasks = %v, %v", tasks, err)
+	}
+	for _, task := range tasks {
+		if task.Integrity != want[task.File] {
+			t.Errorf("Tasks %s: integrity %s, want %s", task.File, task.Integrity, want[task.File])
+		}
+	}
+}
+
 // TestWriteRegisters verifies that Create, Write, Append, Prepend register
 // the content hash automatically.
 func TestWriteRegisters(t *testing.T) {
@@ -294,7 +338,7 @@ func TestDeleteDeregisters(t *testing.T) {
 	}
 
 	// Delete permanently
-	_, err = v.Delete("Temporary", "", true)
+	_, err = v.Delete("Temporary", "", DeleteOptions{Permanent: true})
 	if err != nil {
 		t.Fatalf("Delete: %v", err)
 	}
@@ -318,7 +362,7 @@ func TestIntegrityBaseline(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "sub", "C.md"), []byte("# C\n"), 0644)
 	os.WriteFile(filepath.Join(vaultDir, "ignore.txt"), []byte("not markdown"), 0644)
 
-	if err := v.IntegrityBaseline(); err != nil {
+	if err := v.IntegrityBaseline(false); err != nil {
 		t.Fatalf("IntegrityBaseline: %v", err)
 	}
 
@@ -342,7 +386,7 @@ func TestIntegrityAcknowledge(t *testing.T) {
 	content := "# Note\n"
 	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte(content), 0644)
 
-	if err := v.IntegrityBaseline(); err != nil {
+	if err := v.IntegrityBaseline(false); err != nil {
 		t.Fatalf("IntegrityBaseline: %v", err)
 	}
 
@@ -375,7 +419,7 @@ func TestIntegrityAcknowledgeSince(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("# A\n"), 0644)
 	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("# B\n"), 0644)
 
-	if err := v.IntegrityBaseline(); err != nil {
+	if err := v.IntegrityBaseline(false); err != nil {
 		t.Fatalf("IntegrityBaseline: %v", err)
 	}
 
@@ -410,7 +454,7 @@ func TestIntegrityStatusAll(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "OK.md"), []byte("# OK\n"), 0644)
 	os.WriteFile(filepath.Join(vaultDir, "Modified.md"), []byte("# Original\n"), 0644)
 
-	if err := v.IntegrityBaseline(); err != nil {
+	if err := v.IntegrityBaseline(false); err != nil {
 		t.Fatalf("IntegrityBaseline: %v", err)
 	}
 
@@ -512,7 +556,7 @@ func TestVerifyIntegrity(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("# A\n"), 0644)
 	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("# B\n"), 0644)
 
-	if err := v.IntegrityBaseline(); err != nil {
+	if err := v.IntegrityBaseline(false); err != nil {
 		t.Fatalf("IntegrityBaseline: %v", err)
 	}
 
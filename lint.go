@@ -0,0 +1,301 @@
+package vlt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// lintConfigPath is the vault-relative location of the lint configuration.
+const lintConfigPath = ".vlt/lint.yaml"
+
+// TagLintConfig is the "tags" section of .vlt/lint.yaml:
+//
+//	tags:
+//	  case: kebab            # any (default), lower, kebab, snake or camel
+//	  allowed-prefixes:      # tags must be one of these or a subtag of one
+//	    - project
+//	    - status
+//	  required:              # folder -> tags every note below it must carry
+//	    projects: [project]
+//	    meetings:
+//	      - meeting
+type TagLintConfig struct {
+	Case            string
+	AllowedPrefixes []string
+	Required        map[string][]string
+}
+
+// LintViolation is a single rule violation reported by a lint command.
+type LintViolation struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Tag     string `json:"tag,omitempty"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// tagCasePatterns maps case rules to the pattern each tag segment must match.
+var tagCasePatterns = map[string]*regexp.Regexp{
+	"lower": regexp.MustCompile(`^[^\p{Lu}]+$`),
+	"kebab": regexp.MustCompile(`^[\p{Ll}\p{N}]+(-[\p{Ll}\p{N}]+)*$`),
+	"snake": regexp.MustCompile(`^[\p{Ll}\p{N}]+(_[\p{Ll}\p{N}]+)*$`),
+	"camel": regexp.MustCompile(`^\p{Ll}[\p{L}\p{N}]*$`),
+}
+
+// loadLintConfig reads and parses .vlt/lint.yaml.
+func loadLintConfig(vaultDir string) (map[string]any, error) {
+	data, err := os.ReadFile(filepath.Join(vaultDir, filepath.FromSlash(lintConfigPath)))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no lint configuration found (create %s)", lintConfigPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseYAMLConfig(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", lintConfigPath, err)
+	}
+	return cfg, nil
+}
+
+// parseTagLintConfig extracts the tags section from a parsed lint config.
+func parseTagLintConfig(cfg map[string]any) (TagLintConfig, error) {
+	var tc TagLintConfig
+	section, ok := cfg["tags"].(map[string]any)
+	if !ok {
+		return tc, fmt.Errorf("%s has no tags section", lintConfigPath)
+	}
+
+	tc.Case, _ = section["case"].(string)
+	if tc.Case != "" && tc.Case != "any" && tagCasePatterns[tc.Case] == nil {
+		return tc, fmt.Errorf("%s: unknown tags.case %q (use any, lower, kebab, snake or camel)", lintConfigPath, tc.Case)
+	}
+
+	for _, p := range yamlStringList(section["allowed-prefixes"]) {
+		tc.AllowedPrefixes = append(tc.AllowedPrefixes, strings.Trim(strings.TrimPrefix(p, "#"), "/"))
+	}
+
+	if raw, ok := section["required"]; ok && raw != nil {
+		required, ok := raw.(map[string]any)
+		if !ok {
+			return tc, fmt.Errorf("%s: tags.required must map folders to tag lists", lintConfigPath)
+		}
+		tc.Required = make(map[string][]string)
+		for folder, tags := range required {
+			for _, t := range yamlStringList(tags) {
+				tc.Required[folder] = append(tc.Required[folder], strings.TrimPrefix(t, "#"))
+			}
+		}
+	}
+	return tc, nil
+}
+
+// frontmatterLineHasTag reports whether a line of the frontmatter tags list
+// holds tag: as a "- tag" item, or, on the "tags:" key line itself, as the
+// scalar value or an element of an inline [a, b] list.
+func frontmatterLineHasTag(line string, isKeyLine bool, tag string) bool {
+	unquote := func(s string) string { return strings.Trim(strings.TrimSpace(s), `"'`) }
+	if !isKeyLine {
+		item, ok := strings.CutPrefix(strings.TrimSpace(line), "-")
+		return ok && unquote(item) == tag
+	}
+	value := strings.TrimSpace(strings.TrimPrefix(line, "tags:"))
+	if inner, ok := strings.CutPrefix(value, "["); ok {
+		for _, item := range strings.Split(strings.TrimSuffix(inner, "]"), ",") {
+			if unquote(item) == tag {
+				return true
+			}
+		}
+		return false
+	}
+	return unquote(value) == tag
+}
+
+// tagOccurrence is a tag as written in a note, with its 1-based line.
+type tagOccurrence struct {
+	Tag  string
+	Line int
+}
+
+// noteTagOccurrences returns every tag in a note with the line it appears on,
+// keeping the original spelling: frontmatter tags first, then inline tags
+// (outside inert zones). Unlike AllNoteTags, nothing is lowercased or
+// deduplicated.
+func noteTagOccurrences(text string) []tagOccurrence {
+	var result []tagOccurrence
+	lines := strings.Split(text, "\n")
+
+	yaml, bodyStart, hasFM := ExtractFrontmatter(text)
+	if hasFM {
+		keyLine := 1
+		for i := 1; i < bodyStart-1; i++ {
+			if strings.HasPrefix(lines[i], "tags:") {
+				keyLine = i
+				break
+			}
+		}
+		// Tags are listed in order, so each search resumes where the
+		// previous one matched (on the same line for inline lists).
+		from := keyLine
+		for _, t := range FrontmatterGetList(yaml, "tags") {
+			line := keyLine + 1
+			for i := from; i < bodyStart-1; i++ {
+				if i > keyLine && !strings.HasPrefix(strings.TrimSpace(lines[i]), "-") {
+					break
+				}
+				if frontmatterLineHasTag(lines[i], i == keyLine, t) {
+					line, from = i+1, i+1
+					if i == keyLine {
+						from = i
+					}
+					break
+				}
+			}
+			result = append(result, tagOccurrence{Tag: strings.TrimPrefix(t, "#"), Line: line})
+		}
+	}
+
+	start := bodyOffset(text)
+	masked := MaskInertContent(text)
+	for _, m := range tagPattern.FindAllStringSubmatchIndex(masked[start:], -1) {
+		tag := text[start+m[2] : start+m[3]]
+		if !hasLetter(tag) {
+			continue
+		}
+		line := strings.Count(text[:start+m[2]], "\n") + 1
+		result = append(result, tagOccurrence{Tag: tag, Line: line})
+	}
+	return result
+}
+
+// hasTagOrSubtag reports whether tags (lowercased, as from AllNoteTags)
+// include tag or one of its subtags.
+func hasTagOrSubtag(tags []string, tag string) bool {
+	lower := strings.ToLower(tag)
+	for _, t := range tags {
+		if t == lower || strings.HasPrefix(t, lower+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// lintNoteTags checks one note against the tag rules.
+func lintNoteTags(relPath, text string, tc TagLintConfig) []LintViolation {
+	var violations []LintViolation
+	slashPath := filepath.ToSlash(relPath)
+
+	seen := make(map[string]bool)
+	for _, occ := range noteTagOccurrences(text) {
+		if pattern := tagCasePatterns[tc.Case]; pattern != nil {
+			for _, seg := range strings.Split(occ.Tag, "/") {
+				if !pattern.MatchString(seg) {
+					key := fmt.Sprintf("case:%d:%s", occ.Line, occ.Tag)
+					if !seen[key] {
+						seen[key] = true
+						violations = append(violations, LintViolation{
+							Path: relPath, Line: occ.Line, Tag: occ.Tag, Rule: "case",
+							Message: fmt.Sprintf("tag #%s is not %s case", occ.Tag, tc.Case),
+						})
+					}
+					break
+				}
+			}
+		}
+
+		if len(tc.AllowedPrefixes) > 0 {
+			allowed := false
+			for _, p := range tc.AllowedPrefixes {
+				if len(occ.Tag) >= len(p) && strings.EqualFold(occ.Tag[:len(p)], p) &&
+					(len(occ.Tag) == len(p) || occ.Tag[len(p)] == '/') {
+					allowed = true
+					break
+				}
+			}
+			key := fmt.Sprintf("prefix:%d:%s", occ.Line, occ.Tag)
+			if !allowed && !seen[key] {
+				seen[key] = true
+				violations = append(violations, LintViolation{
+					Path: relPath, Line: occ.Line, Tag: occ.Tag, Rule: "prefix",
+					Message: fmt.Sprintf("tag #%s is not under an allowed prefix (%s)", occ.Tag, strings.Join(tc.AllowedPrefixes, ", ")),
+				})
+			}
+		}
+	}
+
+	folders := make([]string, 0, len(tc.Required))
+	for folder := range tc.Required {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+
+	var noteTags []string
+	for _, folder := range folders {
+		f := strings.Trim(filepath.ToSlash(folder), "/")
+		if f != "" && f != "." && !strings.HasPrefix(slashPath, f+"/") {
+			continue
+		}
+		if noteTags == nil {
+			noteTags = AllNoteTags(text)
+		}
+		for _, req := range tc.Required[folder] {
+			if !hasTagOrSubtag(noteTags, req) {
+				violations = append(violations, LintViolation{
+					Path: relPath, Line: 1, Tag: req, Rule: "required",
+					Message: fmt.Sprintf("missing required tag #%s for notes in %s", req, folder),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// LintTags checks every note against the tag rules in .vlt/lint.yaml:
+// casing, allowed prefixes and required tags per folder. Violations are
+// sorted by path and line.
+func (v *Vault) LintTags() ([]LintViolation, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	cfg, err := loadLintConfig(v.dir)
+	if err != nil {
+		return nil, err
+	}
+	tc, err := parseTagLintConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []LintViolation
+	err = filepath.WalkDir(v.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipHiddenDir(path, d, v.dir) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		relPath, _ := filepath.Rel(v.dir, path)
+		violations = append(violations, lintNoteTags(relPath, string(data), tc)...)
+		return nil
+	})
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Path != violations[j].Path {
+			return violations[i].Path < violations[j].Path
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, err
+}
@@ -0,0 +1,107 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeLintConfig(t *testing.T, vaultDir, config string) {
+	t.Helper()
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	if err := os.WriteFile(filepath.Join(vaultDir, ".vlt", "lint.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNoteTagOccurrences(t *testing.T) {
+	text := "---\ntitle: x\ntags:\n  - Alpha\n  - beta\n---\nBody #Gamma here\n```\n#skipped\n```\n#delta/Sub\n"
+	got := noteTagOccurrences(text)
+	want := []tagOccurrence{{"Alpha", 4}, {"beta", 5}, {"Gamma", 7}, {"delta/Sub", 11}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("occurrence %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNoteTagOccurrences_ExactFrontmatterLines(t *testing.T) {
+	text := "---\ntags:\n  - alpha\n  - a\n  - \"b\"\ntitle: a\n---\n"
+	want := []tagOccurrence{{"alpha", 3}, {"a", 4}, {"b", 5}}
+	got := noteTagOccurrences(text)
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("occurrence %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	inline := noteTagOccurrences("---\nalias: a\ntags: [ab, a]\n---\n")
+	if len(inline) != 2 || inline[0].Line != 3 || inline[1].Line != 3 {
+		t.Errorf("inline list occurrences = %v, want both on line 3", inline)
+	}
+}
+
+func TestLintTags(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	writeLintConfig(t, vaultDir, `tags:
+  case: kebab
+  allowed-prefixes: [project, status]
+  required:
+    projects:
+      - project
+`)
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Good.md"), []byte("---\ntags: [project/apollo]\n---\n#status/active\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Bad.md"), []byte("Tagged #Status/Active and #random.\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Loose.md"), []byte("No tags, no folder rule.\n"), 0644)
+
+	violations, err := v.LintTags()
+	if err != nil {
+		t.Fatalf("LintTags: %v", err)
+	}
+
+	var got []string
+	for _, lv := range violations {
+		got = append(got, lv.Path+":"+lv.Rule+":"+lv.Tag)
+		if lv.Line != 1 {
+			t.Errorf("%s %s: line = %d, want 1", lv.Path, lv.Rule, lv.Line)
+		}
+	}
+	bad := filepath.Join("projects", "Bad.md")
+	want := []string{
+		bad + ":case:Status/Active",
+		bad + ":prefix:random",
+		bad + ":required:project",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLintTags_ConfigErrors(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	if _, err := v.LintTags(); err == nil || !strings.Contains(err.Error(), lintConfigPath) {
+		t.Errorf("missing config: got %v", err)
+	}
+
+	writeLintConfig(t, vaultDir, "tags:\n  case: shouting\n")
+	if _, err := v.LintTags(); err == nil {
+		t.Error("expected error for unknown case rule")
+	}
+
+	writeLintConfig(t, vaultDir, "other: 1\n")
+	if _, err := v.LintTags(); err == nil {
+		t.Error("expected error for missing tags section")
+	}
+}
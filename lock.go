@@ -1,22 +1,53 @@
 package vlt
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
 // WriteCommands lists CLI commands that require an exclusive vault lock; all others use a shared lock.
 var writeCommands = map[string]bool{
-	"create":                true,
-	"append":                true,
-	"prepend":               true,
-	"write":                 true,
-	"patch":                 true,
-	"move":                  true,
-	"delete":                true,
-	"property:set":          true,
-	"property:remove":       true,
-	"daily":                 true,
-	"templates:apply":       true,
-	"bookmarks:add":         true,
-	"bookmarks:remove":      true,
-	"integrity:baseline":    true,
-	"integrity:acknowledge": true,
+	"create":                 true,
+	"append":                 true,
+	"prepend":                true,
+	"write":                  true,
+	"patch":                  true,
+	"move":                   true,
+	"delete":                 true,
+	"attachments:clean":      true,
+	"canvas:add-node":        true,
+	"trash:restore":          true,
+	"trash:empty":            true,
+	"tag:rename":             true,
+	"tag:add":                true,
+	"tag:remove":             true,
+	"task:done":              true,
+	"task:undo":              true,
+	"task:set":               true,
+	"task:add":               true,
+	"property:set":           true,
+	"property:remove":        true,
+	"daily":                  true,
+	"weekly":                 true,
+	"monthly":                true,
+	"quarterly":              true,
+	"yearly":                 true,
+	"templates:apply":        true,
+	"templates:insert":       true,
+	"bookmarks:add":          true,
+	"bookmarks:remove":       true,
+	"bookmarks:add-group":    true,
+	"bookmarks:rename-group": true,
+	"bookmarks:delete-group": true,
+	"integrity:baseline":     true,
+	"integrity:acknowledge":  true,
+	"integrity:restore":      true,
+	"integrity:policy":       true,
+	"integrity:migrate":      true,
 }
 
 // IsWriteCommand returns true if cmd is a write command requiring an exclusive lock.
@@ -26,3 +57,208 @@ func IsWriteCommand(cmd string) bool {
 
 // LockFileName is the advisory lock file placed in the vault root.
 const LockFileName = ".vlt.lock"
+
+// lockPollInterval is how often a lock with a timeout is retried.
+const lockPollInterval = 25 * time.Millisecond
+
+// errLockBusy is returned by tryLockFile when another process holds a
+// conflicting lock.
+var errLockBusy = errors.New("lock is busy")
+
+// ErrVaultLocked is wrapped by every *VaultLockedError, so callers can test
+// with errors.Is.
+var ErrVaultLocked = fmt.Errorf("vault is locked")
+
+// LockHolder describes the process holding the exclusive vault lock, as
+// recorded in the lock file.
+type LockHolder struct {
+	PID     int       `json:"pid"`
+	Command string    `json:"command,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+// VaultLockedError is returned when the vault lock could not be taken
+// before the timeout (or at once, with NoWait).
+type VaultLockedError struct {
+	Holder *LockHolder   // nil when the holder is a reader or unrecorded
+	Waited time.Duration // zero with NoWait
+}
+
+func (e *VaultLockedError) Error() string {
+	msg := ErrVaultLocked.Error()
+	if h := e.Holder; h != nil {
+		msg += " by " + describeHolder(h)
+	} else {
+		msg += " (holder not recorded: a reader using --strict-flock, an older vlt or another tool)"
+	}
+	if e.Waited > 0 {
+		msg += fmt.Sprintf("; gave up after %s", e.Waited)
+	}
+	return msg
+}
+
+func (e *VaultLockedError) Unwrap() error {
+	return ErrVaultLocked
+}
+
+// describeHolder formats a holder as "pid 123 (append, since ..., running)".
+func describeHolder(h *LockHolder) string {
+	state := "not running"
+	if processAlive(h.PID) {
+		state = "running"
+	}
+	command := h.Command
+	if command == "" {
+		command = "unknown command"
+	}
+	return fmt.Sprintf("pid %d (%s, since %s, %s)", h.PID, command, h.Started.Format(time.RFC3339), state)
+}
+
+// LockOptions controls how LockVaultWith waits for the vault lock.
+type LockOptions struct {
+	Exclusive bool          // exclusive (write) lock; shared (read) otherwise
+	Timeout   time.Duration // give up after this long; zero waits forever
+	NoWait    bool          // fail at once if the lock is held
+	Command   string        // recorded in the lock file for exclusive locks
+}
+
+// LockVault acquires an advisory lock on the vault directory, waiting as
+// long as it takes. If exclusive is true an exclusive (write) lock is taken;
+// otherwise a shared (read) lock is taken. The returned function releases
+// the lock.
+func LockVault(vaultDir string, exclusive bool) (func(), error) {
+	return LockVaultWith(vaultDir, LockOptions{Exclusive: exclusive})
+}
+
+// LockVaultWith acquires an advisory lock on the vault directory. With a
+// Timeout or NoWait it returns a *VaultLockedError instead of blocking
+// indefinitely. Exclusive holders record their PID, command and start time
+// in the lock file until they release it.
+func LockVaultWith(vaultDir string, opts LockOptions) (func(), error) {
+	p := filepath.Join(vaultDir, LockFileName)
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Timeout <= 0 && !opts.NoWait {
+		err = lockFile(f, opts.Exclusive)
+	} else {
+		deadline := time.Now().Add(opts.Timeout)
+		for {
+			err = tryLockFile(f, opts.Exclusive)
+			if err != errLockBusy {
+				break
+			}
+			if opts.NoWait || !time.Now().Before(deadline) {
+				lockedErr := &VaultLockedError{Holder: exclusiveHolder(f, p)}
+				if !opts.NoWait {
+					lockedErr.Waited = opts.Timeout
+				}
+				err = lockedErr
+				break
+			}
+			time.Sleep(lockPollInterval)
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if opts.Exclusive {
+		writeLockHolder(f, opts.Command)
+	}
+	return func() {
+		if opts.Exclusive {
+			f.Truncate(0)
+		}
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// writeLockHolder records this process as the lock holder. The record
+// starts at offset 1: Windows locks byte 0, so other processes could not
+// read it there.
+func writeLockHolder(f *os.File, command string) {
+	data, err := json.Marshal(LockHolder{PID: os.Getpid(), Command: command, Started: time.Now().UTC().Truncate(time.Second)})
+	if err != nil {
+		return
+	}
+	f.Truncate(0)
+	f.WriteAt(append(append([]byte("\n"), data...), '\n'), 0)
+}
+
+// readLockHolder returns the holder recorded in the lock file, or nil when
+// there is none (shared locks are not recorded) or it cannot be parsed.
+func readLockHolder(path string) *LockHolder {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.Size() <= 1 {
+		return nil
+	}
+	buf := make([]byte, info.Size()-1)
+	n, _ := f.ReadAt(buf, 1)
+	var h LockHolder
+	if err := json.Unmarshal(buf[:n], &h); err != nil || h.PID == 0 {
+		return nil
+	}
+	return &h
+}
+
+// exclusiveHolder returns the holder recorded in the lock file at p for a
+// lock found busy, or nil when only readers hold it: a record left behind
+// by a writer that crashed must not be blamed for their lock. f is a handle
+// on p that does not hold the lock.
+func exclusiveHolder(f *os.File, p string) *LockHolder {
+	if tryLockFile(f, false) == nil {
+		unlockFile(f)
+		return nil
+	}
+	return readLockHolder(p)
+}
+
+// LockStatus reports the state of a vault's advisory lock.
+type LockStatus struct {
+	Locked bool        `json:"locked"`
+	Holder *LockHolder `json:"holder,omitempty"`
+	// Alive reports whether the holder's process is still running. A
+	// holder recorded while the vault is unlocked was left behind by a
+	// process that exited without releasing the lock cleanly.
+	Alive bool `json:"alive"`
+}
+
+// VaultLockStatus probes the vault lock without waiting and returns who
+// holds it. The caller must not hold the lock itself.
+func VaultLockStatus(vaultDir string) (LockStatus, error) {
+	var status LockStatus
+	p := filepath.Join(vaultDir, LockFileName)
+	f, err := os.OpenFile(p, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+	defer f.Close()
+
+	switch err := tryLockFile(f, true); err {
+	case nil:
+		unlockFile(f)
+		status.Holder = readLockHolder(p)
+	case errLockBusy:
+		status.Locked = true
+		status.Holder = exclusiveHolder(f, p)
+	default:
+		return status, err
+	}
+	if status.Holder != nil {
+		status.Alive = processAlive(status.Holder.PID)
+	}
+	return status, nil
+}
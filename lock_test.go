@@ -3,6 +3,7 @@
 package vlt
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"syscall"
@@ -98,11 +99,123 @@ func TestLockVaultCreatesLockFile(t *testing.T) {
 	}
 }
 
+func TestLockVaultWith_NoWaitAndTimeout(t *testing.T) {
+	dir := t.TempDir()
+
+	unlock, err := LockVaultWith(dir, LockOptions{Exclusive: true, Command: "append"})
+	if err != nil {
+		t.Fatalf("first exclusive lock: %v", err)
+	}
+
+	_, err = LockVaultWith(dir, LockOptions{Exclusive: true, NoWait: true})
+	var locked *VaultLockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrVaultLocked) {
+		t.Fatalf("NoWait err = %v, want *VaultLockedError", err)
+	}
+	if locked.Holder == nil || locked.Holder.PID != os.Getpid() || locked.Holder.Command != "append" {
+		t.Errorf("holder = %+v, want this process running append", locked.Holder)
+	}
+	if locked.Waited != 0 {
+		t.Errorf("Waited = %s, want 0 with NoWait", locked.Waited)
+	}
+
+	// Readers with a timeout give up too.
+	start := time.Now()
+	_, err = LockVaultWith(dir, LockOptions{Timeout: 100 * time.Millisecond})
+	if !errors.As(err, &locked) || locked.Waited != 100*time.Millisecond {
+		t.Fatalf("timeout err = %v, want *VaultLockedError after 100ms", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("gave up after %s, want at least the timeout", elapsed)
+	}
+
+	// Once released, a waiting lock succeeds before its timeout.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		unlock()
+	}()
+	unlock2, err := LockVaultWith(dir, LockOptions{Exclusive: true, Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("lock after release: %v", err)
+	}
+	unlock2()
+}
+
+func TestVaultLockStatus(t *testing.T) {
+	dir := t.TempDir()
+
+	status, err := VaultLockStatus(dir)
+	if err != nil || status.Locked || status.Holder != nil {
+		t.Fatalf("status without lock file = %+v, %v", status, err)
+	}
+
+	unlock, err := LockVaultWith(dir, LockOptions{Exclusive: true, Command: "write"})
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	status, err = VaultLockStatus(dir)
+	if err != nil {
+		t.Fatalf("VaultLockStatus: %v", err)
+	}
+	if !status.Locked || status.Holder == nil || status.Holder.PID != os.Getpid() || !status.Alive {
+		t.Errorf("status while locked = %+v, want held by this live process", status)
+	}
+	if status.Holder != nil && (status.Holder.Command != "write" || status.Holder.Started.IsZero()) {
+		t.Errorf("holder = %+v, want command and start time", status.Holder)
+	}
+
+	unlock()
+	status, _ = VaultLockStatus(dir)
+	if status.Locked || status.Holder != nil {
+		t.Errorf("status after unlock = %+v, want unlocked with no holder", status)
+	}
+
+	// Shared locks are detected but have no recorded holder.
+	unlockShared, err := LockVault(dir, false)
+	if err != nil {
+		t.Fatalf("shared lock: %v", err)
+	}
+	defer unlockShared()
+	status, _ = VaultLockStatus(dir)
+	if !status.Locked || status.Holder != nil {
+		t.Errorf("status with a reader = %+v, want locked without holder", status)
+	}
+
+	// A record left by a crashed writer is not blamed for a reader's lock.
+	stale := "\n{\"pid\":999999999,\"command\":\"write\",\"started\":\"2026-01-02T03:04:05Z\"}\n"
+	if err := os.WriteFile(filepath.Join(dir, LockFileName), []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	status, _ = VaultLockStatus(dir)
+	if !status.Locked || status.Holder != nil {
+		t.Errorf("status with a reader and a stale record = %+v, want locked without holder", status)
+	}
+	_, err = LockVaultWith(dir, LockOptions{Exclusive: true, NoWait: true})
+	var lockedErr *VaultLockedError
+	if !errors.As(err, &lockedErr) || lockedErr.Holder != nil {
+		t.Errorf("no-wait writer behind a reader = %v, want *VaultLockedError without holder", err)
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Error("processAlive(self) = false")
+	}
+	if processAlive(0) || processAlive(-1) {
+		t.Error("processAlive reports invalid PIDs as alive")
+	}
+}
+
 func TestIsWriteCommand(t *testing.T) {
 	writes := []string{
 		"create", "append", "prepend", "write", "patch",
 		"move", "delete", "property:set", "property:remove",
-		"daily", "templates:apply", "bookmarks:add", "bookmarks:remove",
+		"daily", "weekly", "monthly", "quarterly", "yearly", "templates:apply", "templates:insert", "bookmarks:add", "bookmarks:remove",
+		"bookmarks:add-group", "bookmarks:rename-group", "bookmarks:delete-group",
+		"integrity:restore", "integrity:policy", "integrity:migrate",
+		"attachments:clean", "canvas:add-node", "trash:restore", "trash:empty",
+		"tag:rename", "tag:add", "tag:remove",
+		"task:done", "task:undo", "task:set", "task:add",
 	}
 	for _, cmd := range writes {
 		if !IsWriteCommand(cmd) {
@@ -113,7 +226,10 @@ func TestIsWriteCommand(t *testing.T) {
 	reads := []string{
 		"read", "search", "properties", "backlinks", "links",
 		"orphans", "unresolved", "tags", "tag", "files",
-		"tasks", "templates", "bookmarks", "uri",
+		"tasks", "templates", "bookmarks", "uri", "attachments:unused", "integrity:audit", "integrity:diff",
+		"canvas:read", "trash", "tags:related",
+		"lint:tags", "lock:status",
+		"validate",
 	}
 	for _, cmd := range reads {
 		if IsWriteCommand(cmd) {
@@ -4,32 +4,41 @@ package vlt
 
 import (
 	"os"
-	"path/filepath"
 	"syscall"
 )
 
-// lockVault acquires an advisory lock on the vault directory.
-// If exclusive is true an exclusive (write) lock is taken; otherwise a shared
-// (read) lock is taken. The returned function releases the lock.
-func LockVault(vaultDir string, exclusive bool) (func(), error) {
-	p := filepath.Join(vaultDir, LockFileName)
-	f, err := os.OpenFile(p, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		return nil, err
+// lockFile takes a shared or exclusive flock on f, blocking until it is
+// available.
+func lockFile(f *os.File, exclusive bool) error {
+	return syscall.Flock(int(f.Fd()), flockHow(exclusive)) // #nosec G115 -- file descriptors fit in int
+}
+
+// tryLockFile is like lockFile but returns errLockBusy instead of waiting.
+func tryLockFile(f *os.File, exclusive bool) error {
+	err := syscall.Flock(int(f.Fd()), flockHow(exclusive)|syscall.LOCK_NB) // #nosec G115 -- file descriptors fit in int
+	if err == syscall.EWOULDBLOCK {
+		return errLockBusy
 	}
+	return err
+}
+
+// unlockFile releases the flock on f.
+func unlockFile(f *os.File) {
+	syscall.Flock(int(f.Fd()), syscall.LOCK_UN) // #nosec G115 -- file descriptors fit in int
+}
 
-	fd := int(f.Fd()) // #nosec G115 -- file descriptors fit in int
-	how := syscall.LOCK_SH
+func flockHow(exclusive bool) int {
 	if exclusive {
-		how = syscall.LOCK_EX
-	}
-	if err := syscall.Flock(fd, how); err != nil {
-		f.Close()
-		return nil, err
+		return syscall.LOCK_EX
 	}
+	return syscall.LOCK_SH
+}
 
-	return func() {
-		syscall.Flock(fd, syscall.LOCK_UN)
-		f.Close()
-	}, nil
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
 }
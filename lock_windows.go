@@ -4,7 +4,6 @@ package vlt
 
 import (
 	"os"
-	"path/filepath"
 	"syscall"
 	"unsafe"
 )
@@ -20,45 +19,68 @@ var (
 )
 
 const (
-	lockfileExclusiveLock = 0x00000002
+	lockfileFailImmediately = 0x00000001
+	lockfileExclusiveLock   = 0x00000002
+	errorLockViolation      = syscall.Errno(33)
+	processQueryLimitedInfo = 0x1000
 )
 
-func LockVault(vaultDir string, exclusive bool) (func(), error) {
-	p := filepath.Join(vaultDir, LockFileName)
-	f, err := os.OpenFile(p, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		return nil, err
-	}
+// lockFile locks the first byte of f, blocking until it is available.
+func lockFile(f *os.File, exclusive bool) error {
+	return lockFileEx(f, exclusive, 0)
+}
 
-	h := syscall.Handle(f.Fd())
-	ol := new(syscall.Overlapped)
+// tryLockFile is like lockFile but returns errLockBusy instead of waiting.
+func tryLockFile(f *os.File, exclusive bool) error {
+	err := lockFileEx(f, exclusive, lockfileFailImmediately)
+	if err == errorLockViolation {
+		return errLockBusy
+	}
+	return err
+}
 
-	var flags uint32
+func lockFileEx(f *os.File, exclusive bool, flags uint32) error {
 	if exclusive {
-		flags = lockfileExclusiveLock
+		flags |= lockfileExclusiveLock
 	}
-
 	r1, _, e1 := procLockFileEx.Call(
-		uintptr(h),
+		uintptr(syscall.Handle(f.Fd())),
 		uintptr(flags),
 		0, // reserved
 		1, // nNumberOfBytesToLockLow
 		0, // nNumberOfBytesToLockHigh
-		uintptr(unsafe.Pointer(ol)),
+		uintptr(unsafe.Pointer(new(syscall.Overlapped))),
 	)
 	if r1 == 0 {
-		f.Close()
-		return nil, e1
+		return e1
 	}
+	return nil
+}
 
-	return func() {
-		procUnlockFileEx.Call(
-			uintptr(h),
-			0,
-			1,
-			0,
-			uintptr(unsafe.Pointer(ol)),
-		)
-		f.Close()
-	}, nil
+// unlockFile releases the lock on the first byte of f.
+func unlockFile(f *os.File) {
+	procUnlockFileEx.Call(
+		uintptr(syscall.Handle(f.Fd())),
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(new(syscall.Overlapped))),
+	)
+}
+
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	h, err := syscall.OpenProcess(processQueryLimitedInfo, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return true
+	}
+	return code == 259 // STILL_ACTIVE
 }
@@ -1,6 +1,7 @@
 package vlt
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -559,7 +560,7 @@ func TestCmdDelete_Trash(t *testing.T) {
 	notePath := filepath.Join(vaultDir, "ToTrash.md")
 	os.WriteFile(notePath, []byte("# Delete me\n"), 0644)
 
-	if _, err := v.Delete("ToTrash", "", false); err != nil {
+	if _, err := v.Delete("ToTrash", "", DeleteOptions{}); err != nil {
 		t.Fatalf("delete (trash): %v", err)
 	}
 
@@ -582,7 +583,7 @@ func TestCmdDelete_Permanent(t *testing.T) {
 	notePath := filepath.Join(vaultDir, "ToDelete.md")
 	os.WriteFile(notePath, []byte("# Delete me\n"), 0644)
 
-	if _, err := v.Delete("ToDelete", "", true); err != nil {
+	if _, err := v.Delete("ToDelete", "", DeleteOptions{Permanent: true}); err != nil {
 		t.Fatalf("delete (permanent): %v", err)
 	}
 
@@ -597,6 +598,117 @@ func TestCmdDelete_Permanent(t *testing.T) {
 	}
 }
 
+func TestCmdDelete_RefusesWithBacklinks(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "Target.md"), []byte("---\naliases: [TG]\n---\nSee [[Target]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("Link to [[Target]].\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("Via alias [[TG|the target]].\n"), 0644)
+
+	res, err := v.Delete("Target", "", DeleteOptions{})
+	if !errors.Is(err, ErrHasBacklinks) {
+		t.Fatalf("expected ErrHasBacklinks, got %v", err)
+	}
+	if strings.Join(res.Backlinks, ",") != "A.md,B.md" {
+		t.Errorf("Backlinks = %v, want [A.md B.md] (self-link excluded)", res.Backlinks)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "Target.md")); err != nil {
+		t.Error("note should not be deleted when refused")
+	}
+
+	if _, err := v.Delete("Target", "", DeleteOptions{Links: "leave"}); err != nil {
+		t.Fatalf("delete links=leave: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "A.md"))
+	if string(data) != "Link to [[Target]].\n" {
+		t.Errorf("links=leave modified A.md: %q", data)
+	}
+}
+
+func TestCmdDelete_Unlink(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "Target.md"), []byte("---\naliases: [TG]\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("Link to [[Target]] and [[TG|the target]].\n"), 0644)
+
+	res, err := v.Delete("Target", "", DeleteOptions{Links: "unlink"})
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if res.LinksUpdated != 2 {
+		t.Errorf("LinksUpdated = %d, want 2 (one pass per title)", res.LinksUpdated)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "A.md"))
+	if string(data) != "Link to Target and the target.\n" {
+		t.Errorf("got %q", data)
+	}
+}
+
+func TestCmdDelete_UnlinkCanvas(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "Target.md"), []byte("# Target\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Board.canvas"), []byte(`{"nodes":[
+		{"id":"a","type":"file","file":"Target.md","x":0,"y":0,"width":400,"height":400},
+		{"id":"b","type":"text","text":"See [[Target]]","x":500,"y":0,"width":250,"height":60}
+	],"edges":[]}`), 0644)
+
+	res, err := v.Delete("Target", "", DeleteOptions{Links: "unlink"})
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if res.LinksUpdated != 1 {
+		t.Errorf("LinksUpdated = %d, want 1", res.LinksUpdated)
+	}
+	c, _, err := v.CanvasRead("Board")
+	if err != nil {
+		t.Fatalf("read canvas: %v", err)
+	}
+	if n := c.Nodes[0]; n.Type != "text" || n.Text != "Target" || n.File != "" {
+		t.Errorf("file node = %+v, want text node \"Target\"", n)
+	}
+	if c.Nodes[1].Text != "See Target" {
+		t.Errorf("text node = %q, want unlinked", c.Nodes[1].Text)
+	}
+	if refs, _ := FindBacklinks(vaultDir, "Target"); len(refs) != 0 {
+		t.Errorf("dangling references remain: %v", refs)
+	}
+}
+
+func TestCmdDelete_Redirect(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "Old.md"), []byte("old\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "New.md"), []byte("new\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("See [[Old#Intro|intro]].\n"), 0644)
+
+	if _, err := v.Delete("Old", "", DeleteOptions{Links: "redirect"}); err == nil {
+		t.Error("expected error for redirect without target")
+	}
+	if _, err := v.Delete("Old", "", DeleteOptions{Links: "redirect", RedirectTo: "Old"}); err == nil {
+		t.Error("expected error redirecting to the deleted note")
+	}
+
+	res, err := v.Delete("Old", "", DeleteOptions{Links: "redirect", RedirectTo: "New"})
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if res.LinksUpdated != 1 {
+		t.Errorf("LinksUpdated = %d, want 1", res.LinksUpdated)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "A.md"))
+	if string(data) != "See [[New#Intro|intro]].\n" {
+		t.Errorf("got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "Old.md")); !os.IsNotExist(err) {
+		t.Error("Old.md should be trashed")
+	}
+}
+
 func TestCmdProperties(t *testing.T) {
 	vaultDir := t.TempDir()
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
@@ -611,7 +723,7 @@ func TestCmdProperties(t *testing.T) {
 	if err != nil {
 		t.Fatalf("properties: %v", err)
 	}
-	if result == "" {
+	if result.Content == "" {
 		t.Error("expected non-empty properties result")
 	}
 }
@@ -2900,7 +3012,7 @@ func TestDeletePathTraversal(t *testing.T) {
 	vaultDir := t.TempDir()
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
 
-	_, err := v.Delete("", "../outside.md", true)
+	_, err := v.Delete("", "../outside.md", DeleteOptions{Permanent: true})
 	if err == nil {
 		t.Fatal("Delete with traversal path should fail")
 	}
@@ -2944,13 +3056,13 @@ func TestTemplatesApplyPathTraversal(t *testing.T) {
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
 
 	// Traversal in template name
-	err := v.TemplatesApply("../../etc/passwd", "Note", "notes/Note.md")
+	err := v.TemplatesApply("../../etc/passwd", "Note", "notes/Note.md", nil)
 	if err == nil {
 		t.Fatal("TemplatesApply with traversal template should fail")
 	}
 
 	// Traversal in note path
-	err = v.TemplatesApply("default", "Note", "../outside.md")
+	err = v.TemplatesApply("default", "Note", "../outside.md", nil)
 	if err == nil {
 		t.Fatal("TemplatesApply with traversal note path should fail")
 	}
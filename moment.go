@@ -0,0 +1,592 @@
+package vlt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// momentToken is one element of a parsed Moment.js format string: either a
+// format token such as "YYYY" or a literal run of text.
+type momentToken struct {
+	value   string
+	literal bool
+}
+
+// momentTokenNames lists the supported Moment.js tokens, longest first so
+// that "YYYY" wins over "YY" and "Do" over "D".
+var momentTokenNames = []string{
+	"YYYY", "YY", "Y",
+	"gggg", "gg", "GGGG", "GG",
+	"Qo", "Q",
+	"MMMM", "MMM", "Mo", "MM", "M",
+	"DDDD", "DDDo", "DDD", "Do", "DD", "D",
+	"dddd", "ddd", "dd", "do", "d", "e", "E",
+	"wo", "ww", "w", "Wo", "WW", "W",
+	"HH", "H", "hh", "h", "kk", "k",
+	"mm", "m", "ss", "s",
+	"SSS", "SS", "S",
+	"A", "a",
+	"X", "x",
+	"ZZ", "Z",
+}
+
+// tokenizeMoment splits a Moment.js format into tokens. Text inside
+// [brackets] and a character after a backslash are literal; characters
+// that start no token are literal too.
+func tokenizeMoment(format string) []momentToken {
+	var tokens []momentToken
+	addLiteral := func(s string) {
+		if n := len(tokens); n > 0 && tokens[n-1].literal {
+			tokens[n-1].value += s
+			return
+		}
+		tokens = append(tokens, momentToken{value: s, literal: true})
+	}
+
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i+1:], ']'); end >= 0 {
+				addLiteral(format[i+1 : i+1+end])
+				i += end + 2
+				continue
+			}
+		}
+		if format[i] == '\\' && i+1 < len(format) {
+			_, size := utf8.DecodeRuneInString(format[i+1:])
+			addLiteral(format[i+1 : i+1+size])
+			i += 1 + size
+			continue
+		}
+		matched := false
+		for _, name := range momentTokenNames {
+			if strings.HasPrefix(format[i:], name) {
+				tokens = append(tokens, momentToken{value: name})
+				i += len(name)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			_, size := utf8.DecodeRuneInString(format[i:])
+			addLiteral(format[i : i+size])
+			i += size
+		}
+	}
+	return tokens
+}
+
+// momentFirstWeekOffset returns the offset from January 1 of year to the
+// first day of its week 1, for a week starting on weekday dow whose first
+// week contains January (7 + dow - doy).
+func momentFirstWeekOffset(year, dow, doy int) int {
+	fwd := 7 + dow - doy
+	fwdlw := (7 + int(time.Date(year, time.January, fwd, 0, 0, 0, 0, time.UTC).Weekday()) - dow) % 7
+	return -fwdlw + fwd - 1
+}
+
+// momentWeeksInYear returns the number of weeks in a week-numbering year.
+func momentWeeksInYear(year, dow, doy int) int {
+	days := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	return (days - momentFirstWeekOffset(year, dow, doy) + momentFirstWeekOffset(year+1, dow, doy)) / 7
+}
+
+// momentWeek returns the week-numbering year and week of t for a week
+// that starts on weekday dow and whose first week contains January
+// (7 + dow - doy), following Moment.js. ISO weeks use dow=1, doy=4; the
+// default "en" locale used by Obsidian uses dow=0, doy=6.
+func momentWeek(t time.Time, dow, doy int) (year, week int) {
+	year = t.Year()
+	n := t.YearDay() - momentFirstWeekOffset(year, dow, doy) - 1
+	week = n/7 + 1
+	if n < 0 {
+		week = 0 // floor division: the days before week 1
+	}
+	switch {
+	case week < 1:
+		year--
+		week += momentWeeksInYear(year, dow, doy)
+	case week > momentWeeksInYear(year, dow, doy):
+		week -= momentWeeksInYear(year, dow, doy)
+		year++
+	}
+	return year, week
+}
+
+// momentWeekDate is the inverse of momentWeek: the date of weekday
+// (0=Sunday) in the given week of a week-numbering year.
+func momentWeekDate(year, week int, weekday time.Weekday, dow, doy int, loc *time.Location) time.Time {
+	localWeekday := (7 + int(weekday) - dow) % 7
+	dayOfYear := 1 + 7*(week-1) + localWeekday + momentFirstWeekOffset(year, dow, doy)
+	return time.Date(year, time.January, dayOfYear, 0, 0, 0, 0, loc)
+}
+
+// FormatMoment formats t with a Moment.js format string, including tokens
+// Go layouts cannot express such as ordinals (Do), day of year (DDDD),
+// week numbers (ww, WW), week years (gggg, GGGG) and quarters (Q).
+// Bracketed and backslash-escaped text is copied literally.
+func FormatMoment(t time.Time, format string) string {
+	var b strings.Builder
+	for _, tok := range tokenizeMoment(format) {
+		if tok.literal {
+			b.WriteString(tok.value)
+			continue
+		}
+		b.WriteString(formatMomentToken(t, tok.value))
+	}
+	return b.String()
+}
+
+// formatMomentToken renders a single Moment.js token in the "en" locale.
+func formatMomentToken(t time.Time, token string) string {
+	switch token {
+	case "YYYY", "YY":
+		return formatMomentYear(t.Year(), token)
+	case "Y":
+		return strconv.Itoa(t.Year())
+	case "gggg", "gg":
+		year, _ := momentWeek(t, 0, 6)
+		return formatMomentYear(year, token)
+	case "GGGG", "GG":
+		year, _ := momentWeek(t, 1, 4)
+		return formatMomentYear(year, token)
+	case "Q":
+		return strconv.Itoa((int(t.Month())-1)/3 + 1)
+	case "Qo":
+		return momentOrdinal((int(t.Month())-1)/3 + 1)
+	case "MMMM":
+		return t.Month().String()
+	case "MMM":
+		return t.Month().String()[:3]
+	case "Mo":
+		return momentOrdinal(int(t.Month()))
+	case "MM", "M":
+		return padMoment(int(t.Month()), len(token))
+	case "DDDD":
+		return fmt.Sprintf("%03d", t.YearDay())
+	case "DDDo":
+		return momentOrdinal(t.YearDay())
+	case "DDD":
+		return strconv.Itoa(t.YearDay())
+	case "Do":
+		return momentOrdinal(t.Day())
+	case "DD", "D":
+		return padMoment(t.Day(), len(token))
+	case "dddd":
+		return t.Weekday().String()
+	case "ddd":
+		return t.Weekday().String()[:3]
+	case "dd":
+		return t.Weekday().String()[:2]
+	case "do":
+		return momentOrdinal(int(t.Weekday()))
+	case "d", "e":
+		return strconv.Itoa(int(t.Weekday()))
+	case "E":
+		return strconv.Itoa((int(t.Weekday())+6)%7 + 1)
+	case "ww", "w":
+		_, week := momentWeek(t, 0, 6)
+		return padMoment(week, len(token))
+	case "wo":
+		_, week := momentWeek(t, 0, 6)
+		return momentOrdinal(week)
+	case "WW", "W":
+		_, week := momentWeek(t, 1, 4)
+		return padMoment(week, len(token))
+	case "Wo":
+		_, week := momentWeek(t, 1, 4)
+		return momentOrdinal(week)
+	case "HH", "H":
+		return padMoment(t.Hour(), len(token))
+	case "hh", "h":
+		return padMoment((t.Hour()+11)%12+1, len(token))
+	case "kk", "k":
+		return padMoment((t.Hour()+23)%24+1, len(token))
+	case "mm", "m":
+		return padMoment(t.Minute(), len(token))
+	case "ss", "s":
+		return padMoment(t.Second(), len(token))
+	case "SSS", "SS", "S":
+		return fmt.Sprintf("%09d", t.Nanosecond())[:len(token)]
+	case "A":
+		return t.Format("PM")
+	case "a":
+		return t.Format("pm")
+	case "X":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "x":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case "Z":
+		return t.Format("-07:00")
+	case "ZZ":
+		return t.Format("-0700")
+	}
+	return token
+}
+
+// formatMomentYear renders a year as four digits or, for two-letter
+// tokens, its last two digits.
+func formatMomentYear(year int, token string) string {
+	if len(token) == 2 {
+		return fmt.Sprintf("%02d", year%100)
+	}
+	return fmt.Sprintf("%04d", year)
+}
+
+// padMoment renders n, zero-padded to two digits when width is 2.
+func padMoment(n, width int) string {
+	if width == 2 {
+		return fmt.Sprintf("%02d", n)
+	}
+	return strconv.Itoa(n)
+}
+
+// momentOrdinal renders n with its English ordinal suffix: 1st, 2nd, 11th.
+func momentOrdinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// momentGoLayouts maps the Moment.js tokens that have a Go layout
+// equivalent.
+var momentGoLayouts = map[string]string{
+	"YYYY": "2006", "YY": "06",
+	"MMMM": "January", "MMM": "Jan", "MM": "01", "M": "1",
+	"DDDD": "002", "DD": "02", "D": "2",
+	"dddd": "Monday", "ddd": "Mon",
+	"HH": "15", "hh": "03", "h": "3",
+	"mm": "04", "m": "4", "ss": "05", "s": "5",
+	"SSS": "000", "SS": "00", "S": "0",
+	"A": "PM", "a": "pm",
+	"Z": "-07:00", "ZZ": "-0700",
+}
+
+// MomentToGoFormat translates a Moment.js date format to Go's reference
+// time layout. Tokens Go cannot express (Do, ww, Q, gggg, ...) are kept
+// as-is, and literal text is copied unchanged even where Go would read it
+// as a layout element, so prefer FormatMoment and ParseMoment, which
+// handle every token.
+func MomentToGoFormat(moment string) string {
+	var b strings.Builder
+	for _, tok := range tokenizeMoment(moment) {
+		if layout, ok := momentGoLayouts[tok.value]; ok && !tok.literal {
+			b.WriteString(layout)
+			continue
+		}
+		b.WriteString(tok.value)
+	}
+	return b.String()
+}
+
+// momentFields collects the values read by ParseMoment.
+type momentFields struct {
+	year, month, day, yearDay, quarter int
+	weekYear, week                     int
+	weekday                            time.Weekday
+	isoWeek                            bool
+	hour, minute, second, nsec         int
+	pm                                 string // "am", "pm" or "" when absent
+	offset                             *int   // seconds east of UTC
+	unix                               *time.Time
+
+	hasYear, hasMonth, hasDay, hasYearDay, hasQuarter bool
+	hasWeekYear, hasWeek, hasWeekday                  bool
+}
+
+// ParseMoment parses value, such as a periodic note's filename, with a
+// Moment.js format string; it is the inverse of FormatMoment. Missing
+// fields default as in time.Parse (year 0, January 1, midnight), a
+// quarter or week without a day means its first day, and the result is in
+// loc unless the format has an offset (Z, ZZ). Parsing is strict: the
+// value must format back to itself, so "2025-02-30" or a weekday that
+// does not match the date are errors.
+func ParseMoment(value, format string, loc *time.Location) (time.Time, error) {
+	fail := func() (time.Time, error) {
+		return time.Time{}, fmt.Errorf("%q does not match date format %q", value, format)
+	}
+
+	var f momentFields
+	rest := value
+	for _, tok := range tokenizeMoment(format) {
+		if tok.literal {
+			if len(rest) < len(tok.value) || !strings.EqualFold(rest[:len(tok.value)], tok.value) {
+				return fail()
+			}
+			rest = rest[len(tok.value):]
+			continue
+		}
+		var ok bool
+		if rest, ok = parseMomentToken(tok.value, rest, &f); !ok {
+			return fail()
+		}
+	}
+	if rest != "" {
+		return fail()
+	}
+
+	t := f.time(loc)
+	if !strings.EqualFold(FormatMoment(t, format), value) {
+		return fail()
+	}
+	return t, nil
+}
+
+// time assembles the parsed fields into a time.
+func (f *momentFields) time(loc *time.Location) time.Time {
+	if f.unix != nil {
+		return f.unix.In(loc)
+	}
+	if f.offset != nil {
+		loc = time.FixedZone("", *f.offset)
+	}
+
+	hour := f.hour
+	switch f.pm {
+	case "am":
+		hour %= 12
+	case "pm":
+		hour = hour%12 + 12
+	}
+
+	var date time.Time
+	switch {
+	case f.hasWeek:
+		year := f.weekYear
+		if !f.hasWeekYear {
+			year = f.year
+		}
+		dow, doy := 0, 6
+		if f.isoWeek {
+			dow, doy = 1, 4
+		}
+		weekday := time.Weekday(dow)
+		if f.hasWeekday {
+			weekday = f.weekday
+		}
+		date = momentWeekDate(year, f.week, weekday, dow, doy, loc)
+	case f.hasYearDay:
+		date = time.Date(f.year, time.January, f.yearDay, 0, 0, 0, 0, loc)
+	default:
+		month, day := 1, 1
+		if f.hasQuarter {
+			month = (f.quarter-1)*3 + 1
+		}
+		if f.hasMonth {
+			month = f.month
+		}
+		if f.hasDay {
+			day = f.day
+		}
+		date = time.Date(f.year, time.Month(month), day, 0, 0, 0, 0, loc)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, f.minute, f.second, f.nsec, loc)
+}
+
+// parseMomentToken reads one token's value from the start of s into f and
+// returns the remaining input.
+func parseMomentToken(token, s string, f *momentFields) (string, bool) {
+	num := func(minDigits, maxDigits int) (int, bool) {
+		n := 0
+		for n < maxDigits && n < len(s) && s[n] >= '0' && s[n] <= '9' {
+			n++
+		}
+		if n < minDigits {
+			return 0, false
+		}
+		v, _ := strconv.Atoi(s[:n])
+		s = s[n:]
+		return v, true
+	}
+	ordinal := func() (int, bool) {
+		v, ok := num(1, 3)
+		if !ok || len(s) < 2 {
+			return 0, false
+		}
+		s = s[2:] // the suffix is checked when the result is formatted back
+		return v, true
+	}
+	twoDigitYear := func(v int) int {
+		if v > 68 {
+			return 1900 + v
+		}
+		return 2000 + v
+	}
+
+	var ok bool
+	switch token {
+	case "YYYY":
+		f.year, ok = num(4, 4)
+		f.hasYear = true
+	case "YY":
+		f.year, ok = num(2, 2)
+		f.year = twoDigitYear(f.year)
+		f.hasYear = true
+	case "Y":
+		f.year, ok = num(1, 6)
+		f.hasYear = true
+	case "gggg", "GGGG":
+		f.weekYear, ok = num(4, 4)
+		f.hasWeekYear = true
+	case "gg", "GG":
+		f.weekYear, ok = num(2, 2)
+		f.weekYear = twoDigitYear(f.weekYear)
+		f.hasWeekYear = true
+	case "Q":
+		f.quarter, ok = num(1, 1)
+		f.hasQuarter = true
+	case "Qo":
+		f.quarter, ok = ordinal()
+		f.hasQuarter = true
+	case "MMMM", "MMM":
+		var month int
+		if month, s, ok = matchMomentName(s, 12, len(token), func(i int) string {
+			return time.Month(i + 1).String()
+		}); ok {
+			f.month = month + 1
+		}
+		f.hasMonth = true
+	case "Mo":
+		f.month, ok = ordinal()
+		f.hasMonth = true
+	case "MM":
+		f.month, ok = num(2, 2)
+		f.hasMonth = true
+	case "M":
+		f.month, ok = num(1, 2)
+		f.hasMonth = true
+	case "DDDD":
+		f.yearDay, ok = num(3, 3)
+		f.hasYearDay = true
+	case "DDDo":
+		f.yearDay, ok = ordinal()
+		f.hasYearDay = true
+	case "DDD":
+		f.yearDay, ok = num(1, 3)
+		f.hasYearDay = true
+	case "Do":
+		f.day, ok = ordinal()
+		f.hasDay = true
+	case "DD":
+		f.day, ok = num(2, 2)
+		f.hasDay = true
+	case "D":
+		f.day, ok = num(1, 2)
+		f.hasDay = true
+	case "dddd", "ddd", "dd":
+		var wd int
+		if wd, s, ok = matchMomentName(s, 7, len(token), func(i int) string {
+			return time.Weekday(i).String()
+		}); ok {
+			f.weekday = time.Weekday(wd)
+		}
+		f.hasWeekday = true
+	case "do":
+		var wd int
+		wd, ok = ordinal()
+		f.weekday = time.Weekday(wd % 7)
+		f.hasWeekday = true
+	case "d", "e", "E":
+		var wd int
+		wd, ok = num(1, 1)
+		f.weekday = time.Weekday(wd % 7) // E counts Sunday as 7
+		f.hasWeekday = true
+	case "ww", "WW":
+		f.week, ok = num(2, 2)
+		f.hasWeek, f.isoWeek = true, token == "WW"
+	case "w", "W":
+		f.week, ok = num(1, 2)
+		f.hasWeek, f.isoWeek = true, token == "W"
+	case "wo", "Wo":
+		f.week, ok = ordinal()
+		f.hasWeek, f.isoWeek = true, token == "Wo"
+	case "HH", "hh", "kk":
+		f.hour, ok = num(2, 2)
+	case "H", "h", "k":
+		f.hour, ok = num(1, 2)
+	case "mm":
+		f.minute, ok = num(2, 2)
+	case "m":
+		f.minute, ok = num(1, 2)
+	case "ss":
+		f.second, ok = num(2, 2)
+	case "s":
+		f.second, ok = num(1, 2)
+	case "SSS", "SS", "S":
+		var frac int
+		if frac, ok = num(len(token), len(token)); ok {
+			f.nsec = frac * int(math.Pow10(9-len(token)))
+		}
+	case "A", "a":
+		if len(s) >= 2 && (strings.EqualFold(s[:2], "am") || strings.EqualFold(s[:2], "pm")) {
+			f.pm, s, ok = strings.ToLower(s[:2]), s[2:], true
+		}
+	case "X", "x":
+		neg := strings.HasPrefix(s, "-")
+		s = strings.TrimPrefix(s, "-")
+		var n int
+		if n, ok = num(1, 18); ok {
+			if neg {
+				n = -n
+			}
+			t := time.Unix(int64(n), 0)
+			if token == "x" {
+				t = time.UnixMilli(int64(n))
+			}
+			f.unix = &t
+		}
+	case "Z", "ZZ":
+		if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+			sign := 1
+			if s[0] == '-' {
+				sign = -1
+			}
+			s = s[1:]
+			var hh, mm int
+			if hh, ok = num(2, 2); ok {
+				if token == "Z" {
+					ok = strings.HasPrefix(s, ":")
+					s = strings.TrimPrefix(s, ":")
+				}
+				if ok {
+					mm, ok = num(2, 2)
+				}
+			}
+			offset := sign * (hh*3600 + mm*60)
+			f.offset = &offset
+		}
+	}
+	return s, ok
+}
+
+// matchMomentName matches the longest of n names (case-insensitively) at
+// the start of s and returns its index. Names are cut to width letters
+// unless width is 4 or more, which means the full name.
+func matchMomentName(s string, n, width int, name func(int) string) (int, string, bool) {
+	best, bestLen := -1, 0
+	for i := 0; i < n; i++ {
+		nm := name(i)
+		if width < 4 {
+			nm = nm[:width]
+		}
+		if len(nm) > bestLen && len(s) >= len(nm) && strings.EqualFold(s[:len(nm)], nm) {
+			best, bestLen = i, len(nm)
+		}
+	}
+	if best < 0 {
+		return 0, s, false
+	}
+	return best, s[bestLen:], true
+}
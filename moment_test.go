@@ -0,0 +1,168 @@
+package vlt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMomentToGoFormat(t *testing.T) {
+	tests := []struct {
+		moment string
+		want   string
+	}{
+		{"YYYY-MM-DD", "2006-01-02"},
+		{"YY-M-D", "06-1-2"},
+		{"YYYY/MM/DD", "2006/01/02"},
+		{"dddd, MMMM D, YYYY", "Monday, January 2, 2006"},
+		{"ddd MMM DD", "Mon Jan 02"},
+		{"YYYY-MM-DD HH:mm", "2006-01-02 15:04"},
+		{"YYYY-DDDD", "2006-002"},
+		{"[Week] ww", "Week ww"},
+		{"hh:mm:ss.SSS a ZZ", "03:04:05.000 pm -0700"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.moment, func(t *testing.T) {
+			got := MomentToGoFormat(tt.moment)
+			if got != tt.want {
+				t.Errorf("MomentToGoFormat(%q) = %q, want %q", tt.moment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatMoment(t *testing.T) {
+	date := time.Date(2024, 12, 30, 14, 5, 9, 0, time.UTC) // a Monday
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"YYYY-MM-DD", "2024-12-30"},
+		{"gggg-[W]ww", "2025-W01"},
+		{"GGGG-[W]WW", "2025-W01"},
+		{"YYYY-[Q]Q", "2024-Q4"},
+		{"[Week] w, YY", "Week 1, 24"},
+		{"dddd MMM D", "Monday Dec 30"},
+		{"H:m:s h A", "14:5:9 2 PM"},
+		{"YYYY-MM-[DD]", "2024-12-DD"},
+		{"MMMM Do, YYYY", "December 30th, 2024"},
+		{"DDDD DDD DDDo", "365 365 365th"},
+		{"dd d E e do", "Mo 1 1 1 1st"},
+		{"Qo [quarter], Mo [month]", "4th quarter, 12th month"},
+		{"wo WW Wo", "1st 01 1st"},
+		{"hh k kk a", "02 14 14 pm"},
+		{"ss.SSS Z ZZ", "09.000 +00:00 +0000"},
+		{"X x", "1735567509 1735567509000"},
+		{`\Y\Q YYYY \[[x]\]`, "YQ 2024 [x]"},
+		{"[Week] ww", "Week 01"},
+	}
+	for _, tt := range tests {
+		if got := FormatMoment(date, tt.format); got != tt.want {
+			t.Errorf("FormatMoment(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestMomentWeek_ISOMatchesGo(t *testing.T) {
+	for d := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() < 2030; d = d.AddDate(0, 0, 1) {
+		year, week := d.ISOWeek()
+		gotYear, gotWeek := momentWeek(d, 1, 4)
+		if gotYear != year || gotWeek != week {
+			t.Fatalf("%s: got %d-W%02d, want %d-W%02d", d.Format("2006-01-02"), gotYear, gotWeek, year, week)
+		}
+	}
+}
+
+func TestMomentWeek_Locale(t *testing.T) {
+	// Moment's default locale: weeks start on Sunday and week 1 contains Jan 1.
+	tests := []struct {
+		date       string
+		year, week int
+	}{
+		{"2024-12-28", 2024, 52},
+		{"2024-12-29", 2025, 1},
+		{"2025-01-04", 2025, 1},
+		{"2025-01-05", 2025, 2},
+		{"2022-01-01", 2022, 1},
+		{"2021-12-25", 2021, 52},
+	}
+	for _, tt := range tests {
+		d, _ := time.Parse("2006-01-02", tt.date)
+		year, week := momentWeek(d, 0, 6)
+		if year != tt.year || week != tt.week {
+			t.Errorf("%s: got %d-W%02d, want %d-W%02d", tt.date, year, week, tt.year, tt.week)
+		}
+	}
+}
+
+func TestMomentOrdinal(t *testing.T) {
+	want := map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 101: "101st", 111: "111th"}
+	for n, w := range want {
+		if got := momentOrdinal(n); got != w {
+			t.Errorf("momentOrdinal(%d) = %q, want %q", n, got, w)
+		}
+	}
+}
+
+func TestParseMoment(t *testing.T) {
+	tests := []struct {
+		value, format string
+		want          string // RFC 3339
+	}{
+		{"2025-01-15", "YYYY-MM-DD", "2025-01-15T00:00:00Z"},
+		{"25-1-5", "YY-M-D", "2025-01-05T00:00:00Z"},
+		{"Wednesday, January 15th 2025", "dddd, MMMM Do YYYY", "2025-01-15T00:00:00Z"},
+		{"wed jan 15 2025", "ddd MMM D YYYY", "2025-01-15T00:00:00Z"},
+		{"2025-015", "YYYY-DDDD", "2025-01-15T00:00:00Z"},
+		{"2025-W01", "gggg-[W]ww", "2024-12-29T00:00:00Z"},
+		{"2025-W01", "GGGG-[W]WW", "2024-12-30T00:00:00Z"},
+		{"2025-W03-5", "GGGG-[W]WW-E", "2025-01-17T00:00:00Z"},
+		{"2025-Q3", "YYYY-[Q]Q", "2025-07-01T00:00:00Z"},
+		{"2025-03", "YYYY-MM", "2025-03-01T00:00:00Z"},
+		{"2025/03/2025-03-01", "YYYY/MM/YYYY-MM-DD", "2025-03-01T00:00:00Z"},
+		{"2025-01-15 2:05 PM", "YYYY-MM-DD h:mm A", "2025-01-15T14:05:00Z"},
+		{"2025-01-15 12:00 am", "YYYY-MM-DD hh:mm a", "2025-01-15T00:00:00Z"},
+		{"2025-01-15T14:05:09.250+02:00", "YYYY-MM-DD[T]HH:mm:ss.SSSZ", "2025-01-15T14:05:09.25+02:00"},
+		{"1735567509", "X", "2024-12-30T14:05:09Z"},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoment(tt.value, tt.format, time.UTC)
+		if err != nil {
+			t.Errorf("ParseMoment(%q, %q): %v", tt.value, tt.format, err)
+			continue
+		}
+		if got.Format(time.RFC3339Nano) != tt.want {
+			t.Errorf("ParseMoment(%q, %q) = %s, want %s", tt.value, tt.format, got.Format(time.RFC3339Nano), tt.want)
+		}
+	}
+
+	bad := []struct{ value, format string }{
+		{"2025-02-30", "YYYY-MM-DD"},               // no such day
+		{"2025-1-15", "YYYY-MM-DD"},                // padding required
+		{"2025-01-15 extra", "YYYY-MM-DD"},         // trailing text
+		{"Thursday 2025-01-15", "dddd YYYY-MM-DD"}, // wrong weekday
+		{"January 15nd 2025", "MMMM Do YYYY"},      // wrong ordinal suffix
+		{"2025-W54", "GGGG-[W]WW"},                 // no such week
+	}
+	for _, tt := range bad {
+		if _, err := ParseMoment(tt.value, tt.format, time.UTC); err == nil {
+			t.Errorf("ParseMoment(%q, %q): expected error", tt.value, tt.format)
+		}
+	}
+}
+
+func TestParseMoment_RoundTrip(t *testing.T) {
+	formats := []string{"YYYY-MM-DD", "gggg-[W]ww", "GGGG-[W]WW", "YYYY-[Q]Q", "YYYY-MM", "YYYY", "dddd, MMMM Do YYYY", "YYYY-DDDD", "GGGG-[W]WW-E"}
+	for d := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC); d.Year() < 2027; d = d.AddDate(0, 0, 3) {
+		for _, format := range formats {
+			name := FormatMoment(d, format)
+			got, err := ParseMoment(name, format, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseMoment(%q, %q): %v", name, format, err)
+			}
+			if back := FormatMoment(got, format); back != name {
+				t.Fatalf("%s: %q parsed to %s, formats back as %q", format, name, got.Format("2006-01-02"), back)
+			}
+		}
+	}
+}
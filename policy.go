@@ -0,0 +1,195 @@
+package vlt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// IntegrityPolicy decides what happens when a file no longer matches its
+// registered hash.
+type IntegrityPolicy string
+
+const (
+	// PolicyWarn reports mismatches (stderr, JSON status) but reads and
+	// writes go ahead. This is the default.
+	PolicyWarn IntegrityPolicy = "warn"
+	// PolicyBlock makes reads of mismatched files fail and writes refuse to
+	// touch them until they are acknowledged.
+	PolicyBlock IntegrityPolicy = "block"
+	// PolicyIgnore suppresses mismatch warnings.
+	PolicyIgnore IntegrityPolicy = "ignore"
+)
+
+// ErrIntegrityMismatch is wrapped by every *IntegrityError, so callers can
+// test with errors.Is.
+var ErrIntegrityMismatch = fmt.Errorf("integrity mismatch")
+
+// IntegrityError is returned under PolicyBlock when a read or write meets a
+// file whose content differs from its registered hash, or any file while the
+// registry is corrupted and nothing can be verified.
+type IntegrityError struct {
+	Path       string // vault-relative path
+	Op         string // "read" or "write"
+	Unverified bool   // the registry could not be loaded
+}
+
+func (e *IntegrityError) Error() string {
+	if e.Unverified {
+		return fmt.Sprintf("%s: the integrity registry is corrupted, so %s cannot be verified; refusing to %s it under the block policy (rebuild the registry with integrity:baseline)",
+			ErrIntegrityMismatch, e.Path, e.Op)
+	}
+	return fmt.Sprintf("%s: %s was modified outside vlt; refusing to %s it under the block policy (review with integrity:diff, accept with integrity:acknowledge)",
+		ErrIntegrityMismatch, e.Path, e.Op)
+}
+
+func (e *IntegrityError) Unwrap() error {
+	return ErrIntegrityMismatch
+}
+
+// ParseIntegrityPolicy validates a policy name.
+func ParseIntegrityPolicy(s string) (IntegrityPolicy, error) {
+	switch p := IntegrityPolicy(s); p {
+	case PolicyWarn, PolicyBlock, PolicyIgnore:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid integrity policy %q (use warn, block or ignore)", s)
+}
+
+// registryConfig holds per-vault integrity settings, stored as config.json
+// next to the registry.
+type registryConfig struct {
+	Policy IntegrityPolicy `json:"policy,omitempty"`
+}
+
+// loadRegistryConfig reads config.json from the registry directory. A
+// missing file yields an empty config.
+func loadRegistryConfig(dir string) (registryConfig, error) {
+	var cfg registryConfig
+	path := filepath.Join(dir, "config.json")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// resolvePolicy returns the effective policy: VLT_INTEGRITY, else the
+// vault's configured policy, else warn.
+func resolvePolicy(dir string) (IntegrityPolicy, error) {
+	if env := os.Getenv("VLT_INTEGRITY"); env != "" {
+		p, err := ParseIntegrityPolicy(env)
+		if err != nil {
+			return PolicyWarn, fmt.Errorf("VLT_INTEGRITY: %v", err)
+		}
+		return p, nil
+	}
+	cfg, err := loadRegistryConfig(dir)
+	if err != nil {
+		return PolicyWarn, err
+	}
+	if cfg.Policy == "" {
+		return PolicyWarn, nil
+	}
+	return ParseIntegrityPolicy(string(cfg.Policy))
+}
+
+// IntegrityPolicy returns the policy in effect for this vault. An error
+// means VLT_INTEGRITY or the vault's setting is invalid; warn is used then.
+func (v *Vault) IntegrityPolicy() (IntegrityPolicy, error) {
+	return resolvePolicy(v.registry.dir)
+}
+
+// SetIntegrityPolicy stores the vault's default policy (VLT_INTEGRITY
+// still overrides it) and applies it to this Vault.
+func (v *Vault) SetIntegrityPolicy(p IntegrityPolicy) error {
+	if _, err := ParseIntegrityPolicy(string(p)); err != nil {
+		return err
+	}
+	r := v.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, _ := loadRegistryConfig(r.dir)
+	cfg.Policy = p
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(r.dir, 0700); err != nil {
+		return err
+	}
+	tmpPath := filepath.Join(r.dir, "config.json.tmp")
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filepath.Join(r.dir, "config.json")); err != nil {
+		return err
+	}
+	r.policy, _ = resolvePolicy(r.dir)
+	return nil
+}
+
+// blocked returns an *IntegrityError when the block policy is in effect
+// and status is a mismatch. A corrupted registry blocks every file: with
+// nothing to verify against, the policy fails closed.
+func (r *Registry) blocked(vaultDir, absPath string, status IntegrityStatus, op string) error {
+	if r == nil || r.policy != PolicyBlock {
+		return nil
+	}
+	r.mu.Lock()
+	unverified := r.loadErr != nil
+	r.mu.Unlock()
+	if status != IntegrityMismatch && !unverified {
+		return nil
+	}
+	rel, err := filepath.Rel(vaultDir, absPath)
+	if err != nil {
+		rel = absPath
+	}
+	return &IntegrityError{Path: filepath.ToSlash(rel), Op: op, Unverified: unverified}
+}
+
+// guard is called before a write creates, replaces, moves or deletes
+// absPath. Under the block policy it refuses files whose current content
+// does not match their registered hash. Untracked and missing files are not
+// blocked unless the registry is corrupted.
+func (r *Registry) guard(vaultDir, absPath string) error {
+	if r == nil || r.policy != PolicyBlock {
+		return nil
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return r.blocked(vaultDir, absPath, IntegrityUntracked, "write")
+	}
+	return r.blocked(vaultDir, absPath, r.verify(vaultDir, absPath, data), "write")
+}
+
+// guardEdits runs the edit plans of a multi-file operation and guards every
+// file they would touch, so that under the block policy the operation fails
+// before its first change instead of halfway through. Plans are not run
+// under other policies.
+func (r *Registry) guardEdits(vaultDir string, plans ...func() ([]fileEdit, error)) error {
+	if r == nil || r.policy != PolicyBlock {
+		return nil
+	}
+	for _, plan := range plans {
+		edits, err := plan()
+		if err != nil {
+			return err
+		}
+		for _, e := range edits {
+			if err := r.guard(vaultDir, e.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package vlt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseIntegrityPolicy(t *testing.T) {
+	for _, s := range []string{"warn", "block", "ignore"} {
+		if p, err := ParseIntegrityPolicy(s); err != nil || string(p) != s {
+			t.Errorf("ParseIntegrityPolicy(%q) = %q, %v", s, p, err)
+		}
+	}
+	if _, err := ParseIntegrityPolicy("strict"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestIntegrityPolicy_Resolution(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	t.Setenv("VLT_INTEGRITY", "")
+
+	if p, err := v.IntegrityPolicy(); err != nil || p != PolicyWarn {
+		t.Errorf("default policy = %q, %v; want warn", p, err)
+	}
+	if err := v.SetIntegrityPolicy(PolicyBlock); err != nil {
+		t.Fatalf("SetIntegrityPolicy: %v", err)
+	}
+	if p := openRegistry(vaultDir).policy; p != PolicyBlock {
+		t.Errorf("reopened registry policy = %q, want block", p)
+	}
+
+	t.Setenv("VLT_INTEGRITY", "ignore")
+	if p, err := v.IntegrityPolicy(); err != nil || p != PolicyIgnore {
+		t.Errorf("env override = %q, %v; want ignore", p, err)
+	}
+	t.Setenv("VLT_INTEGRITY", "loud")
+	if _, err := v.IntegrityPolicy(); err == nil || !strings.Contains(err.Error(), "VLT_INTEGRITY") {
+		t.Errorf("invalid env: got %v", err)
+	}
+}
+
+func TestIntegrityPolicy_Block(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	v.registry.policy = PolicyBlock
+
+	tampered := filepath.Join(vaultDir, "Tampered.md")
+	clean := filepath.Join(vaultDir, "Clean.md")
+	os.WriteFile(tampered, []byte("# Tampered\n#old\n"), 0644)
+	os.WriteFile(clean, []byte("# Clean\n[[Tampered]]\n#old\n"), 0644)
+	v.registry.register(vaultDir, tampered, []byte("# Tampered\n#old\n"))
+	v.registry.register(vaultDir, clean, []byte("# Clean\n[[Tampered]]\n#old\n"))
+	os.WriteFile(tampered, []byte("# Tampered\n#old\nInjected.\n"), 0644)
+
+	_, err := v.Read("Tampered", "")
+	var ie *IntegrityError
+	if !errors.As(err, &ie) || ie.Op != "read" || ie.Path != "Tampered.md" || !errors.Is(err, ErrIntegrityMismatch) {
+		t.Fatalf("Read error = %v, want *IntegrityError for read", err)
+	}
+
+	// Linked notes are returned with their status but without content.
+	result, linked, err := v.ReadFollow("Clean", "")
+	if err != nil || result.Integrity != IntegrityOK {
+		t.Fatalf("ReadFollow = %v, %v", result.Integrity, err)
+	}
+	if len(linked) != 1 || linked[0].Integrity != IntegrityMismatch || linked[0].Content != "" {
+		t.Errorf("linked = %+v, want withheld mismatched note", linked)
+	}
+
+	writes := map[string]func() error{
+		"append": func() error { return v.Append("Tampered", "x\n", false) },
+		"write":  func() error { return v.Write("Tampered", "x\n", false) },
+		"move": func() error {
+			_, err := v.Move("Tampered.md", "Moved.md")
+			return err
+		},
+		"delete": func() error {
+			_, err := v.Delete("Tampered", "", DeleteOptions{Links: "leave"})
+			return err
+		},
+		"tag:rename": func() error {
+			_, err := v.TagRename("old", "new")
+			return err
+		},
+	}
+	for name, write := range writes {
+		if err := write(); !errors.As(err, &ie) || ie.Op != "write" {
+			t.Errorf("%s: error = %v, want *IntegrityError for write", name, err)
+		}
+	}
+	if data, _ := os.ReadFile(tampered); string(data) != "# Tampered\n#old\nInjected.\n" {
+		t.Errorf("blocked writes modified the file: %q", data)
+	}
+
+	// Acknowledging accepts the content and lifts the block.
+	if err := v.IntegrityAcknowledge("Tampered"); err != nil {
+		t.Fatalf("IntegrityAcknowledge: %v", err)
+	}
+	if _, err := v.Read("Tampered", ""); err != nil {
+		t.Errorf("Read after acknowledge: %v", err)
+	}
+	if err := v.Append("Tampered", "ok\n", false); err != nil {
+		t.Errorf("Append after acknowledge: %v", err)
+	}
+}
+
+func TestIntegrityPolicy_BlockContentReads(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	v.registry.policy = PolicyBlock
+
+	note := filepath.Join(vaultDir, "Plan.md")
+	board := filepath.Join(vaultDir, "Board.canvas")
+	original := []byte("---\nstatus: draft\n---\n- [ ] ship\n")
+	os.WriteFile(note, original, 0644)
+	os.WriteFile(board, []byte(`{"nodes":[],"edges":[]}`), 0644)
+	v.registry.register(vaultDir, note, original)
+	v.registry.register(vaultDir, board, []byte(`{"nodes":[],"edges":[]}`))
+
+	if result, err := v.Properties("Plan"); err != nil || result.Integrity != IntegrityOK {
+		t.Fatalf("Properties before tampering = %+v, %v", result, err)
+	}
+
+	os.WriteFile(note, []byte("---\nstatus: approved\n---\n- [ ] ship to prod\n"), 0644)
+	os.WriteFile(board, []byte(`{"nodes":[{"id":"x","type":"text","text":"Injected","x":0,"y":0,"width":1,"height":1}],"edges":[]}`), 0644)
+
+	reads := map[string]func() error{
+		"properties": func() error {
+			_, err := v.Properties("Plan")
+			return err
+		},
+		"search context": func() error {
+			_, err := v.SearchWithContext(SearchOptions{Query: "ship", ContextN: 1})
+			return err
+		},
+		"tasks": func() error {
+			_, err := v.Tasks(TaskOptions{})
+			return err
+		},
+		"tasks file": func() error {
+			_, err := v.Tasks(TaskOptions{File: "Plan"})
+			return err
+		},
+		"canvas:read": func() error {
+			_, _, err := v.CanvasRead("Board")
+			return err
+		},
+	}
+	for name, read := range reads {
+		var ie *IntegrityError
+		if err := read(); !errors.As(err, &ie) || ie.Op != "read" {
+			t.Errorf("%s: error = %v, want *IntegrityError for read", name, err)
+		}
+	}
+
+	// Searches that do not match the note are unaffected.
+	if _, err := v.SearchWithContext(SearchOptions{Query: "nothing here"}); err != nil {
+		t.Errorf("unrelated search: %v", err)
+	}
+}
+
+func TestIntegrityPolicy_BlockChecksAllFilesFirst(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	v.registry.policy = PolicyBlock
+
+	// A.md is walked before the tampered M.md, so a per-file check would
+	// rewrite it before failing.
+	files := map[string]string{
+		"A.md":         "[[Target]] #old\n",
+		"M.md":         "[[Target]] #old\n",
+		"Target.md":    "# Target\n",
+		"Other.md":     "# Other\n",
+		"Board.canvas": `{"nodes":[{"id":"1","type":"file","file":"Target.md","x":0,"y":0,"width":1,"height":1}],"edges":[]}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(vaultDir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		v.registry.register(vaultDir, path, []byte(content))
+	}
+	files["M.md"] = "[[Target]] #old\nInjected.\n"
+	os.WriteFile(filepath.Join(vaultDir, "M.md"), []byte(files["M.md"]), 0644)
+
+	ops := map[string]func() error{
+		"move": func() error {
+			_, err := v.Move("Target.md", "Renamed.md")
+			return err
+		},
+		"delete unlink": func() error {
+			_, err := v.Delete("Target", "", DeleteOptions{Links: "unlink"})
+			return err
+		},
+		"delete redirect": func() error {
+			_, err := v.Delete("Target", "", DeleteOptions{Links: "redirect", RedirectTo: "Other"})
+			return err
+		},
+		"tag:rename": func() error {
+			_, err := v.TagRename("old", "new")
+			return err
+		},
+	}
+	for name, op := range ops {
+		var ie *IntegrityError
+		if err := op(); !errors.As(err, &ie) || ie.Path != "M.md" {
+			t.Errorf("%s: error = %v, want *IntegrityError for M.md", name, err)
+		}
+		for file, content := range files {
+			if data, _ := os.ReadFile(filepath.Join(vaultDir, file)); string(data) != content {
+				t.Errorf("%s: %s changed to %q", name, file, data)
+			}
+		}
+	}
+}
+
+func TestIntegrityPolicy_BlockCorruptedRegistry(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	path := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(path, []byte("# Note\n"), 0644)
+	v.registry.register(vaultDir, path, []byte("# Note\n"))
+
+	os.WriteFile(filepath.Join(v.registry.dir, "registry.json"), []byte("{not json"), 0600)
+	v.registry = openRegistry(vaultDir)
+	v.registry.policy = PolicyBlock
+
+	ops := map[string]func() error{
+		"read": func() error {
+			_, err := v.Read("Note", "")
+			return err
+		},
+		"append": func() error { return v.Append("Note", "x\n", false) },
+		"create": func() error { return v.Create("New", "New.md", "# New\n", false, false) },
+	}
+	for name, op := range ops {
+		var ie *IntegrityError
+		if err := op(); !errors.As(err, &ie) || !ie.Unverified {
+			t.Errorf("%s: error = %v, want unverified *IntegrityError", name, err)
+		}
+	}
+
+	if err := v.IntegrityBaseline(false); err != nil {
+		t.Fatalf("IntegrityBaseline: %v", err)
+	}
+	if _, err := v.Read("Note", ""); err != nil {
+		t.Errorf("Read after baseline: %v", err)
+	}
+}
+
+func TestIntegrityPolicy_WarnAllows(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	v.registry.policy = PolicyWarn
+
+	path := filepath.Join(vaultDir, "Note.md")
+	v.registry.register(vaultDir, path, []byte("# Note\n"))
+	os.WriteFile(path, []byte("# Changed\n"), 0644)
+
+	result, err := v.Read("Note", "")
+	if err != nil || result.Integrity != IntegrityMismatch {
+		t.Errorf("Read = %v, %v; want mismatch without error", result.Integrity, err)
+	}
+	if err := v.Append("Note", "more\n", false); err != nil {
+		t.Errorf("Append under warn: %v", err)
+	}
+}
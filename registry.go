@@ -0,0 +1,221 @@
+package vlt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// inVaultRegistryDir is where an in-vault registry lives, relative to the
+// vault root. Its registry.json marks the vault as using it.
+const inVaultRegistryDir = ".vlt"
+
+// registryFiles are the files and directories that make up a registry;
+// migrations move exactly these (an in-vault .vlt/ also holds lint.yaml).
+var registryFiles = []string{"registry.json", "registry.json.corrupt", "audit.log", "config.json", "objects"}
+
+// vaultIDPattern matches a vault ID as produced by vaultID.
+var vaultIDPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+// registryBase returns the directory holding out-of-vault registries:
+// VLT_REGISTRY_DIR, else ~/.vlt/registries.
+func registryBase() string {
+	if dir := os.Getenv("VLT_REGISTRY_DIR"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	return filepath.Join(home, ".vlt", "registries")
+}
+
+// externalRegistryDir returns the out-of-vault registry directory for a
+// vault: <registryBase>/<vault-id>/.
+func externalRegistryDir(vaultDir string) string {
+	return filepath.Join(registryBase(), vaultID(vaultDir))
+}
+
+// registryDir returns the registry directory for a vault: <vault>/.vlt/
+// when .vlt/registry.json exists (the registry travels with the vault),
+// else <VLT_REGISTRY_DIR or ~/.vlt/registries>/<vault-id>/.
+func registryDir(vaultDir string) string {
+	inVault := filepath.Join(vaultDir, inVaultRegistryDir)
+	if _, err := os.Stat(filepath.Join(inVault, "registry.json")); err == nil {
+		return inVault
+	}
+	return externalRegistryDir(vaultDir)
+}
+
+// RegistryInVault reports whether the vault's integrity registry is stored
+// inside the vault (.vlt/registry.json).
+func (v *Vault) RegistryInVault() bool {
+	return v.registry.dir == filepath.Join(v.dir, inVaultRegistryDir)
+}
+
+// IntegrityMigrate re-keys the registry of a vault that used to live at
+// oldVaultDir (or whose vault ID is oldVaultDir) to this vault, moving it
+// from <registryBase>/<old-id>/ to this vault's registry directory. An
+// existing registry at the destination is only replaced with force.
+// Returns the new registry directory.
+func (v *Vault) IntegrityMigrate(oldVaultDir string, force bool) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	id := oldVaultDir
+	if !vaultIDPattern.MatchString(id) {
+		id = vaultID(oldVaultDir)
+	}
+	src := filepath.Join(registryBase(), id)
+	if _, err := os.Stat(filepath.Join(src, "registry.json")); err != nil {
+		return "", fmt.Errorf("no registry found for %s (looked in %s)", oldVaultDir, src)
+	}
+	if src == v.registry.dir {
+		return "", fmt.Errorf("registry is already keyed to this vault (%s)", src)
+	}
+	return v.relocateRegistry(src, v.registry.dir, force)
+}
+
+// IntegrityMoveRegistry moves this vault's registry into the vault
+// (.vlt/, so it follows the vault when synced or moved) or, with inVault
+// false, back out to <registryBase>/<vault-id>/. With no registry yet, an
+// empty one is created at the destination. Returns the new directory.
+func (v *Vault) IntegrityMoveRegistry(inVault, force bool) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	dst := externalRegistryDir(v.dir)
+	if inVault {
+		dst = filepath.Join(v.dir, inVaultRegistryDir)
+	}
+	if dst == v.registry.dir {
+		return "", fmt.Errorf("registry is already at %s", dst)
+	}
+	return v.relocateRegistry(v.registry.dir, dst, force)
+}
+
+// relocateRegistry moves the registry files from src to dst and reopens
+// the registry there. The files are first copied into a staging directory
+// under dst and then renamed into place, putting back whatever they
+// replaced if a rename fails, so a failure leaves the registry whole at
+// src. Caller must hold v.mu.
+func (v *Vault) relocateRegistry(src, dst string, force bool) (string, error) {
+	if _, err := os.Stat(filepath.Join(dst, "registry.json")); err == nil && !force {
+		return "", fmt.Errorf("a registry already exists at %s (use force to replace it)", dst)
+	}
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return "", err
+	}
+
+	v.registry.mu.Lock()
+	defer v.registry.mu.Unlock()
+
+	staging, err := os.MkdirTemp(dst, ".staging-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(staging)
+	for _, name := range registryFiles {
+		from := filepath.Join(src, name)
+		if _, err := os.Stat(from); os.IsNotExist(err) {
+			continue
+		}
+		if err := copyPath(from, filepath.Join(staging, name)); err != nil {
+			return "", fmt.Errorf("copying %s: %w", name, err)
+		}
+	}
+	// A registry that was never written still needs registry.json at the
+	// destination, or an in-vault registry would not be picked up.
+	stagedReg := filepath.Join(staging, "registry.json")
+	if _, err := os.Stat(stagedReg); os.IsNotExist(err) {
+		if err := os.WriteFile(stagedReg, []byte("{}"), 0600); err != nil {
+			return "", err
+		}
+	}
+
+	// Swap the staged files in; files they replace go to backup until done.
+	backup, err := os.MkdirTemp(dst, ".replaced-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(backup)
+	var swapped []string
+	rollback := func() {
+		for _, name := range swapped {
+			os.RemoveAll(filepath.Join(dst, name))
+			os.Rename(filepath.Join(backup, name), filepath.Join(dst, name))
+		}
+	}
+	for _, name := range registryFiles {
+		to := filepath.Join(dst, name)
+		if _, err := os.Stat(to); err == nil {
+			if err := os.Rename(to, filepath.Join(backup, name)); err != nil {
+				rollback()
+				return "", fmt.Errorf("replacing %s: %w", name, err)
+			}
+		}
+		swapped = append(swapped, name)
+		staged := filepath.Join(staging, name)
+		if _, err := os.Stat(staged); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.Rename(staged, to); err != nil {
+			rollback()
+			return "", fmt.Errorf("moving %s: %w", name, err)
+		}
+	}
+
+	// registry.json goes first: while it remains, an in-vault source would
+	// still take precedence over dst.
+	for _, name := range registryFiles {
+		if err := os.RemoveAll(filepath.Join(src, name)); err != nil {
+			return "", fmt.Errorf("registry moved to %s, but removing %s from %s failed: %w", dst, name, src, err)
+		}
+	}
+	os.Remove(src) // only succeeds when nothing else is left
+	command, actor := v.registry.command, v.registry.actor
+
+	v.registry = openRegistry(v.dir)
+	v.registry.command, v.registry.actor = command, actor
+	return v.registry.dir, nil
+}
+
+// copyPath copies a file or directory tree.
+func copyPath(from, to string) error {
+	info, err := os.Stat(from)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		if err := os.MkdirAll(to, 0700); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(from)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := copyPath(filepath.Join(from, e.Name()), filepath.Join(to, e.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,145 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistryDir_Location(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("VLT_REGISTRY_DIR", base)
+	vaultDir := t.TempDir()
+
+	if got, want := registryDir(vaultDir), filepath.Join(base, vaultID(vaultDir)); got != want {
+		t.Errorf("registryDir = %q, want %q", got, want)
+	}
+
+	// .vlt/ alone (e.g. for lint.yaml) does not move the registry.
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	if got := registryDir(vaultDir); got != filepath.Join(base, vaultID(vaultDir)) {
+		t.Errorf("registryDir with bare .vlt/ = %q", got)
+	}
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "registry.json"), []byte("{}"), 0600)
+	if got, want := registryDir(vaultDir), filepath.Join(vaultDir, ".vlt"); got != want {
+		t.Errorf("registryDir in vault = %q, want %q", got, want)
+	}
+}
+
+func TestIntegrityMoveRegistry(t *testing.T) {
+	t.Setenv("VLT_REGISTRY_DIR", t.TempDir())
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "lint.yaml"), []byte("tags: {}\n"), 0644)
+	path := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(path, []byte("# Note\n"), 0644)
+
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	v.registry.register(vaultDir, path, []byte("# Note\n"))
+	external := v.registry.dir
+
+	dir, err := v.IntegrityMoveRegistry(true, false)
+	if err != nil {
+		t.Fatalf("IntegrityMoveRegistry(in vault): %v", err)
+	}
+	if dir != filepath.Join(vaultDir, ".vlt") || !v.RegistryInVault() {
+		t.Errorf("registry dir = %q, want in-vault", dir)
+	}
+	if _, err := os.Stat(external); !os.IsNotExist(err) {
+		t.Errorf("old registry dir should be gone: %v", err)
+	}
+	if s := v.VerifyIntegrity("Note")["Note"]; s != IntegrityOK {
+		t.Errorf("status after move = %s, want ok", s)
+	}
+	if report, _ := v.IntegrityAudit(); len(report.Problems) != 0 || report.Records != 1 {
+		t.Errorf("audit after move = %+v", report)
+	}
+
+	// Moving back out leaves lint.yaml in place.
+	if _, err := v.IntegrityMoveRegistry(false, false); err != nil {
+		t.Fatalf("IntegrityMoveRegistry(external): %v", err)
+	}
+	if v.RegistryInVault() || v.registry.dir != external {
+		t.Errorf("registry dir = %q, want %q", v.registry.dir, external)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, ".vlt", "lint.yaml")); err != nil {
+		t.Errorf("lint.yaml was moved: %v", err)
+	}
+	if _, err := v.IntegrityMoveRegistry(false, false); err == nil {
+		t.Error("expected error moving to the current location")
+	}
+}
+
+func TestIntegrityMoveRegistry_FailureLeavesSource(t *testing.T) {
+	t.Setenv("VLT_REGISTRY_DIR", t.TempDir())
+	vaultDir := t.TempDir()
+	path := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(path, []byte("# Note\n"), 0644)
+
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	v.registry.register(vaultDir, path, []byte("# Note\n"))
+	external := v.registry.dir
+
+	// A dangling symlink under objects/ makes copying fail after
+	// registry.json and audit.log were already copied.
+	os.MkdirAll(filepath.Join(external, "objects"), 0700)
+	os.Symlink("missing", filepath.Join(external, "objects", "dangling"))
+
+	if _, err := v.IntegrityMoveRegistry(true, false); err == nil {
+		t.Fatal("expected the move to fail")
+	}
+	if v.registry.dir != external {
+		t.Errorf("registry dir = %q, want %q", v.registry.dir, external)
+	}
+	for _, name := range []string{"registry.json", "audit.log"} {
+		if _, err := os.Stat(filepath.Join(external, name)); err != nil {
+			t.Errorf("%s missing from the source: %v", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Join(vaultDir, ".vlt")); len(entries) != 0 {
+		t.Errorf("destination not left empty: %v", entries)
+	}
+	if s := openRegistry(vaultDir).dir; s != external {
+		t.Errorf("registry reopened at %q, want %q", s, external)
+	}
+}
+
+func TestIntegrityMigrate_Rekey(t *testing.T) {
+	t.Setenv("VLT_REGISTRY_DIR", t.TempDir())
+	oldDir := filepath.Join(t.TempDir(), "old-vault")
+	newDir := t.TempDir()
+	os.MkdirAll(oldDir, 0755)
+
+	old := &Vault{dir: oldDir, registry: openRegistry(oldDir)}
+	content := []byte("# Note\n")
+	old.registry.register(oldDir, filepath.Join(oldDir, "Note.md"), content)
+
+	// The vault moved: same files, new path, new vault ID.
+	os.WriteFile(filepath.Join(newDir, "Note.md"), content, 0644)
+	v := &Vault{dir: newDir, registry: openRegistry(newDir)}
+	if s := v.VerifyIntegrity("Note")["Note"]; s != IntegrityNoRegistry {
+		t.Fatalf("status before migrate = %s, want no-registry", s)
+	}
+
+	if _, err := v.IntegrityMigrate(oldDir, false); err != nil {
+		t.Fatalf("IntegrityMigrate: %v", err)
+	}
+	if s := v.VerifyIntegrity("Note")["Note"]; s != IntegrityOK {
+		t.Errorf("status after migrate = %s, want ok", s)
+	}
+	if _, err := v.IntegrityMigrate(oldDir, false); err == nil {
+		t.Error("expected error migrating a registry that no longer exists")
+	}
+
+	// Migrating by vault ID onto an existing registry needs force.
+	other := filepath.Join(t.TempDir(), "other")
+	os.MkdirAll(other, 0755)
+	o := &Vault{dir: other, registry: openRegistry(other)}
+	o.registry.register(other, filepath.Join(other, "X.md"), []byte("x"))
+	if _, err := v.IntegrityMigrate(vaultID(other), false); err == nil {
+		t.Error("expected error replacing an existing registry without force")
+	}
+	if _, err := v.IntegrityMigrate(vaultID(other), true); err != nil {
+		t.Errorf("IntegrityMigrate force: %v", err)
+	}
+}
@@ -0,0 +1,356 @@
+package vlt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a frontmatter schema from the "schemas" section of
+// .vlt/lint.yaml. A note is checked against every schema it matches:
+//
+//	schemas:
+//	  meeting:
+//	    match:                 # any selector matching is enough
+//	      type: meeting        # frontmatter "type" value
+//	      folder: meetings     # notes below this folder
+//	      tag: meeting         # notes carrying this tag or a subtag
+//	    required: [date, attendees]
+//	    properties:
+//	      date:
+//	        type: date         # string, number, boolean, list or date
+//	        format: YYYY-MM-DD # Moment.js format for dates (default YYYY-MM-DD)
+//	      status:
+//	        enum: [scheduled, done]
+type Schema struct {
+	Name       string
+	Types      []string
+	Folders    []string
+	Tags       []string
+	Required   []string
+	Properties map[string]SchemaProperty
+}
+
+// SchemaProperty constrains a single frontmatter key.
+type SchemaProperty struct {
+	Type   string
+	Format string
+	Enum   []string
+}
+
+// SchemaViolation is a frontmatter value that does not satisfy a schema.
+type SchemaViolation struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Schema  string `json:"schema"`
+	Key     string `json:"key"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// schemaValueTypes lists the supported property types.
+var schemaValueTypes = map[string]bool{
+	"string": true, "number": true, "boolean": true, "list": true, "date": true,
+}
+
+// parseSchemas extracts the schemas section from a parsed lint config,
+// sorted by name.
+func parseSchemas(cfg map[string]any) ([]Schema, error) {
+	section, ok := cfg["schemas"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s has no schemas section", lintConfigPath)
+	}
+
+	var schemas []Schema
+	for name, raw := range section {
+		def, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: schema %q must be a map", lintConfigPath, name)
+		}
+		s := Schema{Name: name, Properties: make(map[string]SchemaProperty)}
+
+		match, _ := def["match"].(map[string]any)
+		if match == nil {
+			return nil, fmt.Errorf("%s: schema %q needs a match (type, folder or tag)", lintConfigPath, name)
+		}
+		s.Types = yamlStringList(match["type"])
+		s.Folders = yamlStringList(match["folder"])
+		for _, t := range yamlStringList(match["tag"]) {
+			s.Tags = append(s.Tags, strings.TrimPrefix(t, "#"))
+		}
+		if len(s.Types)+len(s.Folders)+len(s.Tags) == 0 {
+			return nil, fmt.Errorf("%s: schema %q needs a match (type, folder or tag)", lintConfigPath, name)
+		}
+
+		s.Required = yamlStringList(def["required"])
+
+		if raw, ok := def["properties"]; ok && raw != nil {
+			props, ok := raw.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: schema %q: properties must map keys to rules", lintConfigPath, name)
+			}
+			for key, rawProp := range props {
+				var p SchemaProperty
+				if rules, ok := rawProp.(map[string]any); ok {
+					p.Type, _ = rules["type"].(string)
+					p.Format, _ = rules["format"].(string)
+					p.Enum = yamlStringList(rules["enum"])
+				}
+				if p.Type != "" && !schemaValueTypes[p.Type] {
+					return nil, fmt.Errorf("%s: schema %q: unknown type %q for %s (use string, number, boolean, list or date)",
+						lintConfigPath, name, p.Type, key)
+				}
+				if p.Type == "date" && p.Format == "" {
+					p.Format = "YYYY-MM-DD"
+				}
+				s.Properties[key] = p
+			}
+		}
+		schemas = append(schemas, s)
+	}
+
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].Name < schemas[j].Name })
+	return schemas, nil
+}
+
+// matches reports whether a note falls under the schema.
+func (s Schema) matches(relPath, yaml string, tags []string) bool {
+	if noteType, ok := FrontmatterGetValue(yaml, "type"); ok && noteType != "" {
+		for _, t := range s.Types {
+			if strings.EqualFold(t, noteType) {
+				return true
+			}
+		}
+	}
+	slashPath := filepath.ToSlash(relPath)
+	for _, folder := range s.Folders {
+		f := strings.Trim(filepath.ToSlash(folder), "/")
+		if f == "" || f == "." || strings.HasPrefix(slashPath, f+"/") {
+			return true
+		}
+	}
+	for _, tag := range s.Tags {
+		if hasTagOrSubtag(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// frontmatterField is a top-level frontmatter key with its raw scalar value
+// or list items and its 1-based line in the note. IsMap marks a key holding
+// an indented block of nested keys.
+type frontmatterField struct {
+	Value  string
+	Items  []string
+	IsList bool
+	IsMap  bool
+	Line   int
+}
+
+// frontmatterFields returns the top-level keys of frontmatter YAML (as
+// returned by ExtractFrontmatter). Inline [a, b] and block lists are split
+// into Items; scalars are unquoted. Nested maps are marked but not parsed.
+func frontmatterFields(yaml string) map[string]frontmatterField {
+	fields := make(map[string]frontmatterField)
+	lines := strings.Split(yaml, "\n")
+	for i, line := range lines {
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' || strings.HasPrefix(line, "- ") {
+			continue
+		}
+		key, value, ok := splitYAMLKey(strings.TrimRight(line, " \r"))
+		if !ok {
+			continue
+		}
+		f := frontmatterField{Value: value, Line: i + 2} // +1 for the opening ---, +1 for 1-based
+
+		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			f.IsList = true
+			f.Items = FrontmatterGetList(line, key)
+		} else if value == "" {
+			for j := i + 1; j < len(lines); j++ {
+				t := strings.TrimSpace(lines[j])
+				if !strings.HasPrefix(t, "- ") && t != "-" {
+					break
+				}
+				f.IsList = true
+				if item := strings.Trim(strings.TrimSpace(strings.TrimPrefix(t, "-")), "\"'"); item != "" {
+					f.Items = append(f.Items, item)
+				}
+			}
+			if next := i + 1; !f.IsList && next < len(lines) && strings.TrimSpace(lines[next]) != "" &&
+				(lines[next][0] == ' ' || lines[next][0] == '\t') {
+				f.IsMap = true
+			}
+		} else {
+			f.Value = strings.Trim(value, "\"'")
+		}
+		fields[key] = f
+	}
+	return fields
+}
+
+// checkSchemaValue returns a rule name and message if field violates p.
+func checkSchemaValue(key string, field frontmatterField, p SchemaProperty) (string, string) {
+	if field.IsMap {
+		if p.Type != "" {
+			return "type", fmt.Sprintf("%s must be a %s, not a map", key, p.Type)
+		}
+		if len(p.Enum) > 0 {
+			return "enum", fmt.Sprintf("%s must be one of: %s, not a map", key, strings.Join(p.Enum, ", "))
+		}
+		return "", ""
+	}
+	switch p.Type {
+	case "list":
+		if !field.IsList {
+			return "type", fmt.Sprintf("%s must be a list", key)
+		}
+	case "string":
+		if field.IsList {
+			return "type", fmt.Sprintf("%s must be a string, not a list", key)
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(field.Value, 64); field.IsList || err != nil {
+			return "type", fmt.Sprintf("%s must be a number, got %q", key, field.Value)
+		}
+	case "boolean":
+		if field.IsList || (field.Value != "true" && field.Value != "false") {
+			return "type", fmt.Sprintf("%s must be true or false, got %q", key, field.Value)
+		}
+	case "date":
+		if _, err := ParseMoment(field.Value, p.Format, time.UTC); field.IsList || err != nil {
+			return "format", fmt.Sprintf("%s must be a date in %s format, got %q", key, p.Format, field.Value)
+		}
+	}
+
+	if len(p.Enum) > 0 {
+		values := field.Items
+		if !field.IsList {
+			values = []string{field.Value}
+		}
+		for _, val := range values {
+			allowed := false
+			for _, e := range p.Enum {
+				if val == e {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return "enum", fmt.Sprintf("%s value %q is not one of: %s", key, val, strings.Join(p.Enum, ", "))
+			}
+		}
+	}
+	return "", ""
+}
+
+// validateNote checks a note's frontmatter against every matching schema.
+func validateNote(relPath, text string, schemas []Schema) []SchemaViolation {
+	yaml, _, _ := ExtractFrontmatter(text)
+	fields := frontmatterFields(yaml)
+
+	var tags []string
+	var violations []SchemaViolation
+	for _, s := range schemas {
+		if len(s.Tags) > 0 && tags == nil {
+			tags = AllNoteTags(text)
+		}
+		if !s.matches(relPath, yaml, tags) {
+			continue
+		}
+
+		for _, key := range s.Required {
+			f, ok := fields[key]
+			if !ok || (f.Value == "" && len(f.Items) == 0 && !f.IsMap) {
+				line := 1
+				if ok {
+					line = f.Line
+				}
+				violations = append(violations, SchemaViolation{
+					Path: relPath, Line: line, Schema: s.Name, Key: key, Rule: "required",
+					Message: fmt.Sprintf("missing required property %s", key),
+				})
+			}
+		}
+
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			f, ok := fields[key]
+			if !ok || (f.Value == "" && len(f.Items) == 0 && !f.IsList && !f.IsMap) {
+				continue // absence is the required rule's concern
+			}
+			if rule, msg := checkSchemaValue(key, f, s.Properties[key]); rule != "" {
+				violations = append(violations, SchemaViolation{
+					Path: relPath, Line: f.Line, Schema: s.Name, Key: key, Rule: rule, Message: msg,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// Validate checks note frontmatter against the schemas in .vlt/lint.yaml.
+// With a title, only that note is checked; otherwise the whole vault.
+// Violations are sorted by path and line.
+func (v *Vault) Validate(title string) ([]SchemaViolation, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	cfg, err := loadLintConfig(v.dir)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := parseSchemas(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []SchemaViolation
+	check := func(path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		relPath, _ := filepath.Rel(v.dir, path)
+		violations = append(violations, validateNote(relPath, string(data), schemas)...)
+	}
+
+	if title != "" {
+		path, err := resolveNote(v.dir, title)
+		if err != nil {
+			return nil, err
+		}
+		check(path)
+		return violations, nil
+	}
+
+	err = filepath.WalkDir(v.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipHiddenDir(path, d, v.dir) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".md") {
+			check(path)
+		}
+		return nil
+	})
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Path != violations[j].Path {
+			return violations[i].Path < violations[j].Path
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, err
+}
@@ -0,0 +1,135 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testSchemaConfig = `schemas:
+  meeting:
+    match:
+      type: meeting
+      folder: meetings
+    required: [date, attendees]
+    properties:
+      date:
+        type: date
+      attendees:
+        type: list
+      status:
+        enum: [scheduled, done]
+  person:
+    match:
+      tag: person
+    required:
+      - email
+    properties:
+      age:
+        type: number
+      active:
+        type: boolean
+  book:
+    match:
+      folder: books
+    required: [author, publisher]
+    properties:
+      publisher:
+        type: string
+`
+
+func TestFrontmatterFields(t *testing.T) {
+	yaml := "title: \"Standup\"\ntags: [a, b]\nattendees:\n  - Alice\n  - Bob\nnested:\n  child: x\nempty:\n"
+	fields := frontmatterFields(yaml)
+
+	if f := fields["title"]; f.Value != "Standup" || f.IsList || f.Line != 2 {
+		t.Errorf("title = %+v", f)
+	}
+	if f := fields["tags"]; !f.IsList || strings.Join(f.Items, ",") != "a,b" {
+		t.Errorf("tags = %+v", f)
+	}
+	if f := fields["attendees"]; !f.IsList || strings.Join(f.Items, ",") != "Alice,Bob" || f.Line != 4 {
+		t.Errorf("attendees = %+v", f)
+	}
+	if f := fields["nested"]; !f.IsMap || f.IsList || f.Line != 7 {
+		t.Errorf("nested = %+v", f)
+	}
+	if _, ok := fields["child"]; ok {
+		t.Error("nested keys should not be top-level fields")
+	}
+	if f, ok := fields["empty"]; !ok || f.IsList || f.IsMap || f.Value != "" {
+		t.Errorf("empty = %+v, %v", f, ok)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "lint.yaml"), []byte(testSchemaConfig), 0644)
+	os.MkdirAll(filepath.Join(vaultDir, "meetings"), 0755)
+
+	os.WriteFile(filepath.Join(vaultDir, "meetings", "Good.md"),
+		[]byte("---\ndate: 2026-03-01\nattendees: [Alice]\nstatus: done\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "meetings", "Bad.md"),
+		[]byte("---\ndate: March 1\nattendees: Alice\nstatus: cancelled\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Sync.md"),
+		[]byte("---\ntype: Meeting\nattendees:\n  - Bob\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Alice.md"),
+		[]byte("---\ntags: [person/engineer]\nage: thirty\nactive: yes\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Plain.md"), []byte("No frontmatter.\n"), 0644)
+	// A required key holding a nested map is present; it fails only a type.
+	os.MkdirAll(filepath.Join(vaultDir, "books"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "books", "Dune.md"),
+		[]byte("---\nauthor:\n  name: Frank Herbert\n  email: frank@example.com\npublisher:\n  name: Chilton\n---\n"), 0644)
+
+	violations, err := v.Validate("")
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	var got []string
+	for _, sv := range violations {
+		got = append(got, strings.Join([]string{sv.Path, strconv.Itoa(sv.Line), sv.Schema, sv.Key, sv.Rule}, ":"))
+	}
+	bad := filepath.Join("meetings", "Bad.md")
+	want := []string{
+		"Alice.md:1:person:email:required",
+		"Alice.md:3:person:age:type",
+		"Alice.md:4:person:active:type",
+		"Sync.md:1:meeting:date:required",
+		filepath.Join("books", "Dune.md") + ":5:book:publisher:type",
+		bad + ":2:meeting:date:format",
+		bad + ":3:meeting:attendees:type",
+		bad + ":4:meeting:status:enum",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A single note can be validated by title.
+	violations, err = v.Validate("Good")
+	if err != nil || len(violations) != 0 {
+		t.Errorf("Validate(Good) = %+v, %v", violations, err)
+	}
+}
+
+func TestParseSchemas_Errors(t *testing.T) {
+	tests := map[string]string{
+		"no schemas":   "tags:\n  case: lower\n",
+		"no match":     "schemas:\n  x:\n    required: [a]\n",
+		"unknown type": "schemas:\n  x:\n    match:\n      type: x\n    properties:\n      a:\n        type: float\n",
+	}
+	for name, text := range tests {
+		cfg, err := parseYAMLConfig(text)
+		if err != nil {
+			t.Fatalf("%s: parse: %v", name, err)
+		}
+		if _, err := parseSchemas(cfg); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package vlt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// snapshotsEnabled reports whether registered content should be kept as
+// snapshots (VLT_SNAPSHOTS=1).
+func snapshotsEnabled() bool {
+	return os.Getenv("VLT_SNAPSHOTS") == "1"
+}
+
+// snapshotPath returns where the snapshot of content with the given hash
+// is stored: objects/<first 2 hex chars>/<rest>.gz in the registry dir.
+func (r *Registry) snapshotPath(hash string) string {
+	return filepath.Join(r.dir, "objects", hash[:2], hash[2:]+".gz")
+}
+
+// saveSnapshot stores gzip-compressed content under its hash. Snapshots are
+// content-addressed, so an existing one is never rewritten.
+func (r *Registry) saveSnapshot(hash string, content []byte) error {
+	path := r.snapshotPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(content); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// loadSnapshot returns the content stored under hash, checking that it
+// still hashes to the same value.
+func (r *Registry) loadSnapshot(hash string) ([]byte, error) {
+	f, err := os.Open(r.snapshotPath(hash))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s is corrupted: %v", hash[:12], err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s is corrupted: %v", hash[:12], err)
+	}
+	if contentHash(content) != hash {
+		return nil, fmt.Errorf("snapshot %s is corrupted: content does not match its hash", hash[:12])
+	}
+	return content, nil
+}
+
+// registeredFile resolves title to a registered file. Besides note titles
+// it accepts a registered vault-relative path, so files deleted outside vlt
+// can still be diffed and restored.
+func (v *Vault) registeredFile(title string) (absPath, rel string, err error) {
+	absPath, err = resolveNote(v.dir, title)
+	if err != nil {
+		rel = filepath.FromSlash(title)
+		if !strings.HasSuffix(rel, ".md") {
+			rel += ".md"
+		}
+		v.registry.mu.Lock()
+		_, ok := v.registry.entries[rel]
+		v.registry.mu.Unlock()
+		if !ok {
+			return "", "", err
+		}
+		absPath, err = safePath(v.dir, rel)
+		return absPath, rel, err
+	}
+	rel, err = filepath.Rel(v.dir, absPath)
+	return absPath, rel, err
+}
+
+// registeredSnapshot returns the snapshot of the registered version of rel.
+func (v *Vault) registeredSnapshot(title, rel string) ([]byte, error) {
+	v.registry.mu.Lock()
+	entry, ok := v.registry.entries[rel]
+	v.registry.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%q is not registered -- run integrity:baseline first", title)
+	}
+	content, err := v.registry.loadSnapshot(entry.Hash)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no snapshot of the registered version of %q (set VLT_SNAPSHOTS=1 to keep snapshots)", title)
+	}
+	return content, err
+}
+
+// IntegrityDiff returns a unified diff from the last version registered by
+// vlt to the file's current content, or "" when they are equal. It needs
+// the snapshot taken when that version was registered (VLT_SNAPSHOTS=1).
+func (v *Vault) IntegrityDiff(title string) (string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	path, rel, err := v.registeredFile(title)
+	if err != nil {
+		return "", err
+	}
+	registered, err := v.registeredSnapshot(title, rel)
+	if err != nil {
+		return "", err
+	}
+
+	aName, bName := "a/"+filepath.ToSlash(rel), "b/"+filepath.ToSlash(rel)
+	current, err := os.ReadFile(path)
+	deleted := os.IsNotExist(err)
+	if deleted {
+		bName = "/dev/null"
+	} else if err != nil {
+		return "", err
+	}
+	diff := unifiedDiff(aName, bName, string(registered), string(current), 3)
+	if diff == "" && (deleted || !bytes.Equal(registered, current)) {
+		// Never report "no changes" for a file that differs, even when
+		// there is no line to show (an empty file that was deleted).
+		diff = fmt.Sprintf("Files %s and %s differ\n", aName, bName)
+	}
+	return diff, nil
+}
+
+// IntegrityRestore rolls a file back to the last version registered by
+// vlt, recreating it if it was deleted. It needs the snapshot taken when
+// that version was registered (VLT_SNAPSHOTS=1).
+func (v *Vault) IntegrityRestore(title string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	path, rel, err := v.registeredFile(title)
+	if err != nil {
+		return err
+	}
+	content, err := v.registeredSnapshot(title, rel)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+	v.registry.register(v.dir, path, content)
+	return nil
+}
@@ -0,0 +1,106 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegrityDiffAndRestore(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	v.registry.snapshots = true
+
+	path := filepath.Join(vaultDir, "notes", "Plan.md")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	original := []byte("# Plan\n\nShip on Friday.\n")
+	os.WriteFile(path, original, 0644)
+	v.registry.register(vaultDir, path, original)
+
+	if diff, err := v.IntegrityDiff("Plan"); err != nil || diff != "" {
+		t.Fatalf("IntegrityDiff unchanged = %q, %v", diff, err)
+	}
+
+	os.WriteFile(path, []byte("# Plan\n\nShip never.\n"), 0644)
+	diff, err := v.IntegrityDiff("Plan")
+	if err != nil {
+		t.Fatalf("IntegrityDiff: %v", err)
+	}
+	for _, want := range []string{"--- a/notes/Plan.md\n+++ b/notes/Plan.md\n", "-Ship on Friday.\n", "+Ship never.\n"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+
+	// Dropping only the final newline is still a change.
+	os.WriteFile(path, []byte("# Plan\n\nShip on Friday."), 0644)
+	diff, err = v.IntegrityDiff("Plan")
+	if err != nil || !strings.Contains(diff, "+Ship on Friday.\n\\ No newline at end of file\n") {
+		t.Errorf("IntegrityDiff newline-only change = %q, %v", diff, err)
+	}
+
+	if err := v.IntegrityRestore("Plan"); err != nil {
+		t.Fatalf("IntegrityRestore: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(original) {
+		t.Errorf("restored content = %q", data)
+	}
+
+	// A file deleted outside vlt is addressed by its registered path.
+	os.Remove(path)
+	diff, err = v.IntegrityDiff("notes/Plan")
+	if err != nil || !strings.Contains(diff, "+++ /dev/null") {
+		t.Fatalf("IntegrityDiff deleted = %q, %v", diff, err)
+	}
+	if err := v.IntegrityRestore("notes/Plan.md"); err != nil {
+		t.Fatalf("IntegrityRestore deleted: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(original) {
+		t.Errorf("recreated content = %q", data)
+	}
+}
+
+func TestIntegrityDiff_Errors(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+
+	path := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(path, []byte("# Note\n"), 0644)
+	if _, err := v.IntegrityDiff("Note"); err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("unregistered: got %v", err)
+	}
+
+	v.registry.register(vaultDir, path, []byte("# Note\n"))
+	if _, err := v.IntegrityDiff("Note"); err == nil || !strings.Contains(err.Error(), "VLT_SNAPSHOTS=1") {
+		t.Errorf("no snapshot: got %v", err)
+	}
+
+	// A snapshot whose content no longer matches its hash is rejected.
+	v.registry.snapshots = true
+	v.registry.register(vaultDir, path, []byte("# Note v2\n"))
+	hash := contentHash([]byte("# Note v2\n"))
+	v.registry.saveSnapshot(contentHash([]byte("other")), []byte("other"))
+	os.Rename(v.registry.snapshotPath(contentHash([]byte("other"))), v.registry.snapshotPath(hash))
+	if err := v.IntegrityRestore("Note"); err == nil || !strings.Contains(err.Error(), "corrupted") {
+		t.Errorf("corrupted snapshot: got %v", err)
+	}
+}
+
+func TestSnapshotError(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	t.Cleanup(func() { os.RemoveAll(v.registry.dir) })
+	v.registry.snapshots = true
+
+	// A file in place of objects/ makes every snapshot fail.
+	os.MkdirAll(v.registry.dir, 0700)
+	os.WriteFile(filepath.Join(v.registry.dir, "objects"), nil, 0600)
+	path := filepath.Join(vaultDir, "Note.md")
+	v.registry.register(vaultDir, path, []byte("# Note\n"))
+	if err := v.SnapshotError(); err == nil || !strings.Contains(err.Error(), "snapshot of Note.md") {
+		t.Errorf("SnapshotError = %v, want failure for Note.md", err)
+	}
+}
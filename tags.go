@@ -1,6 +1,7 @@
 package vlt
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -179,3 +180,395 @@ func (v *Vault) Tag(tagName string) ([]string, error) {
 	sort.Strings(results)
 	return results, nil
 }
+
+// walkNoteTags calls fn with the vault-relative path and AllNoteTags of
+// every note in the vault.
+func walkNoteTags(vaultDir string, fn func(relPath string, tags []string)) error {
+	return filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipHiddenDir(path, d, vaultDir) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		fn(relPath, AllNoteTags(string(data)))
+		return nil
+	})
+}
+
+// TagRollup returns, for every tag and every parent of a hierarchical tag,
+// the number of notes carrying it or any of its subtags. A note tagged both
+// #project and #project/api counts once toward "project".
+func (v *Vault) TagRollup() (map[string]int, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	counts := make(map[string]int)
+	err := walkNoteTags(v.dir, func(_ string, tags []string) {
+		seen := make(map[string]bool)
+		for _, tag := range tags {
+			parts := strings.Split(tag, "/")
+			for i := range parts {
+				prefix := strings.Join(parts[:i+1], "/")
+				if prefix != "" && !seen[prefix] {
+					seen[prefix] = true
+					counts[prefix]++
+				}
+			}
+		}
+	})
+	return counts, err
+}
+
+// RelatedTags lists tags that appear in notes carrying tagName (or one of its
+// subtags), with the number of such notes each appears in. The tag itself and
+// its subtags are excluded. Sorted by count, then alphabetically.
+func (v *Vault) RelatedTags(tagName string) ([]string, map[string]int, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	tagLower := strings.ToLower(strings.TrimPrefix(tagName, "#"))
+	matches := func(t string) bool {
+		return t == tagLower || strings.HasPrefix(t, tagLower+"/")
+	}
+
+	counts := make(map[string]int)
+	err := walkNoteTags(v.dir, func(_ string, tags []string) {
+		tagged := false
+		for _, t := range tags {
+			if matches(t) {
+				tagged = true
+				break
+			}
+		}
+		if !tagged {
+			return
+		}
+		for _, t := range tags {
+			if !matches(t) {
+				counts[t]++
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	related := make([]string, 0, len(counts))
+	for t := range counts {
+		related = append(related, t)
+	}
+	sort.Slice(related, func(i, j int) bool {
+		if counts[related[i]] != counts[related[j]] {
+			return counts[related[i]] > counts[related[j]]
+		}
+		return related[i] < related[j]
+	})
+	return related, counts, nil
+}
+
+// validTagPattern matches a tag name that can be written inline.
+var validTagPattern = regexp.MustCompile(`^[\p{L}\p{N}_/-]+$`)
+
+// normalizeTagArg strips a leading # from a tag argument and checks that it
+// is a usable tag name.
+func normalizeTagArg(tag string) (string, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if !validTagPattern.MatchString(tag) || !hasLetter(tag) ||
+		strings.HasPrefix(tag, "/") || strings.HasSuffix(tag, "/") || strings.Contains(tag, "//") {
+		return "", fmt.Errorf("invalid tag %q: use letters, digits, _, - and / (with at least one letter)", tag)
+	}
+	return tag, nil
+}
+
+// renameTag maps tag to its new name when it is from or a subtag of from
+// (case-insensitive): renameTag("Proj/X", "proj", "project") is
+// "project/X". The subtag suffix keeps its original case.
+func renameTag(tag, from, to string) (string, bool) {
+	if len(tag) < len(from) || !strings.EqualFold(tag[:len(from)], from) {
+		return tag, false
+	}
+	if len(tag) > len(from) && tag[len(from)] != '/' {
+		return tag, false
+	}
+	return to + tag[len(from):], true
+}
+
+// bodyOffset returns the byte offset where the note body starts (after the
+// frontmatter, if any).
+func bodyOffset(text string) int {
+	_, bodyStart, hasFM := ExtractFrontmatter(text)
+	if !hasFM {
+		return 0
+	}
+	offset := 0
+	for i := 0; i < bodyStart; i++ {
+		nl := strings.IndexByte(text[offset:], '\n')
+		if nl == -1 {
+			return len(text)
+		}
+		offset += nl + 1
+	}
+	return offset
+}
+
+// replaceInlineTags rewrites inline #tags in the note body using rename,
+// which returns the new tag name and whether to replace it. An empty new name
+// removes the tag along with its # and one separating space. Tags inside
+// inert zones (code, comments, math) are left untouched. Returns the new text
+// and the number of tags replaced.
+func replaceInlineTags(text string, rename func(string) (string, bool)) (string, int) {
+	start := bodyOffset(text)
+	masked := MaskInertContent(text)
+
+	var b strings.Builder
+	last, count := 0, 0
+	for _, m := range tagPattern.FindAllStringSubmatchIndex(masked[start:], -1) {
+		tagStart, tagEnd := start+m[2], start+m[3]
+		newTag, ok := rename(text[tagStart:tagEnd])
+		if !ok {
+			continue
+		}
+		if newTag == "" {
+			tagStart-- // the #
+			if tagStart > last && text[tagStart-1] == ' ' {
+				tagStart--
+			} else if tagEnd < len(text) && text[tagEnd] == ' ' {
+				tagEnd++
+			}
+		}
+		b.WriteString(text[last:tagStart])
+		b.WriteString(newTag)
+		last = tagEnd
+		count++
+	}
+	if count == 0 {
+		return text, 0
+	}
+	b.WriteString(text[last:])
+	return b.String(), count
+}
+
+// dedupeTags removes case-insensitive duplicates, keeping the first spelling.
+func dedupeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, t := range tags {
+		lower := strings.ToLower(t)
+		if !seen[lower] {
+			seen[lower] = true
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// renameNoteTags renames from (and its subtags) to to in a note's frontmatter
+// tags list and inline body tags. Renaming into a tag the note already has
+// merges them in the frontmatter list. Returns the new text and the number of
+// occurrences renamed.
+func renameNoteTags(text, from, to string) (string, int) {
+	count := 0
+	if yaml, _, hasFM := ExtractFrontmatter(text); hasFM {
+		tags := FrontmatterGetList(yaml, "tags")
+		changed := false
+		for i, t := range tags {
+			if renamed, ok := renameTag(strings.TrimPrefix(t, "#"), from, to); ok {
+				tags[i] = renamed
+				changed = true
+				count++
+			}
+		}
+		if changed {
+			text = frontmatterSetList(text, "tags", dedupeTags(tags))
+		}
+	}
+
+	text, n := replaceInlineTags(text, func(tag string) (string, bool) {
+		return renameTag(tag, from, to)
+	})
+	return text, count + n
+}
+
+// TagRenameResult reports how many tag occurrences were renamed in a file.
+type TagRenameResult struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+// TagRename renames a tag and all of its subtags across the vault, in both
+// frontmatter tags lists and inline #tags (outside code blocks, comments and
+// math). Matching is case-insensitive; renaming into an existing tag merges
+// the two. Returns per-file counts sorted by path.
+func (v *Vault) TagRename(from, to string) ([]TagRenameResult, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	from, err := normalizeTagArg(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = normalizeTagArg(to)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("tag:rename: from and to are the same tag")
+	}
+
+	var results []TagRenameResult
+	var edits []fileEdit
+	err = filepath.WalkDir(v.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipHiddenDir(path, d, v.dir) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		updated, count := renameNoteTags(string(data), from, to)
+		if count == 0 {
+			return nil
+		}
+		edits = append(edits, fileEdit{path, []byte(updated)})
+
+		relPath, _ := filepath.Rel(v.dir, path)
+		results = append(results, TagRenameResult{Path: relPath, Count: count})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Every file is guarded before the first one is rewritten.
+	if _, err := applyEdits(v.dir, edits, v.registry); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	return results, nil
+}
+
+// TagAdd adds a tag to a note's frontmatter tags list, creating the list (and
+// frontmatter) if needed. Returns false if the note already lists the tag
+// (compared case-insensitively).
+func (v *Vault) TagAdd(title, tag string) (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	tag, err := normalizeTagArg(tag)
+	if err != nil {
+		return false, err
+	}
+
+	path, err := resolveNote(v.dir, title)
+	if err != nil {
+		return false, err
+	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	text := string(data)
+
+	var tags []string
+	if yaml, _, hasFM := ExtractFrontmatter(text); hasFM {
+		tags = FrontmatterGetList(yaml, "tags")
+	}
+	for _, t := range tags {
+		if strings.EqualFold(strings.TrimPrefix(t, "#"), tag) {
+			return false, nil
+		}
+	}
+
+	updated := []byte(frontmatterSetList(text, "tags", dedupeTags(append(tags, tag))))
+	if err := os.WriteFile(path, updated, 0644); err != nil {
+		return false, err
+	}
+	v.registry.register(v.dir, path, updated)
+	return true, nil
+}
+
+// TagRemove removes a tag from a note's frontmatter tags list (case-insensitive),
+// dropping the tags key once the list is empty. With inline, matching inline
+// #tags in the body are stripped too. Subtags are not affected. Returns the
+// number of occurrences removed.
+func (v *Vault) TagRemove(title, tag string, inline bool) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	tag, err := normalizeTagArg(tag)
+	if err != nil {
+		return 0, err
+	}
+
+	path, err := resolveNote(v.dir, title)
+	if err != nil {
+		return 0, err
+	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	text := string(data)
+
+	removed := 0
+	if yaml, _, hasFM := ExtractFrontmatter(text); hasFM {
+		var kept []string
+		tags := FrontmatterGetList(yaml, "tags")
+		for _, t := range tags {
+			if strings.EqualFold(strings.TrimPrefix(t, "#"), tag) {
+				removed++
+			} else {
+				kept = append(kept, t)
+			}
+		}
+		switch {
+		case removed == 0:
+		case len(kept) == 0:
+			text = frontmatterRemoveKey(text, "tags")
+		default:
+			text = frontmatterSetList(text, "tags", dedupeTags(kept))
+		}
+	}
+
+	if inline {
+		var n int
+		text, n = replaceInlineTags(text, func(t string) (string, bool) {
+			return "", strings.EqualFold(t, tag)
+		})
+		removed += n
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+	updated := []byte(text)
+	if err := os.WriteFile(path, updated, 0644); err != nil {
+		return 0, err
+	}
+	v.registry.register(v.dir, path, updated)
+	return removed, nil
+}
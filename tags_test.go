@@ -3,6 +3,7 @@ package vlt
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -223,3 +224,188 @@ func TestTag_StripHash(t *testing.T) {
 		t.Fatalf("Tag with hash: got %d files, want 1", len(files))
 	}
 }
+
+func TestRenameTag(t *testing.T) {
+	tests := []struct {
+		tag, want string
+		ok        bool
+	}{
+		{"proj", "project", true},
+		{"Proj", "project", true},
+		{"proj/X", "project/X", true},
+		{"project", "project", false},
+		{"projx", "projx", false},
+		{"other/proj", "other/proj", false},
+	}
+	for _, tt := range tests {
+		got, ok := renameTag(tt.tag, "proj", "project")
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("renameTag(%q) = %q, %v; want %q, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRenameNoteTags(t *testing.T) {
+	text := "---\ntags: [proj/x, project/x, misc]\n---\n" +
+		"Working on #proj/x and #PROJ today. Not #projection.\n" +
+		"```\n#proj in code\n```\n" +
+		"Inline `#proj` too.\n"
+
+	got, count := renameNoteTags(text, "proj", "project")
+	want := "---\ntags: [project/x, misc]\n---\n" +
+		"Working on #project/x and #project today. Not #projection.\n" +
+		"```\n#proj in code\n```\n" +
+		"Inline `#proj` too.\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+}
+
+func TestTagRename(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "a.md"), []byte("---\ntags:\n  - proj/x\n---\n#proj/y\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "b.md"), []byte("Just #proj here.\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "c.md"), []byte("Nothing to see.\n"), 0644)
+
+	results, err := v.TagRename("#proj", "project")
+	if err != nil {
+		t.Fatalf("TagRename: %v", err)
+	}
+	if len(results) != 2 || results[0] != (TagRenameResult{"a.md", 2}) || results[1] != (TagRenameResult{"b.md", 1}) {
+		t.Errorf("results = %+v", results)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "a.md"))
+	if string(data) != "---\ntags:\n  - project/x\n---\n#project/y\n" {
+		t.Errorf("a.md = %q", data)
+	}
+
+	notes, _ := v.Tag("project")
+	if len(notes) != 2 {
+		t.Errorf("Tag(project) = %v, want 2 notes", notes)
+	}
+
+	if _, err := v.TagRename("proj", "proj"); err == nil {
+		t.Error("expected error renaming a tag to itself")
+	}
+	if _, err := v.TagRename("proj", "bad tag"); err == nil {
+		t.Error("expected error for invalid target tag")
+	}
+}
+
+func TestTagAdd(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "Plain.md"), []byte("# Plain\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Tagged.md"), []byte("---\ntags: [Review]\n---\nbody\n"), 0644)
+
+	added, err := v.TagAdd("Plain", "#idea")
+	if err != nil || !added {
+		t.Fatalf("TagAdd(Plain) = %v, %v", added, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Plain.md"))
+	if string(data) != "---\ntags:\n  - idea\n---\n# Plain\n" {
+		t.Errorf("Plain.md = %q", data)
+	}
+
+	added, err = v.TagAdd("Tagged", "review")
+	if err != nil || added {
+		t.Errorf("TagAdd(duplicate) = %v, %v; want false, nil", added, err)
+	}
+	if _, err := v.TagAdd("Tagged", "project/alpha"); err != nil {
+		t.Fatalf("TagAdd: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "Tagged.md"))
+	if string(data) != "---\ntags: [Review, project/alpha]\n---\nbody\n" {
+		t.Errorf("Tagged.md = %q", data)
+	}
+
+	if _, err := v.TagAdd("Plain", "42"); err == nil {
+		t.Error("expected error for numeric tag")
+	}
+}
+
+func TestTagRemove(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	note := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(note, []byte("---\ntags:\n  - draft\n  - idea\n---\nA #Draft note. #draft/sub\n"), 0644)
+
+	removed, err := v.TagRemove("Note", "draft", false)
+	if err != nil || removed != 1 {
+		t.Fatalf("TagRemove = %d, %v; want 1", removed, err)
+	}
+	data, _ := os.ReadFile(note)
+	if string(data) != "---\ntags:\n  - idea\n---\nA #Draft note. #draft/sub\n" {
+		t.Errorf("frontmatter only: %q", data)
+	}
+
+	removed, err = v.TagRemove("Note", "draft", true)
+	if err != nil || removed != 1 {
+		t.Fatalf("TagRemove inline = %d, %v; want 1", removed, err)
+	}
+	data, _ = os.ReadFile(note)
+	if string(data) != "---\ntags:\n  - idea\n---\nA note. #draft/sub\n" {
+		t.Errorf("inline: %q", data)
+	}
+
+	removed, _ = v.TagRemove("Note", "idea", false)
+	data, _ = os.ReadFile(note)
+	if removed != 1 || string(data) != "---\n---\nA note. #draft/sub\n" {
+		t.Errorf("last tag: removed=%d, %q", removed, data)
+	}
+
+	if removed, _ := v.TagRemove("Note", "missing", true); removed != 0 {
+		t.Errorf("missing tag removed %d", removed)
+	}
+}
+
+func TestTagRollup(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "a.md"), []byte("#project #project/api\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "b.md"), []byte("#project/api/v2\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "c.md"), []byte("#meeting\n"), 0644)
+
+	counts, err := v.TagRollup()
+	if err != nil {
+		t.Fatalf("TagRollup: %v", err)
+	}
+	want := map[string]int{"project": 2, "project/api": 2, "project/api/v2": 1, "meeting": 1}
+	if len(counts) != len(want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+	for tag, n := range want {
+		if counts[tag] != n {
+			t.Errorf("counts[%q] = %d, want %d", tag, counts[tag], n)
+		}
+	}
+}
+
+func TestRelatedTags(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "a.md"), []byte("#project #review #urgent\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "b.md"), []byte("#project/api #review\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "c.md"), []byte("#review #other\n"), 0644)
+
+	related, counts, err := v.RelatedTags("#Project")
+	if err != nil {
+		t.Fatalf("RelatedTags: %v", err)
+	}
+	if strings.Join(related, ",") != "review,urgent" {
+		t.Errorf("related = %v, want [review urgent]", related)
+	}
+	if counts["review"] != 2 || counts["urgent"] != 1 {
+		t.Errorf("counts = %v", counts)
+	}
+}
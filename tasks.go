@@ -5,51 +5,206 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// Task represents a parsed checkbox item from a note.
+// Task represents a parsed checkbox item from a note. Metadata written in
+// the Obsidian Tasks plugin emoji format (📅 2025-01-31, ⏫, 🔁 every week)
+// is parsed into the structured fields; Text keeps the line verbatim.
 type Task struct {
-	Text string `json:"text"` // task text after the checkbox
-	Done bool   `json:"done"` // true if [x] or [X]
-	Line int    `json:"line"` // 1-based line number
-	File string `json:"file"` // relative path (when searching vault-wide)
+	Text        string `json:"text"`                 // task text after the checkbox
+	Done        bool   `json:"done"`                 // true if [x] or [X]
+	Line        int    `json:"line"`                 // 1-based line number
+	File        string `json:"file"`                 // relative path (when searching vault-wide)
+	Symbol      string `json:"symbol"`               // checkbox character: " ", "x", "/", "-", ...
+	Status      string `json:"status"`               // todo, done, in-progress, cancelled
+	Description string `json:"description"`          // Text without Tasks metadata
+	Priority    string `json:"priority,omitempty"`   // highest, high, medium, low, lowest
+	Due         string `json:"due,omitempty"`        // 📅 YYYY-MM-DD
+	Scheduled   string `json:"scheduled,omitempty"`  // ⏳ YYYY-MM-DD
+	Start       string `json:"start,omitempty"`      // 🛫 YYYY-MM-DD
+	Created     string `json:"created,omitempty"`    // ➕ YYYY-MM-DD
+	DoneDate    string `json:"done_date,omitempty"`  // ✅ YYYY-MM-DD
+	Cancelled   string `json:"cancelled,omitempty"`  // ❌ YYYY-MM-DD
+	Recurrence  string `json:"recurrence,omitempty"` // 🔁 rule, e.g. "every week"
+	Indent      int    `json:"indent"`               // nesting level: 0 for top-level tasks
+	Parent      int    `json:"parent,omitempty"`     // line of the enclosing task, 0 if none
+	Heading     string `json:"heading,omitempty"`    // text of the nearest heading above
+
+	// Integrity is the integrity status of File, set by Vault.Tasks.
+	Integrity IntegrityStatus `json:"integrity"`
 }
 
 // TaskOptions parameterises a Tasks call.
 type TaskOptions struct {
-	File    string // single-file mode: resolve by title
-	Path    string // vault-wide mode: limit to subfolder
-	Done    bool   // filter: only completed tasks
-	Pending bool   // filter: only incomplete tasks
+	File      string   // single-file mode: resolve by title
+	Path      string   // vault-wide mode: limit to subfolder
+	Done      bool     // filter: only completed tasks
+	Pending   bool     // filter: only open (not done or cancelled) tasks
+	Status    []string // filter: status names or checkbox symbols
+	Priority  []string // filter: priority names ("none" for unprioritised)
+	Due       string   // filter: due date or range "from..to" (either end optional; see ResolveDate)
+	Scheduled string   // filter: scheduled date or range
+	Overdue   bool     // filter: open tasks due before today
+	Sort      string   // due, scheduled, start, priority or status (default: file order)
+	Section   string   // filter: tasks under this heading ("## Name" or "Name")
 }
 
-// taskPattern matches markdown checkboxes: - [ ] text or - [x] text
-// Allows leading whitespace/tabs for nesting.
-var taskPattern = regexp.MustCompile(`(?m)^[\t ]*- \[([ xX])\] (.+)$`)
+// taskPattern matches markdown checkboxes in any list style (-, *, +, 1.
+// or 1)) with [ ], [x] or any other single-character status such as [/]
+// or [-]. Allows leading whitespace/tabs for nesting.
+// Groups: 1 = indentation, 2 = list marker, 3 = status symbol, 4 = text.
+var taskPattern = regexp.MustCompile(`(?m)^([\t ]*)([-*+]|\d+[.)]) \[(.)\] (.+)$`)
+
+// listItemPattern matches any list item, task or not.
+var listItemPattern = regexp.MustCompile(`^[\t ]*([-*+]|\d+[.)]) `)
+
+// taskFieldPattern matches Tasks plugin metadata: dated fields, recurrence
+// rules and priority markers, each optionally followed by U+FE0F.
+var taskFieldPattern = regexp.MustCompile(
+	`(📅|⏳|🛫|➕|✅|❌)\x{FE0F}? *(\d{4}-\d{2}-\d{2})|🔁\x{FE0F}? *([a-zA-Z0-9, !]+)|(🔺|⏫|🔼|🔽|⏬)\x{FE0F}?`)
+
+// taskStatusNames maps checkbox symbols to status names. Unknown symbols
+// are treated as todo, as in the Tasks plugin.
+var taskStatusNames = map[string]string{
+	" ": "todo",
+	"x": "done",
+	"X": "done",
+	"/": "in-progress",
+	"-": "cancelled",
+}
+
+// taskPriorities maps priority emoji to names.
+var taskPriorities = map[string]string{
+	"🔺": "highest",
+	"⏫": "high",
+	"🔼": "medium",
+	"🔽": "low",
+	"⏬": "lowest",
+}
+
+// taskPriorityRank orders priorities for sorting; unprioritised tasks sit
+// between medium and low, matching the Tasks plugin.
+var taskPriorityRank = map[string]int{
+	"highest": 0, "high": 1, "medium": 2, "": 3, "low": 4, "lowest": 5,
+}
+
+// taskStatusRank orders statuses for sorting: active work first.
+var taskStatusRank = map[string]int{
+	"in-progress": 0, "todo": 1, "done": 2, "cancelled": 3,
+}
+
+// parseTaskFields fills the status and metadata fields of t from its
+// checkbox symbol and text.
+func parseTaskFields(t *Task, symbol string) {
+	t.Symbol = symbol
+	t.Status = taskStatusNames[symbol]
+	if t.Status == "" {
+		t.Status = "todo"
+	}
 
-// ParseTasks extracts all checkbox items from text.
+	var desc strings.Builder
+	last := 0
+	for _, m := range taskFieldPattern.FindAllStringSubmatchIndex(t.Text, -1) {
+		desc.WriteString(t.Text[last:m[0]])
+		last = m[1]
+		switch {
+		case m[2] >= 0:
+			date := t.Text[m[4]:m[5]]
+			switch t.Text[m[2]:m[3]] {
+			case "📅":
+				t.Due = date
+			case "⏳":
+				t.Scheduled = date
+			case "🛫":
+				t.Start = date
+			case "➕":
+				t.Created = date
+			case "✅":
+				t.DoneDate = date
+			case "❌":
+				t.Cancelled = date
+			}
+		case m[6] >= 0:
+			t.Recurrence = strings.TrimSpace(t.Text[m[6]:m[7]])
+		case m[8] >= 0:
+			t.Priority = taskPriorities[t.Text[m[8]:m[9]]]
+		}
+	}
+	desc.WriteString(t.Text[last:])
+	t.Description = strings.Join(strings.Fields(desc.String()), " ")
+}
+
+// indentWidth returns the width of leading whitespace, counting a tab as
+// four spaces.
+func indentWidth(indent string) int {
+	return len(indent) + 3*strings.Count(indent, "\t")
+}
+
+// ParseTasks extracts all checkbox items from text, skipping frontmatter
+// and inert zones (code, comments, math). Each task records its nesting
+// level, the line of its parent task and the nearest heading above it.
 func ParseTasks(text string) []Task {
 	lines := strings.Split(text, "\n")
+	masked := strings.Split(MaskInertContent(text), "\n")
+	_, bodyStart, _ := ExtractFrontmatter(text)
+
+	type open struct{ width, line int }
+	var stack []open // enclosing tasks, innermost last
+	var heading string
 	var tasks []Task
 
-	for i, line := range lines {
-		m := taskPattern.FindStringSubmatch(line)
+	for i := bodyStart; i < len(lines); i++ {
+		ml := masked[i]
+		if headingLevel(ml) > 0 {
+			heading = headingText(ml)
+			stack = stack[:0]
+			continue
+		}
+
+		// Matching runs on the masked line so tasks inside code blocks and
+		// comments are ignored; text is taken from the original line, whose
+		// byte offsets are identical.
+		m := taskPattern.FindStringSubmatchIndex(ml)
 		if m == nil {
+			if strings.TrimSpace(ml) != "" && !listItemPattern.MatchString(ml) &&
+				ml[0] != ' ' && ml[0] != '\t' {
+				stack = stack[:0] // an unindented paragraph ends the list
+			}
 			continue
 		}
-		tasks = append(tasks, Task{
-			Text: m[2],
-			Done: m[1] == "x" || m[1] == "X",
-			Line: i + 1,
-		})
+
+		width := indentWidth(ml[m[2]:m[3]])
+		for len(stack) > 0 && stack[len(stack)-1].width >= width {
+			stack = stack[:len(stack)-1]
+		}
+
+		symbol := lines[i][m[6]:m[7]]
+		t := Task{
+			Text:    lines[i][m[8]:m[9]],
+			Done:    symbol == "x" || symbol == "X",
+			Line:    i + 1,
+			Indent:  len(stack),
+			Heading: heading,
+		}
+		if len(stack) > 0 {
+			t.Parent = stack[len(stack)-1].line
+		}
+		parseTaskFields(&t, symbol)
+		tasks = append(tasks, t)
+		stack = append(stack, open{width: width, line: i + 1})
 	}
 	return tasks
 }
 
 // Tasks lists tasks (checkboxes) from one note or across the vault.
-// Supports filters via opts.Done and opts.Pending.
+// Supports status, priority and date filters and sorting (see TaskOptions).
 // Supports opts.Path to limit search to a subfolder.
+// Under the block policy a note with tasks that fails its integrity check is
+// an *IntegrityError.
 func (v *Vault) Tasks(opts TaskOptions) ([]Task, error) {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
@@ -68,13 +223,19 @@ func (v *Vault) Tasks(opts TaskOptions) ([]Task, error) {
 
 		relPath, _ := filepath.Rel(v.dir, path)
 		tasks := ParseTasks(string(data))
-		tasks = filterTasks(tasks, opts.Done, opts.Pending)
 
+		status := v.registry.verify(v.dir, path, data)
+		if len(tasks) > 0 {
+			if err := v.registry.blocked(v.dir, path, status, "read"); err != nil {
+				return nil, err
+			}
+		}
 		for i := range tasks {
 			tasks[i].File = relPath
+			tasks[i].Integrity = status
 		}
 
-		return tasks, nil
+		return queryTasks(tasks, opts, vaultNow())
 	}
 
 	// Vault-wide mode
@@ -113,8 +274,15 @@ func (v *Vault) Tasks(opts TaskOptions) ([]Task, error) {
 		relPath, _ := filepath.Rel(v.dir, path)
 		tasks := ParseTasks(string(data))
 
+		status := v.registry.verify(v.dir, path, data)
+		if len(tasks) > 0 {
+			if err := v.registry.blocked(v.dir, path, status, "read"); err != nil {
+				return err
+			}
+		}
 		for i := range tasks {
 			tasks[i].File = relPath
+			tasks[i].Integrity = status
 		}
 
 		allTasks = append(allTasks, tasks...)
@@ -125,8 +293,7 @@ func (v *Vault) Tasks(opts TaskOptions) ([]Task, error) {
 		return nil, err
 	}
 
-	allTasks = filterTasks(allTasks, opts.Done, opts.Pending)
-	return allTasks, nil
+	return queryTasks(allTasks, opts, vaultNow())
 }
 
 // filterTasks applies done/pending filters.
@@ -140,9 +307,533 @@ func filterTasks(tasks []Task, done, pending bool) []Task {
 		if done && t.Done {
 			result = append(result, t)
 		}
-		if pending && !t.Done {
+		if pending && !t.Done && t.Status != "cancelled" {
 			result = append(result, t)
 		}
 	}
 	return result
 }
+
+// parseDateRange parses a date filter: a single date (that day),
+// "from..to", "from.." or "..to". Each end may be any ResolveDate
+// expression ("today..+7d"). Ends are returned as YYYY-MM-DD; open ends
+// are empty.
+func parseDateRange(s string, now time.Time) (from, to string, err error) {
+	ends := []string{s, s}
+	if i := strings.Index(s, ".."); i >= 0 {
+		ends = []string{s[:i], s[i+2:]}
+	}
+	for i, e := range ends {
+		if strings.TrimSpace(e) == "" {
+			ends[i] = ""
+			continue
+		}
+		d, err := ResolveDate(e, now)
+		if err != nil {
+			return "", "", fmt.Errorf("%w in %q", err, s)
+		}
+		ends[i] = d.Format("2006-01-02")
+	}
+	return ends[0], ends[1], nil
+}
+
+// inDateRange reports whether date (YYYY-MM-DD) lies within [from, to].
+// Tasks without the date never match.
+func inDateRange(date, from, to string) bool {
+	return date != "" && (from == "" || date >= from) && (to == "" || date <= to)
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryTasks applies the filters and sort order in opts. now supplies
+// "today" for the overdue filter.
+func queryTasks(tasks []Task, opts TaskOptions, now time.Time) ([]Task, error) {
+	tasks = filterTasks(tasks, opts.Done, opts.Pending)
+
+	var dueFrom, dueTo, schedFrom, schedTo string
+	var err error
+	if opts.Due != "" {
+		if dueFrom, dueTo, err = parseDateRange(opts.Due, now); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Scheduled != "" {
+		if schedFrom, schedTo, err = parseDateRange(opts.Scheduled, now); err != nil {
+			return nil, err
+		}
+	}
+	today := now.Format("2006-01-02")
+
+	section := strings.ToLower(strings.TrimSpace(strings.TrimLeft(opts.Section, "#")))
+
+	var result []Task
+	for _, t := range tasks {
+		if section != "" && strings.ToLower(t.Heading) != section {
+			continue
+		}
+		if len(opts.Status) > 0 && !containsFold(opts.Status, t.Status) && !containsFold(opts.Status, t.Symbol) {
+			continue
+		}
+		if len(opts.Priority) > 0 {
+			priority := t.Priority
+			if priority == "" {
+				priority = "none"
+			}
+			if !containsFold(opts.Priority, priority) {
+				continue
+			}
+		}
+		if opts.Due != "" && !inDateRange(t.Due, dueFrom, dueTo) {
+			continue
+		}
+		if opts.Scheduled != "" && !inDateRange(t.Scheduled, schedFrom, schedTo) {
+			continue
+		}
+		if opts.Overdue && (t.Due == "" || t.Due >= today || t.Status == "done" || t.Status == "cancelled") {
+			continue
+		}
+		result = append(result, t)
+	}
+
+	if err := sortTasks(result, opts.Sort); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sortTasks orders tasks by the given key, keeping file order for ties.
+// Tasks without the sort date come last.
+func sortTasks(tasks []Task, key string) error {
+	var date func(Task) string
+	switch key {
+	case "":
+		return nil
+	case "due":
+		date = func(t Task) string { return t.Due }
+	case "scheduled":
+		date = func(t Task) string { return t.Scheduled }
+	case "start":
+		date = func(t Task) string { return t.Start }
+	case "priority":
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return taskPriorityRank[tasks[i].Priority] < taskPriorityRank[tasks[j].Priority]
+		})
+		return nil
+	case "status":
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return taskStatusRank[tasks[i].Status] < taskStatusRank[tasks[j].Status]
+		})
+		return nil
+	default:
+		return fmt.Errorf("unknown sort %q (use due, scheduled, start, priority or status)", key)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := date(tasks[i]), date(tasks[j])
+		if a == "" || b == "" {
+			return a != "" && b == ""
+		}
+		return a < b
+	})
+	return nil
+}
+
+// TaskRef addresses a task within a note: by 1-based line number, or by a
+// case-insensitive substring of its text that must match exactly one task.
+type TaskRef struct {
+	Line  int
+	Match string
+}
+
+// TaskUpdate holds task fields to change. Empty fields are left alone and
+// "none" clears a field. Dates are anything ResolveDate accepts
+// ("2025-07-01", "tomorrow", "+1w"); priorities are highest, high, medium,
+// low or lowest.
+type TaskUpdate struct {
+	Due       string
+	Scheduled string
+	Start     string
+	Priority  string
+}
+
+// TaskEditResult describes a task line written by a task command.
+type TaskEditResult struct {
+	File string `json:"file"`
+	Line int    `json:"line"`           // 1-based line of the edited task
+	Text string `json:"text"`           // the task line as written
+	Next string `json:"next,omitempty"` // next occurrence of a recurring task, inserted above
+}
+
+// recurrencePattern matches the supported Tasks recurrence rules, e.g.
+// "every day", "every 2 weeks", "every month when done".
+var recurrencePattern = regexp.MustCompile(`^every (?:(\d+) )?(day|week|month|year)s?( when done)?$`)
+
+// taskFieldSignifier returns the emoji of a taskFieldPattern match, or
+// "priority" for priority markers.
+func taskFieldSignifier(text string, m []int) string {
+	switch {
+	case m[2] >= 0:
+		return text[m[2]:m[3]]
+	case m[6] >= 0:
+		return "🔁"
+	}
+	return "priority"
+}
+
+// setTaskField sets a Tasks metadata field in task text, replacing an
+// existing value in place or appending it. sig is the field emoji, or
+// "priority" with a priority emoji as value. An empty value removes the
+// field.
+func setTaskField(text, sig, value string) string {
+	matches := taskFieldPattern.FindAllStringSubmatchIndex(text, -1)
+	if value == "" {
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			if taskFieldSignifier(text, m) != sig {
+				continue
+			}
+			b.WriteString(strings.TrimRight(text[last:m[0]], " "))
+			if rest := text[m[1]:]; rest != "" && !strings.HasPrefix(rest, " ") {
+				b.WriteString(" ")
+			}
+			last = m[1]
+		}
+		b.WriteString(text[last:])
+		return strings.TrimRight(b.String(), " ")
+	}
+
+	replacement := sig + " " + value
+	if sig == "priority" {
+		replacement = value
+	}
+	for _, m := range matches {
+		if taskFieldSignifier(text, m) == sig {
+			if strings.HasSuffix(text[m[0]:m[1]], " ") {
+				replacement += " " // recurrence rules swallow the following space
+			}
+			return text[:m[0]] + replacement + text[m[1]:]
+		}
+	}
+	return strings.TrimRight(text, " ") + " " + replacement
+}
+
+// applyTaskUpdate applies the set fields of upd to task text.
+func applyTaskUpdate(text string, upd TaskUpdate) (string, error) {
+	for _, f := range []struct{ sig, value string }{
+		{"📅", upd.Due}, {"⏳", upd.Scheduled}, {"🛫", upd.Start},
+	} {
+		switch f.value {
+		case "":
+			continue
+		case "none":
+			text = setTaskField(text, f.sig, "")
+		default:
+			d, err := ResolveDate(f.value, vaultNow())
+			if err != nil {
+				return "", err
+			}
+			text = setTaskField(text, f.sig, d.Format("2006-01-02"))
+		}
+	}
+
+	switch upd.Priority {
+	case "":
+	case "none":
+		text = setTaskField(text, "priority", "")
+	default:
+		var emoji string
+		for e, name := range taskPriorities {
+			if strings.EqualFold(name, upd.Priority) {
+				emoji = e
+			}
+		}
+		if emoji == "" {
+			return "", fmt.Errorf("unknown priority %q (use highest, high, medium, low, lowest or none)", upd.Priority)
+		}
+		text = setTaskField(text, "priority", emoji)
+	}
+	return text, nil
+}
+
+// addMonthsClamped adds months to t, clamping the day to the length of the
+// target month like the Tasks plugin: Jan 31 plus one month is Feb 28 (or
+// 29), not Mar 3 as with time.AddDate.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+// nextRecurrence returns the text of the next occurrence of a recurring
+// task: the done and cancelled dates are dropped and the due, scheduled and
+// start dates move forward by the rule's interval, measured from the
+// earliest-set of due, scheduled or start (or from today with "when done").
+func nextRecurrence(t Task, today time.Time) (string, error) {
+	m := recurrencePattern.FindStringSubmatch(strings.ToLower(t.Recurrence))
+	if m == nil {
+		return "", fmt.Errorf("unsupported recurrence %q (use \"every [N] day|week|month|year [when done]\")", t.Recurrence)
+	}
+	n := 1
+	if m[1] != "" {
+		n, _ = strconv.Atoi(m[1])
+	}
+
+	next := setTaskField(setTaskField(t.Text, "✅", ""), "❌", "")
+
+	ref := t.Due
+	if ref == "" {
+		ref = t.Scheduled
+	}
+	if ref == "" {
+		ref = t.Start
+	}
+	if ref == "" {
+		return next, nil
+	}
+	refDate, err := time.Parse("2006-01-02", ref)
+	if err != nil {
+		return "", err
+	}
+
+	base := refDate
+	if m[3] != "" {
+		base, _ = time.Parse("2006-01-02", today.Format("2006-01-02"))
+	}
+	switch m[2] {
+	case "day":
+		base = base.AddDate(0, 0, n)
+	case "week":
+		base = base.AddDate(0, 0, 7*n)
+	case "month":
+		base = addMonthsClamped(base, n)
+	case "year":
+		base = addMonthsClamped(base, 12*n)
+	}
+	shift := base.Sub(refDate)
+
+	for _, f := range []struct{ sig, date string }{
+		{"📅", t.Due}, {"⏳", t.Scheduled}, {"🛫", t.Start},
+	} {
+		if d, err := time.Parse("2006-01-02", f.date); err == nil {
+			next = setTaskField(next, f.sig, d.Add(shift).Format("2006-01-02"))
+		}
+	}
+	return next, nil
+}
+
+// findTask locates the task ref points to in a note's text.
+func findTask(text string, ref TaskRef) (Task, error) {
+	tasks := ParseTasks(text)
+	switch {
+	case ref.Line > 0 && ref.Match != "":
+		return Task{}, fmt.Errorf("address a task by line or by match, not both")
+	case ref.Line > 0:
+		for _, t := range tasks {
+			if t.Line == ref.Line {
+				return t, nil
+			}
+		}
+		return Task{}, fmt.Errorf("no task on line %d", ref.Line)
+	case ref.Match != "":
+		var found []Task
+		lower := strings.ToLower(ref.Match)
+		for _, t := range tasks {
+			if strings.Contains(strings.ToLower(t.Text), lower) {
+				found = append(found, t)
+			}
+		}
+		if len(found) == 0 {
+			return Task{}, fmt.Errorf("no task matching %q", ref.Match)
+		}
+		if len(found) > 1 {
+			nums := make([]string, len(found))
+			for i, t := range found {
+				nums[i] = strconv.Itoa(t.Line)
+			}
+			return Task{}, fmt.Errorf("task match %q is ambiguous: found %d matches at lines %s",
+				ref.Match, len(found), strings.Join(nums, ", "))
+		}
+		return found[0], nil
+	}
+	return Task{}, fmt.Errorf("a task line or match is required")
+}
+
+// writeTaskLines writes a note's lines back after a task edit, refreshing
+// timestamps when enabled. It returns how many lines were added above the
+// body (when timestamps create frontmatter) so reported line numbers stay
+// accurate.
+func (v *Vault) writeTaskLines(path string, lines []string, timestamps bool) (int, error) {
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return 0, err
+	}
+	output := strings.Join(lines, "\n")
+	shift := 0
+	if timestampsEnabled(timestamps) {
+		output = ensureTimestamps(output, false, time.Now())
+		shift = strings.Count(output, "\n") - (len(lines) - 1)
+	}
+
+	outputBytes := []byte(output)
+	if err := os.WriteFile(path, outputBytes, 0644); err != nil {
+		return 0, err
+	}
+	v.registry.register(v.dir, path, outputBytes)
+	return shift, nil
+}
+
+// editTask rewrites the task ref points to. edit receives the task and its
+// line prefix (indentation and list marker) and returns the new task line
+// and, optionally, a line to insert above it.
+func (v *Vault) editTask(title string, ref TaskRef, timestamps bool, edit func(t Task, prefix string) (string, string, error)) (TaskEditResult, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	path, err := resolveNote(v.dir, title)
+	if err != nil {
+		return TaskEditResult{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TaskEditResult{}, err
+	}
+
+	text := string(data)
+	t, err := findTask(text, ref)
+	if err != nil {
+		return TaskEditResult{}, fmt.Errorf("%s in %q", err, title)
+	}
+
+	lines := strings.Split(text, "\n")
+	idx := t.Line - 1
+	m := taskPattern.FindStringSubmatch(lines[idx])
+	line, next, err := edit(t, m[1]+m[2]+" ")
+	if err != nil {
+		return TaskEditResult{}, err
+	}
+
+	relPath, _ := filepath.Rel(v.dir, path)
+	result := TaskEditResult{File: relPath, Line: t.Line, Text: line, Next: next}
+	lines[idx] = line
+	if next != "" {
+		lines = append(lines[:idx], append([]string{next}, lines[idx:]...)...)
+		result.Line++
+	}
+
+	shift, err := v.writeTaskLines(path, lines, timestamps)
+	if err != nil {
+		return TaskEditResult{}, err
+	}
+	result.Line += shift
+	return result, nil
+}
+
+// TaskDone marks a task done and appends its completion date (✅). For a
+// recurring task (🔁) the next occurrence is inserted above it, as the
+// Tasks plugin does.
+func (v *Vault) TaskDone(title string, ref TaskRef, timestamps bool) (TaskEditResult, error) {
+	return v.editTask(title, ref, timestamps, func(t Task, prefix string) (string, string, error) {
+		if t.Done {
+			return "", "", fmt.Errorf("task on line %d is already done", t.Line)
+		}
+		now := vaultNow()
+		line := prefix + "[x] " + setTaskField(t.Text, "✅", now.Format("2006-01-02"))
+
+		var next string
+		if t.Recurrence != "" {
+			text, err := nextRecurrence(t, now)
+			if err != nil {
+				return "", "", err
+			}
+			next = prefix + "[ ] " + text
+		}
+		return line, next, nil
+	})
+}
+
+// TaskUndo reopens a completed task and removes its completion date.
+func (v *Vault) TaskUndo(title string, ref TaskRef, timestamps bool) (TaskEditResult, error) {
+	return v.editTask(title, ref, timestamps, func(t Task, prefix string) (string, string, error) {
+		if !t.Done {
+			return "", "", fmt.Errorf("task on line %d is not done", t.Line)
+		}
+		return prefix + "[ ] " + setTaskField(t.Text, "✅", ""), "", nil
+	})
+}
+
+// TaskSet changes a task's due, scheduled or start date or its priority.
+func (v *Vault) TaskSet(title string, ref TaskRef, upd TaskUpdate, timestamps bool) (TaskEditResult, error) {
+	if upd == (TaskUpdate{}) {
+		return TaskEditResult{}, fmt.Errorf("nothing to set: give due, scheduled, start or priority")
+	}
+	return v.editTask(title, ref, timestamps, func(t Task, prefix string) (string, string, error) {
+		text, err := applyTaskUpdate(t.Text, upd)
+		if err != nil {
+			return "", "", err
+		}
+		return prefix + "[" + t.Symbol + "] " + text, "", nil
+	})
+}
+
+// TaskAdd appends a new open task to a note, at the end of the given
+// heading's section or of the note when heading is empty.
+func (v *Vault) TaskAdd(title, heading, text string, upd TaskUpdate, timestamps bool) (TaskEditResult, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return TaskEditResult{}, fmt.Errorf("task text is required")
+	}
+	text, err := applyTaskUpdate(text, upd)
+	if err != nil {
+		return TaskEditResult{}, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	path, err := resolveNote(v.dir, title)
+	if err != nil {
+		return TaskEditResult{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TaskEditResult{}, err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	_, lo, _ := ExtractFrontmatter(string(data))
+	hi := len(lines)
+	if heading != "" {
+		bounds, err := findSection(lines, heading)
+		if err != nil {
+			return TaskEditResult{}, fmt.Errorf("%s in %q", err, title)
+		}
+		lo, hi = bounds.ContentStart, bounds.ContentEnd
+	}
+
+	// Insert after the last non-blank line of the target range.
+	insert := lo
+	for i := hi - 1; i >= lo; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			insert = i + 1
+			break
+		}
+	}
+
+	line := "- [ ] " + text
+	lines = append(lines[:insert], append([]string{line}, lines[insert:]...)...)
+
+	shift, err := v.writeTaskLines(path, lines, timestamps)
+	if err != nil {
+		return TaskEditResult{}, err
+	}
+	relPath, _ := filepath.Rel(v.dir, path)
+	return TaskEditResult{File: relPath, Line: insert + 1 + shift, Text: line}, nil
+}
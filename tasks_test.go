@@ -3,7 +3,9 @@ package vlt
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseTasks(t *testing.T) {
@@ -200,3 +202,298 @@ func TestTasks_PathFilter(t *testing.T) {
 		t.Errorf("task text = %q, want %q", tasks[0].Text, "Project task")
 	}
 }
+
+func TestParseTasks_TasksPluginFields(t *testing.T) {
+	text := "- [ ] Ship release ⏫ 🔁 every week 📅 2025-06-30 ⏳ 2025-06-27 #work\n" +
+		"- [/] Draft spec 🛫 2025-06-01 🔽\n" +
+		"- [-] Old idea ❌ 2025-05-01\n" +
+		"- [x] Pay rent ✅ 2025-06-02 📅 2025-06-01\n" +
+		"- [?] Custom status\n"
+	tasks := ParseTasks(text)
+	if len(tasks) != 5 {
+		t.Fatalf("got %d tasks, want 5", len(tasks))
+	}
+
+	ship := tasks[0]
+	if ship.Status != "todo" || ship.Priority != "high" || ship.Recurrence != "every week" ||
+		ship.Due != "2025-06-30" || ship.Scheduled != "2025-06-27" {
+		t.Errorf("ship = %+v", ship)
+	}
+	if ship.Description != "Ship release #work" {
+		t.Errorf("description = %q", ship.Description)
+	}
+
+	if tasks[1].Status != "in-progress" || tasks[1].Start != "2025-06-01" || tasks[1].Priority != "low" {
+		t.Errorf("draft = %+v", tasks[1])
+	}
+	if tasks[2].Status != "cancelled" || tasks[2].Done || tasks[2].Cancelled != "2025-05-01" {
+		t.Errorf("cancelled = %+v", tasks[2])
+	}
+	if !tasks[3].Done || tasks[3].DoneDate != "2025-06-02" {
+		t.Errorf("done = %+v", tasks[3])
+	}
+	if tasks[4].Status != "todo" || tasks[4].Symbol != "?" {
+		t.Errorf("custom = %+v", tasks[4])
+	}
+}
+
+func TestQueryTasks(t *testing.T) {
+	tasks := ParseTasks("- [ ] A 📅 2025-06-10 🔼\n" +
+		"- [ ] B 📅 2025-06-01 ⏫\n" +
+		"- [/] C ⏳ 2025-06-05\n" +
+		"- [x] D 📅 2025-05-01\n" +
+		"- [-] E 📅 2025-05-02\n" +
+		"- [ ] F\n")
+	now := time.Date(2025, 6, 8, 12, 0, 0, 0, time.UTC)
+
+	descs := func(ts []Task) string {
+		var s string
+		for _, t := range ts {
+			s += t.Description
+		}
+		return s
+	}
+
+	tests := []struct {
+		name string
+		opts TaskOptions
+		want string
+	}{
+		{"pending excludes cancelled", TaskOptions{Pending: true}, "ABCF"},
+		{"overdue", TaskOptions{Overdue: true}, "B"},
+		{"due range", TaskOptions{Due: "2025-05-01..2025-06-05"}, "BDE"},
+		{"due open start", TaskOptions{Due: "..2025-05-01"}, "D"},
+		{"due exact", TaskOptions{Due: "2025-06-10"}, "A"},
+		{"due relative", TaskOptions{Due: "-7d..today"}, "B"},
+		{"due natural", TaskOptions{Due: "..next tuesday"}, "ABDE"},
+		{"scheduled", TaskOptions{Scheduled: "2025-06-01.."}, "C"},
+		{"status name and symbol", TaskOptions{Status: []string{"in-progress", "x"}}, "CD"},
+		{"priority", TaskOptions{Priority: []string{"high", "none"}}, "BCDEF"},
+		{"sort due", TaskOptions{Sort: "due"}, "DEBACF"},
+		{"sort priority", TaskOptions{Sort: "priority"}, "BACDEF"},
+		{"sort status", TaskOptions{Sort: "status"}, "CABFDE"},
+	}
+	for _, tt := range tests {
+		got, err := queryTasks(tasks, tt.opts, now)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if descs(got) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, descs(got), tt.want)
+		}
+	}
+
+	if _, err := queryTasks(tasks, TaskOptions{Due: "June"}, now); err == nil {
+		t.Error("expected error for invalid due filter")
+	}
+	if _, err := queryTasks(tasks, TaskOptions{Sort: "size"}, now); err == nil {
+		t.Error("expected error for unknown sort")
+	}
+}
+
+func TestSetTaskField(t *testing.T) {
+	tests := []struct {
+		text, sig, value, want string
+	}{
+		{"Pay rent", "📅", "2025-07-01", "Pay rent 📅 2025-07-01"},
+		{"Pay rent 📅 2025-06-01 #home", "📅", "2025-07-01", "Pay rent 📅 2025-07-01 #home"},
+		{"Pay rent 📅 2025-06-01 #home", "📅", "", "Pay rent #home"},
+		{"Pay rent 🔁 every month 📅 2025-06-01", "🔁", "", "Pay rent 📅 2025-06-01"},
+		{"Pay rent 🔼", "priority", "⏫", "Pay rent ⏫"},
+		{"Pay rent ⏫ ✅ 2025-06-02", "✅", "", "Pay rent ⏫"},
+	}
+	for _, tt := range tests {
+		if got := setTaskField(tt.text, tt.sig, tt.value); got != tt.want {
+			t.Errorf("setTaskField(%q, %s, %q) = %q, want %q", tt.text, tt.sig, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNextRecurrence(t *testing.T) {
+	today := time.Date(2025, 6, 20, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		line, want string
+	}{
+		{"- [ ] Review 🔁 every week 📅 2025-06-16", "Review 🔁 every week 📅 2025-06-23"},
+		{"- [ ] Rent 🔁 every month ⏳ 2025-06-25 📅 2025-06-30", "Rent 🔁 every month ⏳ 2025-07-25 📅 2025-07-30"},
+		{"- [ ] Water 🔁 every 3 days when done 📅 2025-06-01", "Water 🔁 every 3 days when done 📅 2025-06-23"},
+		{"- [ ] Taxes 🔁 every year 🛫 2025-01-15", "Taxes 🔁 every year 🛫 2026-01-15"},
+		// Month and year steps clamp to the end of a shorter target month.
+		{"- [ ] Close books 🔁 every month 📅 2026-01-31", "Close books 🔁 every month 📅 2026-02-28"},
+		{"- [ ] Close books 🔁 every month 📅 2026-03-31", "Close books 🔁 every month 📅 2026-04-30"},
+		{"- [ ] Leap 🔁 every year 📅 2028-02-29", "Leap 🔁 every year 📅 2029-02-28"},
+		{"- [ ] Leap 🔁 every 4 years 📅 2028-02-29", "Leap 🔁 every 4 years 📅 2032-02-29"},
+	}
+	for _, tt := range tests {
+		got, err := nextRecurrence(ParseTasks(tt.line)[0], today)
+		if err != nil {
+			t.Errorf("%s: %v", tt.line, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("nextRecurrence(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := nextRecurrence(ParseTasks("- [ ] X 🔁 every second tuesday")[0], today); err == nil {
+		t.Error("expected error for unsupported rule")
+	}
+}
+
+func TestTaskDoneAndUndo(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	path := filepath.Join(vaultDir, "Plan.md")
+	os.WriteFile(path, []byte("# Plan\n\n- [ ] Write spec\n  - [ ] Standup 🔁 every day 📅 2025-06-16\n"), 0644)
+	today := time.Now().Format("2006-01-02")
+
+	res, err := v.TaskDone("Plan", TaskRef{Match: "spec"}, false)
+	if err != nil {
+		t.Fatalf("TaskDone: %v", err)
+	}
+	if res.Line != 3 || res.Text != "- [x] Write spec ✅ "+today || res.Next != "" {
+		t.Errorf("done result = %+v", res)
+	}
+
+	res, err = v.TaskDone("Plan", TaskRef{Line: 4}, false)
+	if err != nil {
+		t.Fatalf("TaskDone recurring: %v", err)
+	}
+	if res.Line != 5 || res.Next != "  - [ ] Standup 🔁 every day 📅 2025-06-17" {
+		t.Errorf("recurring result = %+v", res)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "# Plan\n\n- [x] Write spec ✅ " + today + "\n" +
+		"  - [ ] Standup 🔁 every day 📅 2025-06-17\n" +
+		"  - [x] Standup 🔁 every day 📅 2025-06-16 ✅ " + today + "\n"
+	if string(data) != want {
+		t.Errorf("content:\n%s\nwant:\n%s", data, want)
+	}
+
+	if _, err := v.TaskDone("Plan", TaskRef{Line: 3}, false); err == nil {
+		t.Error("expected error completing a done task")
+	}
+	if _, err := v.TaskDone("Plan", TaskRef{Match: "standup"}, false); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected ambiguous match error, got %v", err)
+	}
+
+	res, err = v.TaskUndo("Plan", TaskRef{Line: 3}, true)
+	if err != nil {
+		t.Fatalf("TaskUndo: %v", err)
+	}
+	// Timestamps add frontmatter above the task; the reported line follows it.
+	data, _ = os.ReadFile(path)
+	lines := strings.Split(string(data), "\n")
+	if res.Text != "- [ ] Write spec" || lines[res.Line-1] != res.Text {
+		t.Errorf("undo result = %+v, line %d is %q", res, res.Line, lines[res.Line-1])
+	}
+	if !strings.Contains(string(data), "updated_at:") {
+		t.Error("timestamps not refreshed")
+	}
+}
+
+func TestTaskSetAndAdd(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	path := filepath.Join(vaultDir, "Plan.md")
+	os.WriteFile(path, []byte("# Plan\n\n## Now\n- [/] Draft 📅 2025-06-01\n\n## Later\n"), 0644)
+
+	res, err := v.TaskSet("Plan", TaskRef{Match: "draft"}, TaskUpdate{Due: "2025-06-10", Priority: "high"}, false)
+	if err != nil {
+		t.Fatalf("TaskSet: %v", err)
+	}
+	if res.Text != "- [/] Draft 📅 2025-06-10 ⏫" {
+		t.Errorf("set = %q", res.Text)
+	}
+	if _, err := v.TaskSet("Plan", TaskRef{Line: 4}, TaskUpdate{Due: "none", Priority: "none"}, false); err != nil {
+		t.Fatalf("TaskSet clear: %v", err)
+	}
+	if _, err := v.TaskSet("Plan", TaskRef{Line: 4}, TaskUpdate{Priority: "urgent"}, false); err == nil {
+		t.Error("expected error for unknown priority")
+	}
+
+	res, err = v.TaskAdd("Plan", "Now", "Review", TaskUpdate{Due: "2025-06-12"}, false)
+	if err != nil {
+		t.Fatalf("TaskAdd: %v", err)
+	}
+	if res.Line != 5 {
+		t.Errorf("added at line %d, want 5", res.Line)
+	}
+	if _, err := v.TaskAdd("Plan", "", "Someday", TaskUpdate{}, false); err != nil {
+		t.Fatalf("TaskAdd end: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "# Plan\n\n## Now\n- [/] Draft\n- [ ] Review 📅 2025-06-12\n\n## Later\n- [ ] Someday\n"
+	if string(data) != want {
+		t.Errorf("content:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestParseTasks_MarkersAndInertZones(t *testing.T) {
+	text := "---\nchecklist: \"- [ ] not a task\"\n---\n" +
+		"* [ ] Star\n" +
+		"+ [ ] Plus\n" +
+		"1. [ ] Numbered\n" +
+		"2) [x] Paren\n" +
+		"```\n- [ ] In code\n```\n" +
+		"%% - [ ] In comment %%\n" +
+		"- [ ] Run `go test` first\n"
+	tasks := ParseTasks(text)
+
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Text)
+	}
+	want := "Star|Plus|Numbered|Paren|Run `go test` first"
+	if strings.Join(got, "|") != want {
+		t.Errorf("tasks = %q, want %q", strings.Join(got, "|"), want)
+	}
+	if tasks[3].Line != 7 || !tasks[3].Done {
+		t.Errorf("paren task = %+v", tasks[3])
+	}
+}
+
+func TestParseTasks_Hierarchy(t *testing.T) {
+	text := "# Project\n" +
+		"- [ ] Parent\n" +
+		"  - [ ] Child\n" +
+		"    - note\n" +
+		"      - [ ] Grandchild\n" +
+		"\t- [ ] Tab child\n" +
+		"- [ ] Sibling\n" +
+		"Paragraph.\n" +
+		"  - [ ] After paragraph\n" +
+		"## Sub\n" +
+		"- [ ] In sub\n"
+	tasks := ParseTasks(text)
+	want := []struct {
+		line, indent, parent int
+		heading              string
+	}{
+		{2, 0, 0, "Project"},
+		{3, 1, 2, "Project"},
+		{5, 2, 3, "Project"},
+		{6, 2, 3, "Project"}, // a tab is four columns, deeper than Child
+		{7, 0, 0, "Project"},
+		{9, 0, 0, "Project"},
+		{11, 0, 0, "Sub"},
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("got %d tasks, want %d", len(tasks), len(want))
+	}
+	for i, w := range want {
+		got := tasks[i]
+		if got.Line != w.line || got.Indent != w.indent || got.Parent != w.parent || got.Heading != w.heading {
+			t.Errorf("task %d = line %d indent %d parent %d heading %q, want %+v",
+				i, got.Line, got.Indent, got.Parent, got.Heading, w)
+		}
+	}
+
+	section, err := queryTasks(tasks, TaskOptions{Section: "## sub"}, time.Now())
+	if err != nil || len(section) != 1 || section[0].Line != 11 {
+		t.Errorf("section filter = %+v, %v", section, err)
+	}
+}
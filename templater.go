@@ -0,0 +1,520 @@
+package vlt
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templateContext describes the note a template is rendered for.
+type templateContext struct {
+	VaultDir string    // vault root, for tp.file.path()
+	Title    string    // note name ({{title}}, tp.file.title)
+	RelPath  string    // vault-relative path of the note being created
+	Date     time.Time // {{date}} and {{time}}; the period date for periodic notes
+	Now      time.Time // tp.date.* and tp.file.creation_date
+
+	Vars map[string]string // user variables: {{name}}, tp.system.prompt("name")
+
+	missing map[string]bool // variables asked for but not supplied
+}
+
+// placeholderPattern matches any {{...}} placeholder, built-in or user.
+var placeholderPattern = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// renderTemplate evaluates Templater tags, user variables and {{title}},
+// {{date}} and {{time}} in a template in a single pass: placeholders are
+// expanded only in the template's own text, never inside an inserted
+// variable value or Templater output. Variables declared in
+// the template's frontmatter supply defaults; declared variables without a
+// value and unanswered tp.system.prompt calls are reported together as
+// missing. Undeclared {{name}} placeholders without a value are left as-is,
+// so templates may contain literal braces. The built-in names title, date
+// and time cannot be used as variables.
+func renderTemplate(content string, ctx templateContext) (string, error) {
+	defaults, declared, content, err := templateVariables(content)
+	if err != nil {
+		return "", err
+	}
+	reserved := func(name string) error {
+		return fmt.Errorf("template variable %q is reserved for the built-in {{%s}}", name, name)
+	}
+	for _, name := range declared {
+		if builtinTemplateVars[name] {
+			return "", reserved(name)
+		}
+	}
+	for name := range ctx.Vars {
+		if builtinTemplateVars[name] {
+			return "", reserved(name)
+		}
+	}
+	vars := make(map[string]string, len(defaults)+len(ctx.Vars))
+	for name, value := range defaults {
+		vars[name] = value
+	}
+	for name, value := range ctx.Vars {
+		vars[name] = value
+	}
+	ctx.Vars = vars
+	ctx.missing = make(map[string]bool)
+	for _, name := range declared {
+		if _, ok := vars[name]; !ok {
+			ctx.missing[name] = true
+		}
+	}
+
+	out, err := renderTemplater(content, ctx, func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+			if userVarPattern.MatchString(match) {
+				if value, ok := vars[match[2:len(match)-2]]; ok {
+					return value
+				}
+			}
+			return substituteTemplateVars(match, ctx.Title, ctx.Date)
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(ctx.missing) > 0 {
+		names := make([]string, 0, len(ctx.missing))
+		for name := range ctx.missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("missing template variables: %s", strings.Join(names, ", "))
+	}
+	return out, nil
+}
+
+// renderTemplater evaluates the sandboxed subset of Templater syntax that
+// vlt supports. Output tags (<% expr %>) may use string, number and boolean
+// literals joined with +, and:
+//
+//	tp.date.now(format, offset, reference, reference_format)
+//	tp.date.today(format), tp.date.tomorrow(format), tp.date.yesterday(format)
+//	tp.date.weekday(format, weekday, reference, reference_format)
+//	tp.file.title, tp.file.folder(relative), tp.file.path(relative)
+//	tp.file.creation_date(format), tp.file.last_modified_date(format)
+//	tp.file.cursor(n)
+//	tp.frontmatter.key, tp.frontmatter["key"]
+//	tp.system.prompt(name, default), answered from the template variables
+//
+// No JavaScript is run: execution tags (<%* %>) and any other expression
+// are errors. The -%> and <%- markers trim one adjacent newline, _%> and
+// <%_ all adjacent whitespace. tp.frontmatter reads the template's own
+// frontmatter, which becomes the new note's. text, if not nil, rewrites the
+// literal template text between tags; tag output is written as-is.
+func renderTemplater(content string, ctx templateContext, text func(string) string) (string, error) {
+	if text == nil {
+		text = func(s string) string { return s }
+	}
+	if !strings.Contains(content, "<%") {
+		return text(content), nil
+	}
+	yaml, _, _ := ExtractFrontmatter(content)
+
+	var b strings.Builder
+	rest := content
+	for {
+		start := strings.Index(rest, "<%")
+		if start < 0 {
+			b.WriteString(text(rest))
+			break
+		}
+		line := strings.Count(content[:len(content)-len(rest)+start], "\n") + 1
+		end := strings.Index(rest[start:], "%>")
+		if end < 0 {
+			return "", fmt.Errorf("template line %d: unterminated <%% tag", line)
+		}
+		tag := rest[start+2 : start+end]
+		before := rest[:start]
+		rest = rest[start+end+2:]
+
+		switch {
+		case strings.HasPrefix(tag, "-"):
+			before = strings.TrimSuffix(strings.TrimSuffix(before, "\n"), "\r")
+			tag = tag[1:]
+		case strings.HasPrefix(tag, "_"):
+			before = strings.TrimRight(before, " \t\r\n")
+			tag = tag[1:]
+		}
+		switch {
+		case strings.HasSuffix(tag, "-"):
+			rest = strings.TrimPrefix(strings.TrimPrefix(rest, "\r"), "\n")
+			tag = tag[:len(tag)-1]
+		case strings.HasSuffix(tag, "_"):
+			rest = strings.TrimLeft(rest, " \t\r\n")
+			tag = tag[:len(tag)-1]
+		}
+		b.WriteString(text(before))
+
+		if strings.HasPrefix(tag, "*") || strings.HasPrefix(tag, "+") {
+			return "", fmt.Errorf("template line %d: Templater JavaScript (<%%%c) is not supported", line, tag[0])
+		}
+		expr := strings.TrimSpace(tag)
+		value, err := evalTemplater(expr, ctx, yaml)
+		if err != nil {
+			return "", fmt.Errorf("template line %d: %v", line, err)
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+// templaterToken is a lexical token of a Templater expression.
+type templaterToken struct {
+	kind  byte // 'i' identifier, 's' string, 'n' number, or the punctuation character
+	value string
+}
+
+// lexTemplater splits a Templater expression into tokens.
+func lexTemplater(expr string) ([]templaterToken, error) {
+	var tokens []templaterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.IndexByte("().,+[]", c) >= 0:
+			tokens = append(tokens, templaterToken{kind: c})
+			i++
+		case c == '"' || c == '\'' || c == '`':
+			var s strings.Builder
+			j := i + 1
+			for ; j < len(expr) && expr[j] != c; j++ {
+				if expr[j] == '\\' && j+1 < len(expr) {
+					j++
+				} else if c == '`' && strings.HasPrefix(expr[j:], "${") {
+					return nil, fmt.Errorf("template literals with ${...} are not supported")
+				}
+				s.WriteByte(expr[j])
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string in %q", expr)
+			}
+			tokens = append(tokens, templaterToken{kind: 's', value: s.String()})
+			i = j + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(expr) && (expr[j] == '.' || (expr[j] >= '0' && expr[j] <= '9')) {
+				j++
+			}
+			tokens = append(tokens, templaterToken{kind: 'n', value: expr[i:j]})
+			i = j
+		case c == '_' || c == '$' || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+			j := i + 1
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '$' || (expr[j]|0x20 >= 'a' && expr[j]|0x20 <= 'z') || (expr[j] >= '0' && expr[j] <= '9')) {
+				j++
+			}
+			tokens = append(tokens, templaterToken{kind: 'i', value: expr[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unsupported Templater expression %q", expr)
+		}
+	}
+	return tokens, nil
+}
+
+// templaterParser evaluates a token stream. Values are string, float64 or
+// bool.
+type templaterParser struct {
+	tokens []templaterToken
+	pos    int
+	ctx    templateContext
+	yaml   string
+	expr   string
+}
+
+// evalTemplater evaluates one output tag's expression to text.
+func evalTemplater(expr string, ctx templateContext, yaml string) (string, error) {
+	if expr == "" {
+		return "", nil
+	}
+	tokens, err := lexTemplater(expr)
+	if err != nil {
+		return "", err
+	}
+	p := &templaterParser{tokens: tokens, ctx: ctx, yaml: yaml, expr: expr}
+	value, err := p.sum()
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.tokens) {
+		return "", p.unsupported()
+	}
+	return templaterString(value), nil
+}
+
+func (p *templaterParser) unsupported() error {
+	return fmt.Errorf("unsupported Templater expression %q", p.expr)
+}
+
+// peek returns the kind of the next token, or 0 at the end.
+func (p *templaterParser) peek() byte {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].kind
+	}
+	return 0
+}
+
+// sum parses term { + term }, adding numbers and concatenating anything
+// else, as JavaScript does.
+func (p *templaterParser) sum() (any, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '+' {
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		ln, lok := left.(float64)
+		rn, rok := right.(float64)
+		if lok && rok {
+			left = ln + rn
+		} else {
+			left = templaterString(left) + templaterString(right)
+		}
+	}
+	return left, nil
+}
+
+// term parses a literal or a tp reference.
+func (p *templaterParser) term() (any, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.unsupported()
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch {
+	case tok.kind == 's':
+		return tok.value, nil
+	case tok.kind == 'n':
+		n, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.unsupported()
+		}
+		return n, nil
+	case tok.kind == 'i' && (tok.value == "true" || tok.value == "false"):
+		return tok.value == "true", nil
+	case tok.kind == 'i' && tok.value == "tp":
+		return p.reference()
+	}
+	return nil, p.unsupported()
+}
+
+// reference parses the rest of a tp.a.b["c"](args) reference and
+// evaluates it.
+func (p *templaterParser) reference() (any, error) {
+	names := []string{"tp"}
+	for {
+		switch p.peek() {
+		case '.':
+			p.pos++
+			if p.peek() != 'i' {
+				return nil, p.unsupported()
+			}
+			names = append(names, p.tokens[p.pos].value)
+			p.pos++
+			continue
+		case '[':
+			p.pos++
+			if p.peek() != 's' || p.pos+1 >= len(p.tokens) || p.tokens[p.pos+1].kind != ']' {
+				return nil, p.unsupported()
+			}
+			names = append(names, p.tokens[p.pos].value)
+			p.pos += 2
+			continue
+		}
+		break
+	}
+
+	var args []any
+	called := p.peek() == '('
+	if called {
+		p.pos++
+		for p.peek() != ')' {
+			arg, err := p.sum()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek() == ',' {
+				p.pos++
+			} else if p.peek() != ')' {
+				return nil, p.unsupported()
+			}
+		}
+		p.pos++
+	}
+	return p.call(names, called, args)
+}
+
+// call evaluates a tp reference.
+func (p *templaterParser) call(names []string, called bool, args []any) (any, error) {
+	ref := strings.Join(names, ".")
+	arg := func(i int) any {
+		if i < len(args) {
+			return args[i]
+		}
+		return nil
+	}
+	format := func(i int, def string) string {
+		if s, ok := arg(i).(string); ok && s != "" {
+			return s
+		}
+		return def
+	}
+
+	if len(names) == 3 && names[1] == "frontmatter" && !called {
+		return strings.Join(FrontmatterGetList(p.yaml, names[2]), ","), nil
+	}
+
+	switch ref {
+	case "tp.file.title":
+		if !called {
+			return p.ctx.Title, nil
+		}
+	case "tp.file.folder":
+		folder := path.Dir(filepath.ToSlash(p.ctx.RelPath))
+		if folder == "." {
+			folder = "/"
+		}
+		if relative, _ := arg(0).(bool); relative || folder == "/" {
+			return folder, nil
+		}
+		return path.Base(folder), nil
+	case "tp.file.path":
+		if relative, _ := arg(0).(bool); relative {
+			return filepath.ToSlash(p.ctx.RelPath), nil
+		}
+		return filepath.Join(p.ctx.VaultDir, p.ctx.RelPath), nil
+	case "tp.file.cursor":
+		return "", nil
+	case "tp.system.prompt":
+		name, _ := arg(0).(string)
+		if !called || name == "" {
+			break
+		}
+		if value, ok := p.ctx.Vars[name]; ok {
+			return value, nil
+		}
+		if def, ok := arg(1).(string); ok {
+			return def, nil
+		}
+		if p.ctx.missing == nil {
+			return nil, fmt.Errorf("no value for tp.system.prompt(%q)", name)
+		}
+		p.ctx.missing[name] = true
+		return "", nil
+	case "tp.file.creation_date", "tp.file.last_modified_date":
+		if called {
+			return FormatMoment(p.ctx.Now, format(0, "YYYY-MM-DD HH:mm")), nil
+		}
+	case "tp.date.today", "tp.date.tomorrow", "tp.date.yesterday":
+		if called {
+			days := map[string]int{"today": 0, "tomorrow": 1, "yesterday": -1}[names[2]]
+			return FormatMoment(p.ctx.Now.AddDate(0, 0, days), format(0, "YYYY-MM-DD")), nil
+		}
+	case "tp.date.now":
+		if called {
+			date, err := p.referenceDate(arg(2), arg(3))
+			if err != nil {
+				return nil, err
+			}
+			if date, err = templaterOffset(date, arg(1)); err != nil {
+				return nil, err
+			}
+			return FormatMoment(date, format(0, "YYYY-MM-DD")), nil
+		}
+	case "tp.date.weekday":
+		if called {
+			date, err := p.referenceDate(arg(2), arg(3))
+			if err != nil {
+				return nil, err
+			}
+			n, ok := arg(1).(float64)
+			if !ok {
+				return nil, fmt.Errorf("tp.date.weekday needs a weekday number")
+			}
+			// Moment's weekday(n) is relative to the locale's week, which
+			// starts on Sunday.
+			date = date.AddDate(0, 0, int(n)-int(date.Weekday()))
+			return FormatMoment(date, format(0, "YYYY-MM-DD")), nil
+		}
+	}
+	return nil, p.unsupported()
+}
+
+// referenceDate returns the date tp.date functions count from: now, or a
+// reference date parsed with an optional Moment.js format.
+func (p *templaterParser) referenceDate(reference, referenceFormat any) (time.Time, error) {
+	ref, _ := reference.(string)
+	if ref == "" {
+		return p.ctx.Now, nil
+	}
+	var date time.Time
+	var err error
+	if refFormat, _ := referenceFormat.(string); refFormat != "" {
+		date, err = ParseMoment(ref, refFormat, p.ctx.Now.Location())
+	} else {
+		date, err = ResolveDate(ref, p.ctx.Now)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("tp.date reference: %v", err)
+	}
+	return date, nil
+}
+
+// isoDurationPattern matches ISO 8601 date durations such as "P1W" or
+// "-P1Y2M".
+var isoDurationPattern = regexp.MustCompile(`^(-)?P(?:(-?\d+)Y)?(?:(-?\d+)M)?(?:(-?\d+)W)?(?:(-?\d+)D)?$`)
+
+// templaterOffset shifts date by a tp.date.now offset: a number of days or
+// an ISO 8601 duration.
+func templaterOffset(date time.Time, offset any) (time.Time, error) {
+	switch o := offset.(type) {
+	case nil:
+		return date, nil
+	case float64:
+		return date.AddDate(0, 0, int(o)), nil
+	case string:
+		m := isoDurationPattern.FindStringSubmatch(o)
+		if m == nil || o == "P" || o == "-P" {
+			return time.Time{}, fmt.Errorf("invalid tp.date offset %q (use a number of days or a duration like P1W)", o)
+		}
+		sign := 1
+		if m[1] == "-" {
+			sign = -1
+		}
+		n := func(s string) int {
+			v, _ := strconv.Atoi(s)
+			return sign * v
+		}
+		return date.AddDate(n(m[2]), n(m[3]), 7*n(m[4])+n(m[5])), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid tp.date offset %v", offset)
+}
+
+// templaterString renders a value as JavaScript would.
+func templaterString(v any) string {
+	switch x := v.(type) {
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case string:
+		return x
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTemplater(t *testing.T) {
+	now := time.Date(2025, 6, 18, 9, 30, 0, 0, time.UTC) // a Wednesday
+	ctx := templateContext{
+		VaultDir: "/vault",
+		Title:    "Team Sync",
+		RelPath:  "meetings/2025/Team Sync.md",
+		Date:     now,
+		Now:      now,
+	}
+	fm := "---\nproject: Apollo\ntags: [meeting, weekly]\ndue date: 2025-07-01\n---\n"
+
+	tests := []struct {
+		name, in, want string
+	}{
+		{"title", `# <% tp.file.title %>`, "# Team Sync"},
+		{"date default", `<% tp.date.now() %>`, "2025-06-18"},
+		{"date offset", `<% tp.date.now("YYYY-MM-DD", 7) %>`, "2025-06-25"},
+		{"date negative offset", `<% tp.date.now("ddd Do", -1) %>`, "Tue 17th"},
+		{"date duration", `<% tp.date.now("YYYY-MM-DD", "P1M") %>`, "2025-07-18"},
+		{"date reference", `<% tp.date.now("YYYY-MM-DD", 1, "2025-W10", "GGGG-[W]WW") %>`, "2025-03-04"},
+		{"tomorrow", `<% tp.date.tomorrow("dddd") %>`, "Thursday"},
+		{"weekday", `<% tp.date.weekday("YYYY-MM-DD", 1) %>`, "2025-06-16"},
+		{"folder", `<% tp.file.folder() %>`, "2025"},
+		{"folder relative", `<% tp.file.folder(true) %>`, "meetings/2025"},
+		{"folder property", `<% tp.file.folder %>`, "2025"},
+		{"path relative", `<% tp.file.path(true) %>`, "meetings/2025/Team Sync.md"},
+		{"cursor", `a<% tp.file.cursor(1) %>b`, "ab"},
+		{"frontmatter", fm + `<% tp.frontmatter.project %>`, fm + "Apollo"},
+		{"frontmatter list", fm + `<% tp.frontmatter.tags %>`, fm + "meeting,weekly"},
+		{"frontmatter bracket", fm + `<% tp.frontmatter["due date"] %>`, fm + "2025-07-01"},
+		{"concat", `<% "Week " + tp.date.now("w") + '!' %>`, "Week 25!"},
+		{"numbers", `<% 1 + 2 %>`, "3"},
+		{"trim newline", "a\n<%- tp.file.title -%>\nb", "aTeam Syncb"},
+		{"trim whitespace", "a  \n\n<%_ tp.file.title _%>  \n b", "aTeam Syncb"},
+		{"no tags", "plain {{title}}", "plain {{title}}"},
+	}
+	for _, tt := range tests {
+		got, err := renderTemplater(tt.in, ctx, nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTemplater_Errors(t *testing.T) {
+	ctx := templateContext{Title: "Note", RelPath: "Note.md", Now: time.Now()}
+	tests := []struct {
+		in, want string
+	}{
+		{"ok\n<%* tR += 'x' %>", "line 2: Templater JavaScript (<%*) is not supported"},
+		{`<% tp.system.suggester(["a"], ["a"]) %>`, `unsupported Templater expression "tp.system.suggester([\"a\"], [\"a\"])"`},
+		{`<% app.vault.getFiles() %>`, "unsupported Templater expression"},
+		{`<% tp.file.title.toUpperCase() %>`, "unsupported Templater expression"},
+		{"<% `${tp.file.title}` %>", "${...} are not supported"},
+		{`<% tp.date.now("YYYY", "soon") %>`, `invalid tp.date offset "soon"`},
+		{`<% tp.file.title`, "line 1: unterminated <% tag"},
+	}
+	for _, tt := range tests {
+		_, err := renderTemplater(tt.in, ctx, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("renderTemplater(%q) error = %v, want %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestRenderTemplate_ValuesNotReexpanded(t *testing.T) {
+	ctx := templateContext{
+		Title:   "Note",
+		RelPath: "Note.md",
+		Date:    time.Date(2025, 6, 18, 9, 0, 0, 0, time.UTC),
+		Now:     time.Date(2025, 6, 18, 9, 0, 0, 0, time.UTC),
+		Vars:    map[string]string{"x": "{{date:YYYY}}", "y": "{{date}}"},
+	}
+	in := "---\nsummary: \"{{title}}\"\n---\n{{x}} {{y}} <% tp.frontmatter.summary %> <% tp.system.prompt(\"y\") %> {{date:YYYY}} {{title}}\n"
+	got, err := renderTemplate(in, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "---\nsummary: \"Note\"\n---\n{{date:YYYY}} {{date}} {{title}} {{date}} 2025 Note\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTemplatesApply_Templater(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "Meeting.md"),
+		[]byte("---\ntype: meeting\n---\n# <% tp.file.title %> (<% tp.frontmatter.type %>)\nFolder: <% tp.file.folder(true) %>\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "Bad.md"),
+		[]byte("<% tp.user.custom() %>\n"), 0644)
+
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	if err := v.TemplatesApply("Meeting", "Sync", "meetings/Sync.md", nil); err != nil {
+		t.Fatalf("TemplatesApply: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "meetings", "Sync.md"))
+	if want := "---\ntype: meeting\n---\n# Sync (meeting)\nFolder: meetings\n"; string(data) != want {
+		t.Errorf("content = %q, want %q", data, want)
+	}
+
+	err := v.TemplatesApply("Bad", "X", "X.md", nil)
+	if err == nil || !strings.Contains(err.Error(), "tp.user.custom()") {
+		t.Errorf("expected unsupported expression error, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(vaultDir, "X.md")); statErr == nil {
+		t.Error("note should not be created when the template fails")
+	}
+}
+
+func TestDaily_TemplaterTemplate(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "_templates"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "daily-notes.json"),
+		[]byte(`{"folder":"daily","template":"_templates/daily"}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "_templates", "daily.md"),
+		[]byte(`# <% tp.file.title %>
+Prev: [[<% tp.date.now("YYYY-MM-DD", -1, tp.file.title, "YYYY-MM-DD") %>]]
+`), 0644)
+
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	result, err := v.Daily("2025-03-01")
+	if err != nil {
+		t.Fatalf("Daily: %v", err)
+	}
+	if want := "# 2025-03-01\nPrev: [[2025-02-28]]\n"; result.Content != want {
+		t.Errorf("content = %q, want %q", result.Content, want)
+	}
+
+	os.WriteFile(filepath.Join(vaultDir, "_templates", "daily.md"), []byte("<%* await tp.system.prompt() %>"), 0644)
+	if _, err := v.Daily("2025-03-02"); err == nil {
+		t.Error("expected error for Templater JavaScript in daily template")
+	}
+}
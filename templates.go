@@ -37,12 +37,15 @@ func discoverTemplateFolder(vaultDir string) (string, error) {
 	return "", fmt.Errorf("no template folder configured or found")
 }
 
-// templateVarPattern matches {{varname}} and {{varname:format}} patterns.
-var templateVarPattern = regexp.MustCompile(`\{\{(date|time|title)(?::([^}]+))?\}\}`)
+// templateVarPattern matches {{varname}}, {{varname:format}} and, for
+// dates, {{date <expr>}} or {{date <expr>:format}} patterns.
+var templateVarPattern = regexp.MustCompile(`\{\{(date|time|title)(?: +([^:}]+))?(?::([^}]+))?\}\}`)
 
 // substituteTemplateVars replaces known template variables in content.
 // Known variables: {{title}}, {{date}}, {{time}}, {{date:FORMAT}}, {{time:FORMAT}}.
-// Unknown variables (e.g., {{foo}}) are left as-is.
+// {{date <expr>}} shifts the date with a ResolveDate expression relative
+// to now, e.g. {{date tomorrow}} or {{date +7d:YYYY-MM-DD}}.
+// Other placeholders (e.g., {{foo}}) and invalid expressions are left as-is.
 func substituteTemplateVars(content string, title string, now time.Time) string {
 	return templateVarPattern.ReplaceAllStringFunc(content, func(match string) string {
 		sub := templateVarPattern.FindStringSubmatch(match)
@@ -50,21 +53,33 @@ func substituteTemplateVars(content string, title string, now time.Time) string
 			return match
 		}
 		varName := sub[1]
-		varFormat := sub[2]
+		varExpr := strings.TrimSpace(sub[2])
+		varFormat := sub[3]
+
+		now := now
+		if varExpr != "" {
+			if varName != "date" {
+				return match
+			}
+			date, err := ResolveDate(varExpr, now)
+			if err != nil {
+				return match
+			}
+			now = time.Date(date.Year(), date.Month(), date.Day(),
+				now.Hour(), now.Minute(), now.Second(), 0, now.Location())
+		}
 
 		switch varName {
 		case "title":
 			return title
 		case "date":
 			if varFormat != "" {
-				goFmt := MomentToGoFormat(varFormat)
-				return now.Format(goFmt)
+				return FormatMoment(now, varFormat)
 			}
 			return now.Format("2006-01-02")
 		case "time":
 			if varFormat != "" {
-				goFmt := MomentToGoFormat(varFormat)
-				return now.Format(goFmt)
+				return FormatMoment(now, varFormat)
 			}
 			return now.Format("15:04")
 		default:
@@ -73,6 +88,76 @@ func substituteTemplateVars(content string, title string, now time.Time) string
 	})
 }
 
+// templateVarsKey is the template frontmatter key that declares template
+// variables and their defaults. It is dropped from notes created from the
+// template:
+//
+//	variables:
+//	  project: Apollo   # default, overridden by var.project=
+//	  owner:            # required: no default
+//
+// A list (variables: [project, owner]) declares required variables.
+const templateVarsKey = "variables"
+
+// userVarPattern matches {{name}} placeholders for user-supplied variables.
+var userVarPattern = regexp.MustCompile(`\{\{([A-Za-z_][A-Za-z0-9_-]*)\}\}`)
+
+// builtinTemplateVars are filled by substituteTemplateVars and cannot be
+// set as user variables.
+var builtinTemplateVars = map[string]bool{"title": true, "date": true, "time": true}
+
+// templateVariables reads the variables declared in a template's
+// frontmatter. It returns the defaults, the names of all declared
+// variables and the template without its variables block.
+func templateVariables(content string) (map[string]string, []string, string, error) {
+	if !strings.HasPrefix(content, "---") {
+		return nil, nil, content, nil
+	}
+	lines := strings.Split(content, "\n")
+	start, end := -1, -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			break
+		}
+		if start >= 0 {
+			if lines[i] != "" && lines[i][0] != ' ' && lines[i][0] != '\t' && !strings.HasPrefix(lines[i], "-") {
+				break
+			}
+			end = i + 1
+		} else if strings.HasPrefix(lines[i], templateVarsKey+":") {
+			start, end = i, i+1
+		}
+	}
+	if start < 0 {
+		return nil, nil, content, nil
+	}
+
+	cfg, err := parseYAMLConfig(strings.Join(lines[start:end], "\n"))
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("template %s: %v", templateVarsKey, err)
+	}
+	defaults := make(map[string]string)
+	var declared []string
+	switch vars := cfg[templateVarsKey].(type) {
+	case map[string]any:
+		for name, value := range vars {
+			declared = append(declared, name)
+			if s := strings.Join(yamlStringList(value), ", "); s != "" {
+				defaults[name] = s
+			}
+		}
+	default:
+		declared = yamlStringList(vars)
+	}
+	sort.Strings(declared)
+
+	rest := append(append([]string{}, lines[:start]...), lines[end:]...)
+	if len(rest) > 1 && strings.TrimSpace(rest[1]) == "---" {
+		rest = rest[2:] // the variables block was the only property
+	}
+	return defaults, declared, strings.Join(rest, "\n"), nil
+}
+
 // Templates lists available template files in the configured template folder.
 func (v *Vault) Templates() ([]string, error) {
 	v.mu.RLock()
@@ -108,30 +193,43 @@ func (v *Vault) Templates() ([]string, error) {
 	return templates, nil
 }
 
-// TemplatesApply reads a template file, substitutes variables, and creates
-// a new note at the specified path.
-func (v *Vault) TemplatesApply(templateName, noteName, notePath string) error {
-	v.mu.Lock()
-	defer v.mu.Unlock()
-
-	folder, err := discoverTemplateFolder(v.dir)
+// readTemplate returns the content of a template in the vault's template
+// folder; the .md extension is optional.
+func readTemplate(vaultDir, templateName string) (string, error) {
+	folder, err := discoverTemplateFolder(vaultDir)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	// Resolve template file
 	tmplRel := filepath.Join(folder, templateName)
 	if !strings.HasSuffix(tmplRel, ".md") {
 		tmplRel += ".md"
 	}
-	tmplPath, pathErr := safePath(v.dir, tmplRel)
+	tmplPath, pathErr := safePath(vaultDir, tmplRel)
 	if pathErr != nil {
-		return fmt.Errorf("template path: %w", pathErr)
+		return "", fmt.Errorf("template path: %w", pathErr)
+	}
+
+	data, err := os.ReadFile(tmplPath)
+	if err != nil {
+		return "", fmt.Errorf("template %q not found in %s", templateName, folder)
 	}
+	return string(data), nil
+}
+
+// TemplatesApply reads a template file, evaluates its Templater tags and
+// variables, and creates a new note at the specified path. vars fills
+// {{name}} placeholders and tp.system.prompt("name") calls, falling back to
+// the defaults declared in the template's frontmatter (see
+// templateVarsKey). Missing variables and unsupported Templater
+// expressions are errors and no note is created.
+func (v *Vault) TemplatesApply(templateName, noteName, notePath string, vars map[string]string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 
-	tmplData, err := os.ReadFile(tmplPath)
+	tmplData, err := readTemplate(v.dir, templateName)
 	if err != nil {
-		return fmt.Errorf("template %q not found in %s", templateName, folder)
+		return err
 	}
 
 	// Check target doesn't already exist
@@ -144,7 +242,14 @@ func (v *Vault) TemplatesApply(templateName, noteName, notePath string) error {
 	}
 
 	// Substitute variables
-	content := substituteTemplateVars(string(tmplData), noteName, time.Now())
+	now := vaultNow()
+	content, err := renderTemplate(tmplData, templateContext{
+		VaultDir: v.dir, Title: noteName, RelPath: notePath, Date: now, Now: now,
+		Vars: vars,
+	})
+	if err != nil {
+		return fmt.Errorf("template %q: %w", templateName, err)
+	}
 
 	// Ensure parent directories exist
 	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
@@ -158,3 +263,171 @@ func (v *Vault) TemplatesApply(templateName, noteName, notePath string) error {
 	v.registry.register(v.dir, fullPath, contentBytes)
 	return nil
 }
+
+// TemplateInsertOptions controls where TemplatesInsert splices a template.
+type TemplateInsertOptions struct {
+	Heading    string            // insert into this section (see findSection)
+	Line       int               // insert before this 1-based line instead
+	Position   string            // "top" or "bottom" (default) of the section or body
+	Vars       map[string]string // template variables, as for TemplatesApply
+	Timestamps bool
+}
+
+// TemplatesInsert renders a template for an existing note and splices its
+// body into the note: at the top or bottom of a heading's section (top is
+// right below the heading, bottom after its last non-blank line), before a
+// given line, or at the top or bottom of the body. Keys from the template's
+// frontmatter are merged into the note's (see mergeTemplateFrontmatter).
+// When opts.Timestamps is true (or VLT_TIMESTAMPS=1), updated_at is refreshed.
+func (v *Vault) TemplatesInsert(templateName, title string, opts TemplateInsertOptions) error {
+	switch opts.Position {
+	case "", "top", "bottom":
+	default:
+		return fmt.Errorf("invalid position %q (use top or bottom)", opts.Position)
+	}
+	if opts.Heading != "" && opts.Line > 0 {
+		return fmt.Errorf("use either a heading or a line, not both")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	tmplData, err := readTemplate(v.dir, templateName)
+	if err != nil {
+		return err
+	}
+	path, err := resolveNote(v.dir, title)
+	if err != nil {
+		return err
+	}
+	if err := v.registry.guard(v.dir, path); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(v.dir, path)
+
+	now := vaultNow()
+	rendered, err := renderTemplate(tmplData, templateContext{
+		VaultDir: v.dir, Title: strings.TrimSuffix(filepath.Base(path), ".md"), RelPath: relPath,
+		Date: now, Now: now, Vars: opts.Vars,
+	})
+	if err != nil {
+		return fmt.Errorf("template %q: %w", templateName, err)
+	}
+
+	// Split the rendered template into frontmatter and the snippet to insert.
+	tmplYAML, tmplBody, _ := ExtractFrontmatter(rendered)
+	renderedLines := strings.Split(rendered, "\n")
+	snippet := strings.Trim(strings.Join(renderedLines[tmplBody:], "\n"), "\n")
+
+	text := mergeTemplateFrontmatter(string(data), tmplYAML)
+	if snippet != "" {
+		lines := strings.Split(text, "\n")
+		shift := len(lines) - strings.Count(string(data), "\n") - 1 // lines added by the merge
+		_, lo, _ := ExtractFrontmatter(text)
+		hi := len(lines)
+		if opts.Heading != "" {
+			bounds, err := findSection(lines, opts.Heading)
+			if err != nil {
+				return fmt.Errorf("%s in %q", err, title)
+			}
+			lo, hi = bounds.ContentStart, bounds.ContentEnd
+		}
+
+		insert := lo
+		switch {
+		case opts.Line > 0:
+			if opts.Line > len(lines)-shift {
+				return fmt.Errorf("line %d is beyond file length (%d lines); out of range", opts.Line, len(lines)-shift)
+			}
+			insert = opts.Line - 1 + shift
+		case opts.Position != "top":
+			for i := hi - 1; i >= lo; i-- {
+				if strings.TrimSpace(lines[i]) != "" {
+					insert = i + 1
+					break
+				}
+			}
+		}
+		snippetLines := strings.Split(snippet, "\n")
+		lines = append(lines[:insert], append(snippetLines, lines[insert:]...)...)
+		text = strings.Join(lines, "\n")
+	}
+
+	if timestampsEnabled(opts.Timestamps) {
+		text = ensureTimestamps(text, false, now)
+	}
+	textBytes := []byte(text)
+	if err := os.WriteFile(path, textBytes, 0644); err != nil {
+		return err
+	}
+	v.registry.register(v.dir, path, textBytes)
+	return nil
+}
+
+// mergeTemplateFrontmatter adds a template's frontmatter keys to a note.
+// Keys the note lacks are copied as written; list values present in both
+// are merged (note items first); the note's own scalar values are kept.
+func mergeTemplateFrontmatter(text, tmplYAML string) string {
+	if strings.TrimSpace(tmplYAML) == "" {
+		return text
+	}
+	noteYAML, _, hasFM := ExtractFrontmatter(text)
+	if !hasFM {
+		return "---\n" + strings.TrimRight(tmplYAML, "\n") + "\n---\n" + text
+	}
+	noteFields := frontmatterFields(noteYAML)
+
+	var added []string
+	tmplLines := strings.Split(tmplYAML, "\n")
+	for i := 0; i < len(tmplLines); i++ {
+		line := tmplLines[i]
+		key, _, ok := splitYAMLKey(strings.TrimRight(line, " \r"))
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' || line[0] == '-' || !ok {
+			continue
+		}
+		// The key's block: its line plus indented or list continuation lines.
+		end := i + 1
+		for end < len(tmplLines) && tmplLines[end] != "" &&
+			(tmplLines[end][0] == ' ' || tmplLines[end][0] == '\t' || tmplLines[end][0] == '-') {
+			end++
+		}
+		block := tmplLines[i:end]
+		i = end - 1
+
+		noteField, exists := noteFields[key]
+		if !exists {
+			added = append(added, block...)
+			continue
+		}
+		tmplField := frontmatterFields(strings.Join(block, "\n"))[key]
+		if !tmplField.IsList {
+			continue
+		}
+		merged := noteField.Items
+		if !noteField.IsList && noteField.Value != "" {
+			merged = []string{noteField.Value}
+		}
+		for _, item := range tmplField.Items {
+			if !containsFold(merged, item) {
+				merged = append(merged, item)
+			}
+		}
+		text = frontmatterSetList(text, key, merged)
+	}
+
+	if len(added) > 0 {
+		lines := strings.Split(text, "\n")
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				lines = append(lines[:i], append(added, lines[i:]...)...)
+				break
+			}
+		}
+		text = strings.Join(lines, "\n")
+	}
+	return text
+}
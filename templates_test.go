@@ -219,7 +219,7 @@ func TestTemplatesApplyIntegration(t *testing.T) {
 	)
 
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	if err := v.TemplatesApply("Meeting Notes", "Q1 Planning", "meetings/Q1 Planning.md"); err != nil {
+	if err := v.TemplatesApply("Meeting Notes", "Q1 Planning", "meetings/Q1 Planning.md", nil); err != nil {
 		t.Fatalf("TemplatesApply: %v", err)
 	}
 
@@ -274,7 +274,7 @@ func TestTemplatesApplyExistingNote(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "notes", "Existing.md"), []byte("# Existing"), 0644)
 
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	err := v.TemplatesApply("Simple", "Existing", "notes/Existing.md")
+	err := v.TemplatesApply("Simple", "Existing", "notes/Existing.md", nil)
 	if err == nil {
 		t.Fatal("expected error when applying to existing note")
 	}
@@ -295,7 +295,7 @@ func TestTemplatesApplyNotFound(t *testing.T) {
 	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
 
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	err := v.TemplatesApply("Nonexistent", "Test", "test.md")
+	err := v.TemplatesApply("Nonexistent", "Test", "test.md", nil)
 	if err == nil {
 		t.Fatal("expected error for nonexistent template")
 	}
@@ -319,7 +319,7 @@ func TestTemplatesApplyCreatesDirectories(t *testing.T) {
 	os.WriteFile(filepath.Join(tmplDir, "Simple.md"), []byte("# {{title}}"), 0644)
 
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	if err := v.TemplatesApply("Simple", "Deep Note", "deeply/nested/dir/Deep Note.md"); err != nil {
+	if err := v.TemplatesApply("Simple", "Deep Note", "deeply/nested/dir/Deep Note.md", nil); err != nil {
 		t.Fatalf("TemplatesApply failed: %v", err)
 	}
 
@@ -352,7 +352,7 @@ func TestTemplatesApplyNoFolderError(t *testing.T) {
 
 	// No config, no templates/ folder
 	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
-	err := v.TemplatesApply("Something", "Test", "test.md")
+	err := v.TemplatesApply("Something", "Test", "test.md", nil)
 	if err == nil {
 		t.Fatal("expected error when no template folder configured or found")
 	}
@@ -360,3 +360,169 @@ func TestTemplatesApplyNoFolderError(t *testing.T) {
 		t.Errorf("error = %q, want to contain %q", err.Error(), "no template folder configured or found")
 	}
 }
+
+func TestSubstituteTemplateVars_DateExpressions(t *testing.T) {
+	now := time.Date(2025, 6, 18, 9, 30, 0, 0, time.UTC) // a Wednesday
+	content := "{{date tomorrow}} | {{date +7d:DD/MM}} | {{date next monday:dddd D}} | {{date someday}} | {{time next monday}}"
+	got := substituteTemplateVars(content, "T", now)
+	want := "2025-06-19 | 25/06 | Monday 23 | {{date someday}} | {{time next monday}}"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestTemplatesApply_Variables(t *testing.T) {
+	vaultDir := t.TempDir()
+	tmplDir := filepath.Join(vaultDir, "templates")
+	os.MkdirAll(tmplDir, 0755)
+	os.WriteFile(filepath.Join(tmplDir, "Project.md"), []byte(`---
+type: project
+variables:
+  project:
+  owner: nobody
+  status: active
+---
+# {{project}}
+Owner: {{owner}} / Status: <% tp.system.prompt("status") %>
+Lead: <% tp.system.prompt("lead", "tbd") %>
+`), 0644)
+	os.WriteFile(filepath.Join(tmplDir, "Only.md"), []byte("---\nvariables: [topic]\n---\n# {{topic}}\n"), 0644)
+	os.WriteFile(filepath.Join(tmplDir, "Undeclared.md"), []byte("# {{title}}\n{{attendees}} {{agenda}}\n"), 0644)
+
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+	if err := v.TemplatesApply("Project", "Apollo", "Apollo.md", map[string]string{"project": "Apollo", "status": "paused"}); err != nil {
+		t.Fatalf("TemplatesApply: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Apollo.md"))
+	want := "---\ntype: project\n---\n# Apollo\nOwner: nobody / Status: paused\nLead: tbd\n"
+	if string(data) != want {
+		t.Errorf("content = %q, want %q", data, want)
+	}
+
+	if err := v.TemplatesApply("Only", "T", "T.md", map[string]string{"topic": "Rust"}); err != nil {
+		t.Fatalf("TemplatesApply(Only): %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "T.md"))
+	if string(data) != "# Rust\n" {
+		t.Errorf("variables-only frontmatter should be dropped, got %q", data)
+	}
+
+	err := v.TemplatesApply("Project", "X", "X.md", nil)
+	if err == nil || err.Error() != `template "Project": missing template variables: project` {
+		t.Errorf("err = %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(vaultDir, "X.md")); statErr == nil {
+		t.Error("X.md should not be created with missing variables")
+	}
+
+	// Undeclared placeholders are not required: they may be literal braces.
+	if err := v.TemplatesApply("Undeclared", "Y", "Y.md", map[string]string{"agenda": "-"}); err != nil {
+		t.Fatalf("TemplatesApply(Undeclared): %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "Y.md"))
+	if string(data) != "# Y\n{{attendees}} -\n" {
+		t.Errorf("undeclared placeholders = %q, want {{attendees}} kept", data)
+	}
+
+	// Built-in names cannot be shadowed.
+	err = v.TemplatesApply("Only", "Z", "Z.md", map[string]string{"topic": "x", "title": "Other"})
+	if err == nil || !strings.Contains(err.Error(), `"title" is reserved`) {
+		t.Errorf("err = %v, want reserved title", err)
+	}
+}
+
+func TestTemplatesInsert(t *testing.T) {
+	vaultDir := t.TempDir()
+	tmplDir := filepath.Join(vaultDir, "templates")
+	os.MkdirAll(tmplDir, 0755)
+	os.WriteFile(filepath.Join(tmplDir, "Decision.md"),
+		[]byte("---\ntags: [decision, project]\nstatus: decided\nreviewers:\n  - ana\n---\n\n### Decision: {{topic}}\n- Owner: <% tp.file.title %>\n\n"), 0644)
+	os.WriteFile(filepath.Join(tmplDir, "Line.md"), []byte("> note\n"), 0644)
+
+	note := "---\ntags: [project, apollo]\nstatus: open\n---\n# Apollo\n\nIntro.\n\n## Decisions\n\n- Old decision\n\n## Notes\n\nText.\n"
+	tests := []struct {
+		name string
+		tmpl string
+		opts TemplateInsertOptions
+		want string
+	}{
+		{
+			name: "section bottom",
+			tmpl: "Decision",
+			opts: TemplateInsertOptions{Heading: "Decisions"},
+			want: "---\ntags: [project, apollo, decision]\nstatus: open\nreviewers:\n  - ana\n---\n# Apollo\n\nIntro.\n\n## Decisions\n\n- Old decision\n### Decision: DB\n- Owner: Apollo\n\n## Notes\n\nText.\n",
+		},
+		{
+			name: "section top",
+			tmpl: "Line",
+			opts: TemplateInsertOptions{Heading: "## Notes", Position: "top"},
+			want: strings.Replace(note, "## Notes\n", "## Notes\n> note\n", 1),
+		},
+		{
+			name: "body bottom",
+			tmpl: "Line",
+			want: note[:len(note)-1] + "\n> note\n",
+		},
+		{
+			name: "body top",
+			tmpl: "Line",
+			opts: TemplateInsertOptions{Position: "top"},
+			want: strings.Replace(note, "---\n# Apollo", "---\n> note\n# Apollo", 1),
+		},
+		{
+			name: "line",
+			tmpl: "Line",
+			opts: TemplateInsertOptions{Line: 7},
+			want: strings.Replace(note, "Intro.", "> note\nIntro.", 1),
+		},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(vaultDir, "Apollo.md")
+		os.WriteFile(path, []byte(note), 0644)
+		v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+		tt.opts.Vars = map[string]string{"topic": "DB"}
+		if err := v.TemplatesInsert(tt.tmpl, "Apollo", tt.opts); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, data, tt.want)
+		}
+	}
+}
+
+func TestTemplatesInsert_Errors(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "Snippet.md"), []byte("---\nvariables: [item]\n---\n- {{item}}\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("# Note\n"), 0644)
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	tests := []struct {
+		opts TemplateInsertOptions
+		want string
+	}{
+		{TemplateInsertOptions{Position: "middle", Vars: map[string]string{"item": "x"}}, `invalid position "middle"`},
+		{TemplateInsertOptions{Heading: "Missing", Vars: map[string]string{"item": "x"}}, `heading "Missing" not found`},
+		{TemplateInsertOptions{Line: 9, Vars: map[string]string{"item": "x"}}, "line 9 is beyond file length"},
+		{TemplateInsertOptions{}, "missing template variables: item"},
+	}
+	for _, tt := range tests {
+		err := v.TemplatesInsert("Snippet", "Note", tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("TemplatesInsert(%+v) error = %v, want %q", tt.opts, err, tt.want)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Note.md"))
+	if string(data) != "# Note\n" {
+		t.Errorf("note modified by failed inserts: %q", data)
+	}
+}
+
+func TestMergeTemplateFrontmatter_NoteWithoutFrontmatter(t *testing.T) {
+	got := mergeTemplateFrontmatter("# Note\n", "type: meeting\ntags: [a]")
+	if want := "---\ntype: meeting\ntags: [a]\n---\n# Note\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,402 @@
+package vlt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trashFolder is the vault-local trash, shared with Obsidian's "Move to
+// Obsidian trash" option.
+const trashFolder = ".trash"
+
+// trashManifestName is the sidecar file inside .trash/ recording where each
+// trashed file came from and when it was deleted.
+const trashManifestName = ".vlt-trash.json"
+
+// TrashEntry describes a file in the vault's .trash/ folder.
+type TrashEntry struct {
+	Path     string    `json:"path"`     // relative to .trash/
+	Original string    `json:"original"` // vault-relative path before deletion
+	Deleted  time.Time `json:"deleted"`
+	Size     int64     `json:"size"`
+}
+
+// trashRecord is a manifest entry, keyed by the path relative to .trash/.
+type trashRecord struct {
+	Original string    `json:"original"`
+	Deleted  time.Time `json:"deleted"`
+}
+
+// loadTrashOption reads Obsidian's "Deleted files" setting (trashOption in
+// .obsidian/app.json): "system", "local" or "none". Returns "local" when
+// unset so deletions stay recoverable with trash:restore.
+func loadTrashOption(vaultDir string) string {
+	data, err := os.ReadFile(filepath.Join(vaultDir, ".obsidian", "app.json"))
+	if err != nil {
+		return "local"
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "local"
+	}
+	switch opt, _ := raw["trashOption"].(string); opt {
+	case "system", "none":
+		return opt
+	default:
+		return "local"
+	}
+}
+
+// loadTrashManifest reads .trash/.vlt-trash.json. A missing manifest is an
+// empty one.
+func loadTrashManifest(vaultDir string) (map[string]trashRecord, error) {
+	m := make(map[string]trashRecord)
+	data, err := os.ReadFile(filepath.Join(vaultDir, trashFolder, trashManifestName))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("cannot parse trash manifest: %w", err)
+	}
+	return m, nil
+}
+
+// saveTrashManifest writes the manifest, dropping records whose files are no
+// longer in the trash. The manifest is removed once it is empty.
+func saveTrashManifest(vaultDir string, m map[string]trashRecord) error {
+	dir := filepath.Join(vaultDir, trashFolder)
+	for rel := range m {
+		if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+			delete(m, rel)
+		}
+	}
+
+	path := filepath.Join(dir, trashManifestName)
+	if len(m) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// uniqueName returns name, or "name 2.ext", "name 3.ext", ... if a file with
+// that name already exists in dir.
+func uniqueName(dir, name string) string {
+	if _, err := os.Lstat(filepath.Join(dir, name)); os.IsNotExist(err) {
+		return name
+	}
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s %d%s", stem, i, ext)
+		if _, err := os.Lstat(filepath.Join(dir, candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// discardFile removes a file according to Obsidian's trashOption setting and
+// returns where it went: a vault-relative .trash/ path, "system trash", or ""
+// when the file was deleted permanently. If the system trash cannot be used
+// (unsupported platform, different filesystem), the vault trash is used.
+func discardFile(vaultDir, fullPath string) (string, error) {
+	switch loadTrashOption(vaultDir) {
+	case "none":
+		if err := os.Remove(fullPath); err != nil {
+			return "", err
+		}
+		return "", nil
+	case "system":
+		if err := moveToSystemTrash(fullPath); err == nil {
+			return "system trash", nil
+		}
+	}
+	return moveToTrash(vaultDir, fullPath)
+}
+
+// moveToTrash moves a file into the vault's .trash/ folder, keeping its
+// vault-relative path and renaming on collision, and records the original
+// location and deletion time in the trash manifest. Returns the
+// vault-relative trash path.
+func moveToTrash(vaultDir, fullPath string) (string, error) {
+	relPath, err := filepath.Rel(vaultDir, fullPath)
+	if err != nil {
+		return "", err
+	}
+
+	trashDir := filepath.Join(vaultDir, trashFolder)
+	destDir := filepath.Join(trashDir, filepath.Dir(relPath))
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", err
+	}
+	name := uniqueName(destDir, filepath.Base(relPath))
+	trashPath := filepath.Join(destDir, name)
+
+	manifest, err := loadTrashManifest(vaultDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(fullPath, trashPath); err != nil {
+		return "", err
+	}
+
+	trashRel, _ := filepath.Rel(trashDir, trashPath)
+	manifest[filepath.ToSlash(trashRel)] = trashRecord{
+		Original: filepath.ToSlash(relPath),
+		Deleted:  time.Now().UTC().Truncate(time.Second),
+	}
+	if err := saveTrashManifest(vaultDir, manifest); err != nil {
+		return "", fmt.Errorf("trashed file but failed to update manifest: %w", err)
+	}
+	return filepath.Join(trashFolder, trashRel), nil
+}
+
+// moveToSystemTrash moves a file to the operating system's trash: ~/.Trash
+// on macOS and the freedesktop.org trash ($XDG_DATA_HOME/Trash) elsewhere.
+// Windows' Recycle Bin is not supported.
+func moveToSystemTrash(fullPath string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		return fmt.Errorf("system trash is not supported on windows")
+	case "darwin":
+		dir := filepath.Join(home, ".Trash")
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+		return os.Rename(fullPath, filepath.Join(dir, uniqueName(dir, filepath.Base(fullPath))))
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	filesDir := filepath.Join(dataHome, "Trash", "files")
+	infoDir := filepath.Join(dataHome, "Trash", "info")
+	if err := os.MkdirAll(filesDir, 0700); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(infoDir, 0700); err != nil {
+		return err
+	}
+
+	abs, err := filepath.Abs(fullPath)
+	if err != nil {
+		return err
+	}
+	name := uniqueName(filesDir, filepath.Base(abs))
+	infoPath := filepath.Join(infoDir, name+".trashinfo")
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: abs}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+
+	f, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(info)
+	f.Close()
+	if err != nil {
+		os.Remove(infoPath)
+		return err
+	}
+	if err := os.Rename(abs, filepath.Join(filesDir, name)); err != nil {
+		os.Remove(infoPath)
+		return err
+	}
+	return nil
+}
+
+// trashEntries lists every file in .trash/. Files without a manifest record
+// (trashed by Obsidian or an older vlt) report their trash path as the
+// original location and their modification time as the deletion time.
+// Sorted newest first.
+func trashEntries(vaultDir string) ([]TrashEntry, error) {
+	manifest, err := loadTrashManifest(vaultDir)
+	if err != nil {
+		return nil, err
+	}
+
+	trashDir := filepath.Join(vaultDir, trashFolder)
+	var entries []TrashEntry
+	err = filepath.WalkDir(trashDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return nil
+		}
+		if d.IsDir() || path == filepath.Join(trashDir, trashManifestName) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(trashDir, path)
+		rel = filepath.ToSlash(rel)
+		entry := TrashEntry{Path: rel, Original: rel, Deleted: info.ModTime().UTC(), Size: info.Size()}
+		if rec, ok := manifest[rel]; ok {
+			entry.Original = rec.Original
+			entry.Deleted = rec.Deleted
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Deleted.Equal(entries[j].Deleted) {
+			return entries[i].Deleted.After(entries[j].Deleted)
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+// pruneEmptyTrashDirs removes directories left empty under .trash/ after
+// files were restored or purged, working up from dir.
+func pruneEmptyTrashDirs(vaultDir, dir string) {
+	trashDir := filepath.Join(vaultDir, trashFolder)
+	for dir != trashDir && strings.HasPrefix(dir, trashDir+string(filepath.Separator)) {
+		if os.Remove(dir) != nil {
+			return // not empty
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// TrashList returns the files in the vault's .trash/ folder, newest first.
+func (v *Vault) TrashList() ([]TrashEntry, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return trashEntries(v.dir)
+}
+
+// TrashRestore moves a trashed file back into the vault and re-registers its
+// integrity hash. name matches the trash path (with or without the .trash/
+// prefix), the original path, or the original note title; the most recently
+// deleted match wins. The file returns to its original location unless to is
+// given. Returns the restored entry and its new vault-relative path.
+func (v *Vault) TrashRestore(name, to string) (TrashEntry, string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entries, err := trashEntries(v.dir)
+	if err != nil {
+		return TrashEntry{}, "", err
+	}
+
+	key := strings.TrimPrefix(filepath.ToSlash(name), trashFolder+"/")
+	var entry *TrashEntry
+	for i := range entries {
+		e := &entries[i]
+		title := strings.TrimSuffix(filepath.Base(e.Original), ".md")
+		if e.Path == key || e.Original == key || strings.EqualFold(title, name) {
+			entry = e
+			break
+		}
+	}
+	if entry == nil {
+		return TrashEntry{}, "", fmt.Errorf("%q not found in trash", name)
+	}
+
+	dest := to
+	if dest == "" {
+		dest = entry.Original
+	}
+	destPath, err := safePath(v.dir, dest)
+	if err != nil {
+		return TrashEntry{}, "", fmt.Errorf("trash:restore: %w", err)
+	}
+	if _, err := os.Stat(destPath); err == nil {
+		return TrashEntry{}, "", fmt.Errorf("cannot restore: %s already exists (use to= to restore elsewhere)", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return TrashEntry{}, "", err
+	}
+
+	srcPath := filepath.Join(v.dir, trashFolder, filepath.FromSlash(entry.Path))
+	if err := os.Rename(srcPath, destPath); err != nil {
+		return TrashEntry{}, "", err
+	}
+	pruneEmptyTrashDirs(v.dir, filepath.Dir(srcPath))
+
+	manifest, err := loadTrashManifest(v.dir)
+	if err == nil {
+		err = saveTrashManifest(v.dir, manifest)
+	}
+	if err != nil {
+		return *entry, dest, fmt.Errorf("restored file but failed to update manifest: %w", err)
+	}
+
+	if isLinkSource(destPath) {
+		if data, err := os.ReadFile(destPath); err == nil {
+			v.registry.register(v.dir, destPath, data)
+		}
+	}
+
+	rel, _ := filepath.Rel(v.dir, destPath)
+	return *entry, rel, nil
+}
+
+// TrashEmpty permanently deletes trashed files deleted at least olderThan
+// ago; zero empties the whole trash. Returns the purged entries.
+func (v *Vault) TrashEmpty(olderThan time.Duration) ([]TrashEntry, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entries, err := trashEntries(v.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var purged []TrashEntry
+	for _, e := range entries {
+		if olderThan > 0 && e.Deleted.After(cutoff) {
+			continue
+		}
+		path := filepath.Join(v.dir, trashFolder, filepath.FromSlash(e.Path))
+		if err := os.Remove(path); err != nil {
+			return purged, err
+		}
+		pruneEmptyTrashDirs(v.dir, filepath.Dir(path))
+		purged = append(purged, e)
+	}
+
+	if len(purged) > 0 {
+		manifest, err := loadTrashManifest(v.dir)
+		if err == nil {
+			err = saveTrashManifest(v.dir, manifest)
+		}
+		if err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,191 @@
+package vlt
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestLoadTrashOption(t *testing.T) {
+	vaultDir := t.TempDir()
+	if got := loadTrashOption(vaultDir); got != "local" {
+		t.Errorf("no app.json: got %q, want local", got)
+	}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	for _, opt := range []string{"system", "none", "local"} {
+		os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte(`{"trashOption": "`+opt+`"}`), 0644)
+		if got := loadTrashOption(vaultDir); got != opt {
+			t.Errorf("trashOption %q: got %q", opt, got)
+		}
+	}
+}
+
+func TestDelete_KeepsPathAndHandlesCollisions(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Draft.md"), []byte("first"), 0644)
+
+	res, err := v.Delete("", "projects/Draft.md", DeleteOptions{})
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if res.Dest != filepath.Join(".trash", "projects", "Draft.md") {
+		t.Errorf("Dest = %q", res.Dest)
+	}
+
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Draft.md"), []byte("second"), 0644)
+	if _, err := v.Delete("", "projects/Draft.md", DeleteOptions{}); err != nil {
+		t.Fatalf("second delete: %v", err)
+	}
+
+	first, _ := os.ReadFile(filepath.Join(vaultDir, ".trash", "projects", "Draft.md"))
+	second, _ := os.ReadFile(filepath.Join(vaultDir, ".trash", "projects", "Draft 2.md"))
+	if string(first) != "first" || string(second) != "second" {
+		t.Errorf("collision not handled: first=%q second=%q", first, second)
+	}
+
+	entries, err := v.TrashList()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	for _, e := range entries {
+		if e.Original != "projects/Draft.md" {
+			t.Errorf("entry %s: original = %q, want projects/Draft.md", e.Path, e.Original)
+		}
+	}
+}
+
+func TestDelete_TrashOptionNone(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte(`{"trashOption": "none"}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("x"), 0644)
+
+	res, err := v.Delete("Note", "", DeleteOptions{})
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if res.Dest != "" {
+		t.Errorf("Dest = %q, want permanent delete", res.Dest)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, ".trash")); !os.IsNotExist(err) {
+		t.Error(".trash should not be created when trashOption is none")
+	}
+}
+
+func TestDelete_TrashOptionSystem(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("system trash not supported on windows")
+	}
+	vaultDir := t.TempDir()
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("HOME", dataHome)
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte(`{"trashOption": "system"}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("x"), 0644)
+
+	res, err := v.Delete("Note", "", DeleteOptions{})
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if res.Dest != "system trash" {
+		t.Errorf("Dest = %q, want system trash", res.Dest)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "Note.md")); !os.IsNotExist(err) {
+		t.Error("note should be gone from the vault")
+	}
+}
+
+func TestTrashRestore(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Plan.md"), []byte("# Plan\n"), 0644)
+	if _, err := v.Delete("Plan", "", DeleteOptions{}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	entry, restored, err := v.TrashRestore("Plan", "")
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if entry.Path != "projects/Plan.md" || restored != filepath.Join("projects", "Plan.md") {
+		t.Errorf("restore = %+v -> %q", entry, restored)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "projects", "Plan.md")); err != nil {
+		t.Errorf("note not restored: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, ".trash", "projects")); !os.IsNotExist(err) {
+		t.Error("empty trash folder should be pruned")
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, ".trash", trashManifestName)); !os.IsNotExist(err) {
+		t.Error("empty manifest should be removed")
+	}
+	if got := v.registry.verify(v.dir, filepath.Join(vaultDir, "projects", "Plan.md"), []byte("# Plan\n")); got != IntegrityOK {
+		t.Errorf("restored note integrity = %v, want OK", got)
+	}
+
+	// Restoring over an existing file is refused; to= redirects.
+	v.Delete("Plan", "", DeleteOptions{})
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Plan.md"), []byte("new"), 0644)
+	if _, _, err := v.TrashRestore(".trash/projects/Plan.md", ""); err == nil {
+		t.Error("expected error restoring over an existing file")
+	}
+	if _, restored, err := v.TrashRestore("projects/Plan.md", "archive/Plan.md"); err != nil || restored != filepath.Join("archive", "Plan.md") {
+		t.Errorf("restore to=: %q, %v", restored, err)
+	}
+
+	if _, _, err := v.TrashRestore("Nope", ""); err == nil {
+		t.Error("expected error for missing trash entry")
+	}
+}
+
+func TestTrashEmpty(t *testing.T) {
+	vaultDir := t.TempDir()
+	v := &Vault{dir: vaultDir, registry: openRegistry(vaultDir)}
+
+	os.WriteFile(filepath.Join(vaultDir, "Old.md"), []byte("old"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "New.md"), []byte("new"), 0644)
+	v.Delete("Old", "", DeleteOptions{})
+	v.Delete("New", "", DeleteOptions{})
+
+	// Backdate Old's deletion in the manifest.
+	manifest, _ := loadTrashManifest(vaultDir)
+	rec := manifest["Old.md"]
+	rec.Deleted = time.Now().Add(-40 * 24 * time.Hour)
+	manifest["Old.md"] = rec
+	saveTrashManifest(vaultDir, manifest)
+
+	purged, err := v.TrashEmpty(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("empty: %v", err)
+	}
+	if len(purged) != 1 || purged[0].Path != "Old.md" {
+		t.Errorf("purged = %+v, want Old.md only", purged)
+	}
+
+	purged, err = v.TrashEmpty(0)
+	if err != nil {
+		t.Fatalf("empty all: %v", err)
+	}
+	if len(purged) != 1 || purged[0].Path != "New.md" {
+		t.Errorf("purged = %+v, want New.md", purged)
+	}
+	if entries, _ := v.TrashList(); len(entries) != 0 {
+		t.Errorf("trash not empty: %+v", entries)
+	}
+}
@@ -191,6 +191,8 @@ func skipHiddenDir(path string, d os.DirEntry, walkRoot string) bool {
 
 // resolveNote finds a note by title within the vault.
 // First pass: exact filename match (<title>.md).
+// Attachment pass: titles with an extension (e.g., "diagram.png") resolve to
+// the file itself, honouring the configured attachment folder.
 // Second pass (if needed): checks frontmatter aliases.
 // Skips hidden dirs and .trash.
 func resolveNote(vaultDir, title string) (string, error) {
@@ -216,6 +218,13 @@ func resolveNote(vaultDir, title string) (string, error) {
 		return found, nil
 	}
 
+	// Attachment pass: embeds like ![[diagram.png]] name the file directly.
+	if hasFileExtension(title) {
+		if path, ok := resolveAttachment(vaultDir, title); ok {
+			return path, nil
+		}
+	}
+
 	// Second pass: check frontmatter aliases
 	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -54,21 +54,151 @@ func ParseWikilinks(text string) []Wikilink {
 
 // ReplaceWikilinks replaces all wikilinks and embeds referencing oldTitle
 // with newTitle, preserving the !prefix, #heading, and |display text.
-// Case-insensitive to match Obsidian's link resolution behavior.
+// Case-insensitive to match Obsidian's link resolution behavior. Links inside
+// inert zones (code blocks, comments, math) are left alone, matching
+// ParseWikilinks and FindBacklinks.
 func ReplaceWikilinks(text, oldTitle, newTitle string) string {
-	pattern := regexp.MustCompile(
-		`(?i)(!?)\[\[` + regexp.QuoteMeta(oldTitle) +
+	return replaceWikilinks(text, oldTitle, func(m []string) string {
+		return m[1] + "[[" + newTitle + m[3] + m[4] + "]]"
+	})
+}
+
+// UnlinkWikilinks replaces all wikilinks and embeds referencing title with
+// plain text: the |display text when present, otherwise the title as written
+// in the link. Case-insensitive and inert-aware, like ReplaceWikilinks.
+func UnlinkWikilinks(text, title string) string {
+	return replaceWikilinks(text, title, func(m []string) string {
+		if m[4] != "" {
+			return m[4][1:]
+		}
+		return m[2]
+	})
+}
+
+// replaceWikilinks replaces every wikilink to title outside inert zones with
+// the result of repl, which receives the wikilinkPattern submatches.
+func replaceWikilinks(text, title string, repl func(m []string) string) string {
+	pattern := wikilinkPattern(title)
+	// Masking keeps byte offsets, so match positions in the masked text
+	// apply to the original.
+	locs := pattern.FindAllStringSubmatchIndex(MaskInertContent(text), -1)
+	if len(locs) == 0 {
+		return text
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range locs {
+		m := make([]string, len(loc)/2)
+		for i := range m {
+			if loc[2*i] >= 0 {
+				m[i] = text[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		sb.WriteString(text[last:loc[0]])
+		sb.WriteString(repl(m))
+		last = loc[1]
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// wikilinkPattern matches wikilinks and embeds referencing title. Groups:
+// 1 the ! prefix, 2 the title as written, 3 the #heading, 4 the |display.
+func wikilinkPattern(title string) *regexp.Regexp {
+	return regexp.MustCompile(
+		`(?i)(!?)\[\[(` + regexp.QuoteMeta(title) + `)` +
 			`((?:#[^\]|]*)?)` +
 			`((?:\|[^\]]*)?)` +
 			`\]\]`)
-	return pattern.ReplaceAllString(text, `${1}[[`+newTitle+`${2}${3}]]`)
 }
 
 // updateVaultLinks scans all .md files in vaultDir and replaces wikilinks
 // from oldTitle to newTitle. Returns the number of files modified.
 // If reg is non-nil, updated files are registered for integrity tracking.
 func updateVaultLinks(vaultDir, oldTitle, newTitle string, reg *Registry) (int, error) {
-	modified := 0
+	edits, err := vaultLinkEdits(vaultDir, oldTitle, newTitle)
+	if err != nil {
+		return 0, err
+	}
+	return applyEdits(vaultDir, edits, reg)
+}
+
+// vaultLinkEdits plans the edits of updateVaultLinks without writing.
+func vaultLinkEdits(vaultDir, oldTitle, newTitle string) ([]fileEdit, error) {
+	return noteRewriteEdits(vaultDir, func(text string) string {
+		return ReplaceWikilinks(text, oldTitle, newTitle)
+	})
+}
+
+// unlinkVaultLinks scans all .md files in vaultDir and replaces wikilinks to
+// title with plain text (see UnlinkWikilinks). Returns the number of files
+// modified. If reg is non-nil, updated files are registered for integrity
+// tracking.
+func unlinkVaultLinks(vaultDir, title string, reg *Registry) (int, error) {
+	edits, err := unlinkEdits(vaultDir, title)
+	if err != nil {
+		return 0, err
+	}
+	return applyEdits(vaultDir, edits, reg)
+}
+
+// unlinkEdits plans the edits of unlinkVaultLinks without writing.
+func unlinkEdits(vaultDir, title string) ([]fileEdit, error) {
+	return noteRewriteEdits(vaultDir, func(text string) string {
+		return UnlinkWikilinks(text, title)
+	})
+}
+
+// unlinkVaultCanvasLinks unlinks references to a deleted note from every
+// canvas (see unlinkCanvas). Returns the number of canvases modified.
+func unlinkVaultCanvasLinks(vaultDir, relPath string, titles []string, reg *Registry) (int, error) {
+	edits, err := canvasUnlinkEdits(vaultDir, relPath, titles)
+	if err != nil {
+		return 0, err
+	}
+	return applyEdits(vaultDir, edits, reg)
+}
+
+// canvasUnlinkEdits plans the edits of unlinkVaultCanvasLinks without
+// writing.
+func canvasUnlinkEdits(vaultDir, relPath string, titles []string) ([]fileEdit, error) {
+	return canvasRewriteEdits(vaultDir, func(c *Canvas) bool {
+		return unlinkCanvas(c, relPath, titles)
+	})
+}
+
+// fileEdit is a planned rewrite of one vault file.
+type fileEdit struct {
+	path    string
+	content []byte
+}
+
+// applyEdits writes planned edits and registers them. Every file is guarded
+// before the first write, so under the block policy one mismatched file
+// fails the batch without changing anything. Returns the number of files
+// written.
+func applyEdits(vaultDir string, edits []fileEdit, reg *Registry) (int, error) {
+	for _, e := range edits {
+		if err := reg.guard(vaultDir, e.path); err != nil {
+			return 0, err
+		}
+	}
+	for i, e := range edits {
+		if err := os.WriteFile(e.path, e.content, 0644); err != nil {
+			return i, fmt.Errorf("failed to update %s: %w", e.path, err)
+		}
+		if reg != nil {
+			reg.register(vaultDir, e.path, e.content)
+		}
+	}
+	return len(edits), nil
+}
+
+// noteRewriteEdits applies rewrite to every .md file in vaultDir and returns
+// an edit for each file it changed.
+func noteRewriteEdits(vaultDir string, rewrite func(string) string) ([]fileEdit, error) {
+	var edits []fileEdit
 
 	err := filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -89,21 +219,13 @@ func updateVaultLinks(vaultDir, oldTitle, newTitle string, reg *Registry) (int,
 		}
 
 		text := string(data)
-		updated := ReplaceWikilinks(text, oldTitle, newTitle)
-		if updated != text {
-			updatedBytes := []byte(updated)
-			if err := os.WriteFile(path, updatedBytes, 0644); err != nil {
-				return fmt.Errorf("failed to update %s: %w", path, err)
-			}
-			if reg != nil {
-				reg.register(vaultDir, path, updatedBytes)
-			}
-			modified++
+		if updated := rewrite(text); updated != text {
+			edits = append(edits, fileEdit{path, []byte(updated)})
 		}
 		return nil
 	})
 
-	return modified, err
+	return edits, err
 }
 
 // mdLinkPattern matches markdown-style links to .md files: [text](path.md) or [text](path.md#heading)
@@ -111,11 +233,27 @@ var mdLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\(([^)]+\.md(?:#[^)]*)?)\)`)
 
 // updateVaultMdLinks scans all .md files in the vault and updates
 // markdown-style [text](path.md) links when a file is moved/renamed.
+// When the moved file is an attachment, links and images with any extension
+// (e.g., ![alt](assets/diagram.png)) are considered.
 // oldRelPath and newRelPath are vault-relative paths.
 // Returns the number of files modified.
 // If reg is non-nil, updated files are registered for integrity tracking.
 func updateVaultMdLinks(vaultDir, oldRelPath, newRelPath string, reg *Registry) (int, error) {
-	modified := 0
+	edits, err := mdLinkEdits(vaultDir, oldRelPath, newRelPath)
+	if err != nil {
+		return 0, err
+	}
+	return applyEdits(vaultDir, edits, reg)
+}
+
+// mdLinkEdits plans the edits of updateVaultMdLinks without writing.
+func mdLinkEdits(vaultDir, oldRelPath, newRelPath string) ([]fileEdit, error) {
+	linkPattern := mdLinkPattern
+	if !strings.HasSuffix(oldRelPath, ".md") {
+		linkPattern = mdAttachmentLinkPattern
+	}
+
+	var edits []fileEdit
 
 	err := filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -138,8 +276,8 @@ func updateVaultMdLinks(vaultDir, oldRelPath, newRelPath string, reg *Registry)
 		text := string(data)
 		fileDir, _ := filepath.Rel(vaultDir, filepath.Dir(path))
 
-		updated := mdLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
-			sub := mdLinkPattern.FindStringSubmatch(match)
+		updated := linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+			sub := linkPattern.FindStringSubmatch(match)
 			if len(sub) < 3 {
 				return match
 			}
@@ -179,25 +317,20 @@ func updateVaultMdLinks(vaultDir, oldRelPath, newRelPath string, reg *Registry)
 		})
 
 		if updated != text {
-			updatedBytes := []byte(updated)
-			if err := os.WriteFile(path, updatedBytes, 0644); err != nil {
-				return fmt.Errorf("failed to update %s: %w", path, err)
-			}
-			if reg != nil {
-				reg.register(vaultDir, path, updatedBytes)
-			}
-			modified++
+			edits = append(edits, fileEdit{path, []byte(updated)})
 		}
 		return nil
 	})
 
-	return modified, err
+	return edits, err
 }
 
 // FindBacklinks returns relative paths of notes that contain wikilinks or
 // embeds referencing the given title. Case-insensitive.
 // Content inside inert zones (fenced code blocks, etc.) is masked before
 // matching so that references inside code blocks are ignored.
+// Canvases referencing the title (file nodes or wikilinks in text nodes) are
+// included as well.
 func FindBacklinks(vaultDir, title string) ([]string, error) {
 	pattern := regexp.MustCompile(
 		`(?i)!?\[\[` + regexp.QuoteMeta(title) +
@@ -215,7 +348,7 @@ func FindBacklinks(vaultDir, title string) ([]string, error) {
 			return filepath.SkipDir
 		}
 		name := d.Name()
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
+		if d.IsDir() || !isLinkSource(name) {
 			return nil
 		}
 
@@ -224,6 +357,17 @@ func FindBacklinks(vaultDir, title string) ([]string, error) {
 			return nil
 		}
 
+		if isCanvasFile(name) {
+			for _, link := range noteLinks(name, data) {
+				if strings.EqualFold(link.Title, title) {
+					relPath, _ := filepath.Rel(vaultDir, path)
+					results = append(results, relPath)
+					break
+				}
+			}
+			return nil
+		}
+
 		masked := MaskInertContent(string(data))
 		if pattern.MatchString(masked) {
 			relPath, _ := filepath.Rel(vaultDir, path)
@@ -401,6 +401,29 @@ func TestReplaceWikilinks_BlockReference(t *testing.T) {
 	}
 }
 
+func TestUnlinkWikilinks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain link", "See [[Old Note]] here.", "See Old Note here."},
+		{"keeps written case", "See [[old note]] here.", "See old note here."},
+		{"display text", "The [[Old Note|alias]] is useful.", "The alias is useful."},
+		{"heading dropped", "See [[Old Note#Section]].", "See Old Note."},
+		{"embed", "![[Old Note]]", "Old Note"},
+		{"other links untouched", "[[Old Note 2]] and [[Other]]", "[[Old Note 2]] and [[Other]]"},
+		{"code untouched", "[[Old Note]]\n```\n[[Old Note]]\n```\n`[[Old Note]]`", "Old Note\n```\n[[Old Note]]\n```\n`[[Old Note]]`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnlinkWikilinks(tt.text, "Old Note"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFindBacklinks_BlockReference(t *testing.T) {
 	vaultDir := t.TempDir()
 
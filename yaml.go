@@ -0,0 +1,228 @@
+package vlt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// yamlLine is a non-blank, comment-stripped line of a YAML config file.
+type yamlLine struct {
+	num    int // 1-based line number in the file
+	indent int
+	text   string
+}
+
+// parseYAMLConfig parses the small YAML subset used by vlt's config files
+// under .vlt/: nested maps, block lists ("- item"), lists of maps, inline
+// lists ([a, b]), quoted or plain scalars and # comments. Scalars are
+// returned as strings, maps as map[string]any and lists as []any. Anchors,
+// multi-line strings and flow maps are not supported.
+func parseYAMLConfig(text string) (map[string]any, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimRight(stripYAMLComment(raw), " \t\r")
+		trimmed := strings.TrimLeft(raw, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		lines = append(lines, yamlLine{num: i + 1, indent: len(raw) - len(trimmed), text: trimmed})
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	p := &yamlParser{lines: lines}
+	node, err := p.parseNode(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[p.pos].num)
+	}
+	m, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("line %d: expected a map at the top level", lines[0].num)
+	}
+	return m, nil
+}
+
+// stripYAMLComment removes a trailing # comment that is outside quotes and
+// starts the line or follows whitespace.
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseNode parses the map or list starting at the current line, whose
+// entries sit at the given indentation.
+func (p *yamlParser) parseNode(indent int) (any, error) {
+	if isYAMLListItem(p.lines[p.pos].text) {
+		return p.parseList(indent)
+	}
+	return p.parseMap(indent)
+}
+
+func (p *yamlParser) parseMap(indent int) (map[string]any, error) {
+	m := make(map[string]any)
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		if isYAMLListItem(line.text) {
+			return nil, fmt.Errorf("line %d: unexpected list item in a map", line.num)
+		}
+		key, value, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+
+		if value != "" {
+			m[key] = parseYAMLScalar(value)
+			continue
+		}
+		switch {
+		case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+			child, err := p.parseNode(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = child
+		case p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLListItem(p.lines[p.pos].text):
+			// Lists may sit at the same indentation as their key.
+			child, err := p.parseList(indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = child
+		default:
+			m[key] = nil
+		}
+	}
+	return m, nil
+}
+
+func (p *yamlParser) parseList(indent int) ([]any, error) {
+	list := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLListItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+
+		switch {
+		case rest == "":
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				child, err := p.parseNode(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, child)
+			} else {
+				list = append(list, nil)
+			}
+		case isYAMLMapEntry(rest):
+			// "- key: value" starts a map whose keys align with "key".
+			childIndent := indent + len(line.text) - len(rest)
+			p.lines[p.pos] = yamlLine{num: line.num, indent: childIndent, text: rest}
+			child, err := p.parseMap(childIndent)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, child)
+		default:
+			list = append(list, parseYAMLScalar(rest))
+			p.pos++
+		}
+	}
+	return list, nil
+}
+
+func isYAMLListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// isYAMLMapEntry reports whether text is "key: value" or "key:" with a plain
+// (unquoted, non-list) key.
+func isYAMLMapEntry(text string) bool {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "\"") || strings.HasPrefix(text, "'") {
+		return false
+	}
+	_, _, ok := splitYAMLKey(text)
+	return ok
+}
+
+// splitYAMLKey splits "key: value" (or "key:") into its key and raw value.
+func splitYAMLKey(text string) (string, string, bool) {
+	if strings.HasSuffix(text, ":") {
+		key := strings.TrimSpace(text[:len(text)-1])
+		return unquoteYAML(key), "", key != ""
+	}
+	i := strings.Index(text, ": ")
+	if i <= 0 {
+		return "", "", false
+	}
+	return unquoteYAML(strings.TrimSpace(text[:i])), strings.TrimSpace(text[i+2:]), true
+}
+
+// parseYAMLScalar parses a plain or quoted scalar, or an inline [a, b] list.
+func parseYAMLScalar(value string) any {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		list := []any{}
+		for _, part := range strings.Split(value[1:len(value)-1], ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				list = append(list, unquoteYAML(part))
+			}
+		}
+		return list
+	}
+	return unquoteYAML(value)
+}
+
+func unquoteYAML(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// yamlStringList converts a config value to a list of strings: lists are
+// flattened to their scalar items and a single scalar becomes a one-item list.
+func yamlStringList(v any) []string {
+	switch val := v.(type) {
+	case string:
+		if val == "" {
+			return nil
+		}
+		return []string{val}
+	case []any:
+		var out []string
+		for _, item := range val {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
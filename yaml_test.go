@@ -0,0 +1,83 @@
+package vlt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAMLConfig(t *testing.T) {
+	text := `# vlt lint config
+tags:
+  case: kebab   # trailing comment
+  allowed-prefixes:
+    - project
+    - "status"
+  required:
+    projects: [project, 'active']
+    meetings:
+    - meeting
+schemas:
+  - folder: decisions
+    required: [status]
+    properties:
+      status:
+        enum: [proposed, accepted]
+  - tag: "#person"
+empty:
+`
+	got, err := parseYAMLConfig(text)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := map[string]any{
+		"tags": map[string]any{
+			"case":             "kebab",
+			"allowed-prefixes": []any{"project", "status"},
+			"required": map[string]any{
+				"projects": []any{"project", "active"},
+				"meetings": []any{"meeting"},
+			},
+		},
+		"schemas": []any{
+			map[string]any{
+				"folder":   "decisions",
+				"required": []any{"status"},
+				"properties": map[string]any{
+					"status": map[string]any{"enum": []any{"proposed", "accepted"}},
+				},
+			},
+			map[string]any{"tag": "#person"},
+		},
+		"empty": nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %#v\nwant %#v", got, want)
+	}
+}
+
+func TestParseYAMLConfig_Errors(t *testing.T) {
+	tests := map[string]string{
+		"bad indentation": "a:\n  b: 1\n c: 2\n",
+		"not a map":       "- a\n- b\n",
+		"missing colon":   "a: 1\njust text\n",
+		"duplicate key":   "a: 1\na: 2\n",
+		"tab indentation": "a:\n\tb: 1\n",
+	}
+	for name, text := range tests {
+		if _, err := parseYAMLConfig(text); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestYAMLStringList(t *testing.T) {
+	if got := yamlStringList("a"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("scalar: got %v", got)
+	}
+	if got := yamlStringList([]any{"a", map[string]any{}, "b"}); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("list: got %v", got)
+	}
+	if got := yamlStringList(nil); got != nil {
+		t.Errorf("nil: got %v", got)
+	}
+}